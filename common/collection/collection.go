@@ -0,0 +1,166 @@
+// Package collection 제네릭 기반의 슬라이스, 맵, 집합 헬퍼
+// go-funk 와 달리 리플렉션을 사용하지 않아 컴파일 시점에 타입이 검사되고 타입 단언이 필요 없다.
+package collection
+
+import (
+	"sort"
+)
+
+// Ordered 대소 비교(<)가 가능한 타입
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// Map 슬라이스의 각 원소를 변환한 새 슬라이스를 반환
+/* Map([]Widget{{I: "a"}, {I: "b"}}, func(w Widget) string { return w.I }) => [a b]
+ */
+func Map[T any, R any](items []T, mapper func(T) R) []R {
+	result := make([]R, len(items))
+	for i, item := range items {
+		result[i] = mapper(item)
+	}
+	return result
+}
+
+// Filter 조건을 만족하는 원소만 담은 새 슬라이스를 반환
+func Filter[T any](items []T, predicate func(T) bool) []T {
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		if predicate(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Find 조건을 만족하는 첫 번째 원소와 존재 여부를 반환
+func Find[T any](items []T, predicate func(T) bool) (T, bool) {
+	for _, item := range items {
+		if predicate(item) {
+			return item, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// FindIndex 조건을 만족하는 첫 번째 원소의 인덱스를 반환(없으면 -1)
+func FindIndex[T any](items []T, predicate func(T) bool) int {
+	for i, item := range items {
+		if predicate(item) {
+			return i
+		}
+	}
+	return -1
+}
+
+// IndexOf 값과 같은 첫 번째 원소의 인덱스를 반환(없으면 -1)
+func IndexOf[T comparable](items []T, value T) int {
+	for i, item := range items {
+		if item == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// Contains 값과 같은 원소가 있는지 확인(정렬되지 않은 슬라이스도 사용 가능)
+func Contains[T comparable](items []T, value T) bool {
+	return IndexOf(items, value) != -1
+}
+
+// Reduce 초기값에서 시작하여 원소를 차례로 누적한 값을 반환
+func Reduce[T any, R any](items []T, reducer func(R, T) R, initial R) R {
+	result := initial
+	for _, item := range items {
+		result = reducer(result, item)
+	}
+	return result
+}
+
+// GroupBy 원소를 키 함수의 결과로 묶은 맵을 반환(그룹 안의 순서는 원래 순서를 유지)
+func GroupBy[T any, K comparable](items []T, key func(T) K) map[K][]T {
+	result := make(map[K][]T)
+	for _, item := range items {
+		k := key(item)
+		result[k] = append(result[k], item)
+	}
+	return result
+}
+
+// Chunk 슬라이스를 size 개씩 나눈 슬라이스 목록을 반환(마지막 묶음은 size 보다 작을 수 있음, size 가 0 이하면 nil)
+/* Chunk([]int{1, 2, 3, 4, 5}, 2) => [[1 2] [3 4] [5]]
+ */
+func Chunk[T any](items []T, size int) [][]T {
+	if size <= 0 {
+		return nil
+	}
+	result := make([][]T, 0, (len(items)+size-1)/size)
+	for start := 0; start < len(items); start += size {
+		end := start + size
+		if end > len(items) {
+			end = len(items)
+		}
+		result = append(result, items[start:end:end])
+	}
+	return result
+}
+
+// Uniq 중복을 제거한 새 슬라이스를 반환(처음 나온 순서를 유지)
+func Uniq[T comparable](items []T) []T {
+	return UniqBy(items, func(item T) T { return item })
+}
+
+// UniqBy 키 함수의 결과가 중복되는 원소를 제거한 새 슬라이스를 반환(처음 나온 원소를 유지)
+func UniqBy[T any, K comparable](items []T, key func(T) K) []T {
+	seen := make(map[K]struct{}, len(items))
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		k := key(item)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		result = append(result, item)
+	}
+	return result
+}
+
+// SortBy 키 함수의 결과로 오름차순 안정 정렬한 새 슬라이스를 반환(원본은 수정하지 않음)
+func SortBy[T any, K Ordered](items []T, key func(T) K) []T {
+	result := make([]T, len(items))
+	copy(result, items)
+	sort.SliceStable(result, func(i, j int) bool {
+		return key(result[i]) < key(result[j])
+	})
+	return result
+}
+
+// Keys 맵의 키 목록을 반환(순서는 보장하지 않음, 정렬이 필요하면 SortedKeys 사용)
+func Keys[K comparable, V any](m map[K]V) []K {
+	result := make([]K, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
+
+// SortedKeys 맵의 키 목록을 오름차순으로 반환
+func SortedKeys[K Ordered, V any](m map[K]V) []K {
+	result := Keys(m)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result
+}
+
+// Values 맵의 값 목록을 반환(순서는 보장하지 않음)
+func Values[K comparable, V any](m map[K]V) []V {
+	result := make([]V, 0, len(m))
+	for _, v := range m {
+		result = append(result, v)
+	}
+	return result
+}
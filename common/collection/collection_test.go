@@ -0,0 +1,179 @@
+package collection
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/thoas/go-funk"
+)
+
+type widget struct {
+	ID    string
+	Group string
+	Order int
+}
+
+var widgets = []widget{
+	{"a", "text", 3},
+	{"b", "chart", 1},
+	{"c", "text", 2},
+	{"d", "chart", 1},
+}
+
+func TestSliceHelpers(t *testing.T) {
+	ids := Map(widgets, func(w widget) string { return w.ID })
+	if !reflect.DeepEqual(ids, []string{"a", "b", "c", "d"}) {
+		t.Errorf("Map: got %v", ids)
+	}
+	texts := Filter(widgets, func(w widget) bool { return w.Group == "text" })
+	if len(texts) != 2 || texts[1].ID != "c" {
+		t.Errorf("Filter: got %v", texts)
+	}
+	if found, ok := Find(widgets, func(w widget) bool { return w.Order == 1 }); !ok || found.ID != "b" {
+		t.Errorf("Find: got %v %v", found, ok)
+	}
+	if _, ok := Find(widgets, func(w widget) bool { return w.Order == 9 }); ok {
+		t.Error("Find: expected no match")
+	}
+	if index := FindIndex(widgets, func(w widget) bool { return w.ID == "c" }); index != 2 {
+		t.Errorf("FindIndex: got %d", index)
+	}
+	if !Contains([]string{"MiB", "B", "GiB"}, "B") || IndexOf([]string{"MiB", "B"}, "KiB") != -1 {
+		t.Error("Contains/IndexOf: unexpected result for unsorted slice")
+	}
+	if sum := Reduce(widgets, func(total int, w widget) int { return total + w.Order }, 0); sum != 7 {
+		t.Errorf("Reduce: got %d", sum)
+	}
+
+	groups := GroupBy(widgets, func(w widget) string { return w.Group })
+	if len(groups["text"]) != 2 || groups["chart"][1].ID != "d" {
+		t.Errorf("GroupBy: got %v", groups)
+	}
+	chunks := Chunk([]int{1, 2, 3, 4, 5}, 2)
+	if !reflect.DeepEqual(chunks, [][]int{{1, 2}, {3, 4}, {5}}) {
+		t.Errorf("Chunk: got %v", chunks)
+	}
+	chunks[0] = append(chunks[0], 9)
+	if chunks[1][0] != 3 {
+		t.Error("Chunk: appending to a chunk must not overwrite the next chunk")
+	}
+	if uniq := Uniq([]int{3, 1, 3, 2, 1}); !reflect.DeepEqual(uniq, []int{3, 1, 2}) {
+		t.Errorf("Uniq: got %v", uniq)
+	}
+	sorted := SortBy(widgets, func(w widget) int { return w.Order })
+	if ids := Map(sorted, func(w widget) string { return w.ID }); !reflect.DeepEqual(ids, []string{"b", "d", "c", "a"}) {
+		t.Errorf("SortBy: got %v", ids)
+	}
+	if widgets[0].ID != "a" {
+		t.Error("SortBy: input was modified")
+	}
+}
+
+func TestMapHelpers(t *testing.T) {
+	m := map[string]int{"b": 2, "a": 1, "c": 3}
+	if keys := SortedKeys(m); !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("SortedKeys: got %v", keys)
+	}
+	values := Values(m)
+	sort.Ints(values)
+	if !reflect.DeepEqual(values, []int{1, 2, 3}) {
+		t.Errorf("Values: got %v", values)
+	}
+}
+
+func TestSet(t *testing.T) {
+	a := NewSet("team-a", "team-b", "team-b")
+	b := NewSet("team-b", "team-c")
+	if a.Len() != 2 || !a.Has("team-a") || a.Has("team-c") {
+		t.Errorf("NewSet: got %v", a)
+	}
+	union := a.Union(b).Items()
+	sort.Strings(union)
+	if !reflect.DeepEqual(union, []string{"team-a", "team-b", "team-c"}) {
+		t.Errorf("Union: got %v", union)
+	}
+	if intersection := a.Intersection(b); intersection.Len() != 1 || !intersection.Has("team-b") {
+		t.Errorf("Intersection: got %v", intersection)
+	}
+	if difference := a.Difference(b); difference.Len() != 1 || !difference.Has("team-a") {
+		t.Errorf("Difference: got %v", difference)
+	}
+	a.Remove("team-a")
+	if a.Has("team-a") {
+		t.Error("Remove: value still exists")
+	}
+}
+
+func benchmarkWidgets() []widget {
+	items := make([]widget, 1000)
+	for i := range items {
+		items[i] = widget{ID: strconv.Itoa(i), Group: strconv.Itoa(i % 10), Order: i % 100}
+	}
+	return items
+}
+
+func BenchmarkMap(b *testing.B) {
+	items := benchmarkWidgets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Map(items, func(w widget) string { return w.ID })
+	}
+}
+
+func BenchmarkFunkMap(b *testing.B) {
+	items := benchmarkWidgets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = funk.Map(items, func(w widget) string { return w.ID }).([]string)
+	}
+}
+
+func BenchmarkFind(b *testing.B) {
+	items := benchmarkWidgets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Find(items, func(w widget) bool { return w.ID == "999" })
+	}
+}
+
+func BenchmarkFunkFind(b *testing.B) {
+	items := benchmarkWidgets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = funk.Find(items, func(w widget) bool { return w.ID == "999" })
+	}
+}
+
+func BenchmarkFilter(b *testing.B) {
+	items := benchmarkWidgets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Filter(items, func(w widget) bool { return w.Order < 50 })
+	}
+}
+
+func BenchmarkFunkFilter(b *testing.B) {
+	items := benchmarkWidgets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = funk.Filter(items, func(w widget) bool { return w.Order < 50 }).([]widget)
+	}
+}
+
+func BenchmarkUniq(b *testing.B) {
+	items := Map(benchmarkWidgets(), func(w widget) int { return w.Order })
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Uniq(items)
+	}
+}
+
+func BenchmarkFunkUniq(b *testing.B) {
+	items := Map(benchmarkWidgets(), func(w widget) int { return w.Order })
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = funk.Uniq(items).([]int)
+	}
+}
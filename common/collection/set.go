@@ -0,0 +1,75 @@
+package collection
+
+// Set 순서가 없는 중복되지 않는 값의 집합
+type Set[T comparable] map[T]struct{}
+
+// NewSet 값 목록으로 집합을 생성
+func NewSet[T comparable](items ...T) Set[T] {
+	set := make(Set[T], len(items))
+	set.Add(items...)
+	return set
+}
+
+// Add 값을 집합에 추가
+func (s Set[T]) Add(items ...T) {
+	for _, item := range items {
+		s[item] = struct{}{}
+	}
+}
+
+// Remove 값을 집합에서 삭제
+func (s Set[T]) Remove(items ...T) {
+	for _, item := range items {
+		delete(s, item)
+	}
+}
+
+// Has 값이 집합에 있는지 확인
+func (s Set[T]) Has(item T) bool {
+	_, ok := s[item]
+	return ok
+}
+
+// Len 집합의 크기
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Items 집합의 값 목록(순서는 보장하지 않음)
+func (s Set[T]) Items() []T {
+	return Keys(s)
+}
+
+// Union 두 집합의 합집합
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	result := make(Set[T], len(s)+len(other))
+	for item := range s {
+		result.Add(item)
+	}
+	for item := range other {
+		result.Add(item)
+	}
+	return result
+}
+
+// Intersection 두 집합의 교집합
+func (s Set[T]) Intersection(other Set[T]) Set[T] {
+	result := make(Set[T])
+	for item := range s {
+		if other.Has(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// Difference 다른 집합에 없는 값만 담은 차집합
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	result := make(Set[T])
+	for item := range s {
+		if !other.Has(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
@@ -1,83 +1,37 @@
 package common
 
 import (
-	"reflect"
-	"sort"
-	"strconv"
-	"strings"
+	"go-practice/common/collection"
 )
 
-// Get 두 개의 인자를 받아 두 번째 인자의 경로에 해당하는 값을 반환
+// Get 첫 번째 인자(데이터)에서 두 번째 인자(경로)에 해당하는 값을 반환하고, 값이 없으면 세 번째 인자(fallback)를 반환
+// 경로 문법은 GetE 참고
 func Get(args ...interface{}) interface{} {
-	if len(args) < 2 {
-		panic("Invalid number of argument. At least 2 arguments are required")
-	}
-	var (
-		path     string
-		mapData  interface{}
-		fallback interface{}
-	)
-
-	if len(args) >= 1 {
-		mapData = args[0]
-	}
-	if len(args) >= 2 {
-		path = args[1].(string)
-	}
+	var fallback interface{}
 	if len(args) >= 3 {
 		fallback = args[2]
 	}
-	if mapData == nil {
+	if len(args) < 2 {
 		return fallback
 	}
-	defer func() interface{} {
-		if r := recover(); r != nil {
-			return fallback
-		}
+	path, ok := args[1].(string)
+	if !ok {
 		return fallback
-	}()
-	data := mapData
-	paths := strings.Split(path, ".")
-	for _, key := range paths {
-		value := reflect.ValueOf(data)
-		dataType := value.Type().Kind()
-		if dataType == reflect.Map {
-			data = data.(map[string]interface{})[key]
-			continue
-		}
-
-		if dataType == reflect.Slice {
-			indx, err := strconv.Atoi(key)
-			if err != nil {
-				return fallback
-			}
-			data = value.Index(indx).Interface()
-			continue
-		}
-
-		if dataType == reflect.Struct {
-			data = value.FieldByName(key).Interface()
-			continue
-		}
-
-		if dataType == reflect.String {
-			indx, err := strconv.Atoi(key)
-			if err != nil {
-				return fallback
-			}
-			data = string(data.(string)[indx])
-			continue
-		}
+	}
+	value, err := GetE(args[0], path)
+	if err != nil {
 		return fallback
 	}
-	return data
+	return value
 }
 
+// Exists 문자열 배열에 대상값이 있는지 확인(정렬되지 않은 배열도 사용 가능)
+// Deprecated: collection.Contains 사용
 func Exists(s []string, target string) bool {
-	i := sort.SearchStrings(s, target)
-	return i < len(s) && s[i] == target
+	return collection.Contains(s, target)
 }
 
+// MergeJSONMaps 맵을 순서대로 얕은 병합(같은 키는 나중 값으로 교체), 중첩된 맵까지 병합하려면 DeepMerge 사용
 func MergeJSONMaps(maps ...map[string]interface{}) (result map[string]interface{}) {
 	result = make(map[string]interface{})
 	for _, m := range maps {
@@ -88,11 +42,8 @@ func MergeJSONMaps(maps ...map[string]interface{}) (result map[string]interface{
 	return result
 }
 
+// IndexOf 문자열 배열에 대상값 인덱스 반환
+// Deprecated: collection.IndexOf 사용
 func IndexOf(arr []string, val string) int {
-	for pos, v := range arr {
-		if v == val {
-			return pos
-		}
-	}
-	return -1
+	return collection.IndexOf(arr, val)
 }
@@ -0,0 +1,285 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ErrTypeMismatch 경로의 중간 값이 맵 또는 배열이 아니어서 하위 값을 수정할 수 없는 경우의 에러
+var ErrTypeMismatch = errors.New("type mismatch")
+
+// Set JSON 문서(map[string]interface{}, []interface{})의 경로에 값을 설정하고 수정된 문서를 반환
+// 경로의 중간 값이 없으면 다음 구간이 숫자인 경우 배열, 그 외에는 맵을 생성한다.
+// 배열의 인덱스가 길이 이상이면 null 로 채워 확장하고, -1 은 배열의 끝에 추가한다.
+// 인자로 받은 문서를 직접 수정하지만 배열이 확장될 수 있으므로 반환된 문서를 사용해야 하며, 경로에 #, #(조건) 은 사용할 수 없다.
+/* Set({"data":{}}, "data.result.0.value", 1) => {"data":{"result":[{"value":1}]}}
+ * Set({"items":[1,2]}, "items.-1", 3)        => {"items":[1,2,3]}
+ */
+func Set(data interface{}, path string, value interface{}) (interface{}, error) {
+	segments, err := parseEditPath(path)
+	if err != nil {
+		return data, err
+	}
+	result, err := setValue(data, segments, value)
+	if err != nil {
+		if pathErr, ok := err.(*PathError); ok {
+			pathErr.Path = path
+		}
+		return data, err
+	}
+	return result, nil
+}
+
+// Delete JSON 문서의 경로에 해당하는 맵의 키 또는 배열의 원소를 삭제하고 수정된 문서를 반환(인자로 받은 문서를 직접 수정)
+func Delete(data interface{}, path string) (interface{}, error) {
+	segments, err := parseEditPath(path)
+	if err != nil {
+		return data, err
+	}
+	if len(segments) == 0 {
+		return data, &PathError{Path: path, Err: ErrInvalidPath}
+	}
+	result, err := deleteValue(data, segments)
+	if err != nil {
+		if pathErr, ok := err.(*PathError); ok {
+			pathErr.Path = path
+		}
+		return data, err
+	}
+	return result, nil
+}
+
+// parseEditPath 수정에 사용하는 경로를 파싱하는 함수(키 구간만 허용)
+func parseEditPath(path string) ([]segment, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, &PathError{Path: path, Segment: path, Err: err}
+	}
+	for _, s := range segments {
+		if s.kind != keySegment {
+			return nil, &PathError{Path: path, Segment: s.raw, Err: fmt.Errorf("%w, wildcard and filter are not supported", ErrInvalidPath)}
+		}
+	}
+	return segments, nil
+}
+
+func setValue(node interface{}, segments []segment, value interface{}) (interface{}, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+	s := segments[0]
+	if node == nil {
+		if _, err := strconv.Atoi(s.key); err == nil {
+			node = []interface{}{}
+		} else {
+			node = map[string]interface{}{}
+		}
+	}
+
+	switch container := node.(type) {
+	case map[string]interface{}:
+		child, err := setValue(container[s.key], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		container[s.key] = child
+		return container, nil
+	case []interface{}:
+		index, err := strconv.Atoi(s.key)
+		if err != nil || index < -1 {
+			return nil, &PathError{Segment: s.raw, Err: fmt.Errorf("%w, invalid array index", ErrInvalidPath)}
+		}
+		if index == -1 {
+			index = len(container)
+		}
+		for len(container) <= index {
+			container = append(container, nil)
+		}
+		child, err := setValue(container[index], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = child
+		return container, nil
+	}
+	return nil, &PathError{Segment: s.raw, Err: ErrTypeMismatch}
+}
+
+func deleteValue(node interface{}, segments []segment) (interface{}, error) {
+	s := segments[0]
+	switch container := node.(type) {
+	case map[string]interface{}:
+		child, ok := container[s.key]
+		if !ok {
+			return nil, &PathError{Segment: s.raw, Err: ErrPathNotFound}
+		}
+		if len(segments) == 1 {
+			delete(container, s.key)
+			return container, nil
+		}
+		child, err := deleteValue(child, segments[1:])
+		if err != nil {
+			return nil, err
+		}
+		container[s.key] = child
+		return container, nil
+	case []interface{}:
+		index, err := strconv.Atoi(s.key)
+		if err != nil || index < 0 || index >= len(container) {
+			return nil, &PathError{Segment: s.raw, Err: ErrPathNotFound}
+		}
+		if len(segments) == 1 {
+			return append(container[:index], container[index+1:]...), nil
+		}
+		child, err := deleteValue(container[index], segments[1:])
+		if err != nil {
+			return nil, err
+		}
+		container[index] = child
+		return container, nil
+	}
+	return nil, &PathError{Segment: s.raw, Err: ErrPathNotFound}
+}
+
+// ArrayMergeStrategy 깊은 병합 시 양쪽 모두 배열인 값의 병합 방식
+type ArrayMergeStrategy int
+
+const (
+	ArrayReplace    ArrayMergeStrategy = iota // 나중 배열로 교체
+	ArrayAppend                               // 나중 배열의 원소를 이어 붙임
+	ArrayMergeByKey                           // MergeKey 값이 같은 객체 원소끼리 깊은 병합, 나머지는 이어 붙임
+)
+
+// MergeOptions DeepMerge 옵션
+type MergeOptions struct {
+	Arrays   ArrayMergeStrategy
+	MergeKey string // ArrayMergeByKey 에서 원소를 식별하는 키(예: name)
+}
+
+// DeepMerge 맵을 순서대로 깊은 병합한 새 맵을 반환(인자로 받은 맵은 수정하지 않음)
+// 양쪽 모두 맵인 값은 재귀적으로 병합하고, 배열은 옵션의 방식으로 병합하며, 그 외에는 나중 값이 우선한다.
+func DeepMerge(options *MergeOptions, maps ...map[string]interface{}) map[string]interface{} {
+	if options == nil {
+		options = &MergeOptions{}
+	}
+	result := make(map[string]interface{})
+	for _, m := range maps {
+		result = mergeMap(result, m, options)
+	}
+	return result
+}
+
+func mergeMap(dst map[string]interface{}, src map[string]interface{}, options *MergeOptions) map[string]interface{} {
+	for key, value := range src {
+		dst[key] = mergeValue(dst[key], value, options)
+	}
+	return dst
+}
+
+func mergeValue(dst interface{}, src interface{}, options *MergeOptions) interface{} {
+	switch srcValue := src.(type) {
+	case map[string]interface{}:
+		if dstMap, ok := dst.(map[string]interface{}); ok {
+			return mergeMap(dstMap, srcValue, options)
+		}
+	case []interface{}:
+		if dstSlice, ok := dst.([]interface{}); ok {
+			switch options.Arrays {
+			case ArrayAppend:
+				return append(dstSlice, DeepCopy(srcValue).([]interface{})...)
+			case ArrayMergeByKey:
+				return mergeByKey(dstSlice, srcValue, options)
+			}
+		}
+	}
+	return DeepCopy(src)
+}
+
+// mergeByKey MergeKey 값이 같은 객체 원소끼리 병합하고 그 외의 원소는 이어 붙이는 함수
+func mergeByKey(dst []interface{}, src []interface{}, options *MergeOptions) []interface{} {
+	for _, element := range src {
+		object, ok := element.(map[string]interface{})
+		key, hasKey := object[options.MergeKey]
+		merged := false
+		if ok && hasKey {
+			for i, existing := range dst {
+				if existingObject, ok := existing.(map[string]interface{}); ok && jsonEqual(existingObject[options.MergeKey], key) {
+					dst[i] = mergeMap(existingObject, object, options)
+					merged = true
+					break
+				}
+			}
+		}
+		if !merged {
+			dst = append(dst, DeepCopy(element))
+		}
+	}
+	return dst
+}
+
+// DeepCopy JSON 문서(맵, 배열)를 깊은 복사하는 함수(맵과 배열 이외의 값은 그대로 반환)
+func DeepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			copied[key] = DeepCopy(element)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, element := range v {
+			copied[i] = DeepCopy(element)
+		}
+		return copied
+	}
+	return value
+}
+
+// jsonEqual 두 JSON 값이 같은지 비교하는 함수(숫자는 타입과 관계없이 값으로 비교)
+func jsonEqual(a interface{}, b interface{}) bool {
+	switch aValue := a.(type) {
+	case map[string]interface{}:
+		bValue, ok := b.(map[string]interface{})
+		if !ok || len(aValue) != len(bValue) {
+			return false
+		}
+		for key, element := range aValue {
+			other, ok := bValue[key]
+			if !ok || !jsonEqual(element, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bValue, ok := b.([]interface{})
+		if !ok || len(aValue) != len(bValue) {
+			return false
+		}
+		for i := range aValue {
+			if !jsonEqual(aValue[i], bValue[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if isNumber(a) && isNumber(b) {
+		aFloat, _ := toFloat(a)
+		bFloat, _ := toFloat(b)
+		return aFloat == bFloat
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// isNumber 값이 숫자 타입인지 확인하는 함수
+func isNumber(value interface{}) bool {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func decodeJSON(t *testing.T, document string) interface{} {
+	t.Helper()
+	var value interface{}
+	if err := json.Unmarshal([]byte(document), &value); err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+func TestSetAndDelete(t *testing.T) {
+	document := decodeJSON(t, `{"data":{"items":[1,2]}}`)
+
+	document, err := Set(document, "data.result.0.value", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document, err = Set(document, "data.items.-1", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document, err = Set(document, "data.items.4", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := decodeJSON(t, `{"data":{"items":[1,2,3,null,5],"result":[{"value":1}]}}`)
+	if !jsonEqual(document, expected) {
+		t.Errorf("expected %v, got %v", expected, document)
+	}
+
+	if _, err = Set(document, "data.items.0.value", 1); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("expected type mismatch, got %v", err)
+	}
+	if _, err = Set(document, "data.items.#", 1); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("expected invalid path for wildcard, got %v", err)
+	}
+
+	document, err = Delete(document, "data.items.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	document, err = Delete(document, "data.result")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = decodeJSON(t, `{"data":{"items":[1,2,3,5]}}`)
+	if !jsonEqual(document, expected) {
+		t.Errorf("expected %v, got %v", expected, document)
+	}
+	if _, err = Delete(document, "data.missing"); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("expected path not found, got %v", err)
+	}
+}
+
+func TestDeepMerge(t *testing.T) {
+	base := decodeJSON(t, `{"CPU":{"unit":"core","values":[1]},"containers":[{"name":"a","cpu":1},{"name":"b","cpu":2}]}`).(map[string]interface{})
+	override := decodeJSON(t, `{"CPU":{"label":"CPU","values":[2]},"containers":[{"name":"b","memory":3},{"name":"c"}]}`).(map[string]interface{})
+	original := DeepCopy(base)
+
+	tests := []struct {
+		name     string
+		options  *MergeOptions
+		expected string
+	}{
+		{"replace arrays", nil,
+			`{"CPU":{"unit":"core","label":"CPU","values":[2]},"containers":[{"name":"b","memory":3},{"name":"c"}]}`},
+		{"append arrays", &MergeOptions{Arrays: ArrayAppend},
+			`{"CPU":{"unit":"core","label":"CPU","values":[1,2]},"containers":[{"name":"a","cpu":1},{"name":"b","cpu":2},{"name":"b","memory":3},{"name":"c"}]}`},
+		{"merge arrays by key", &MergeOptions{Arrays: ArrayMergeByKey, MergeKey: "name"},
+			`{"CPU":{"unit":"core","label":"CPU","values":[1,2]},"containers":[{"name":"a","cpu":1},{"name":"b","cpu":2,"memory":3},{"name":"c"}]}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged := DeepMerge(test.options, base, override)
+			if expected := decodeJSON(t, test.expected); !jsonEqual(merged, expected) {
+				t.Errorf("expected %v, got %v", expected, merged)
+			}
+			if !reflect.DeepEqual(base, original) {
+				t.Errorf("input was modified, got %v", base)
+			}
+		})
+	}
+}
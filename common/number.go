@@ -0,0 +1,232 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidNumber 숫자로 변환할 수 없는 값인 경우의 에러
+var ErrInvalidNumber = errors.New("invalid number")
+
+// RoundingMode 반올림 방식
+type RoundingMode int
+
+const (
+	RoundHalfUp   RoundingMode = iota // 0.5 는 0 에서 먼 쪽으로 올림(2.5 => 3, -2.5 => -3)
+	RoundHalfEven                     // 0.5 는 짝수 쪽으로(은행가 반올림, 2.5 => 2, 3.5 => 4)
+)
+
+// Number 메트릭 값 타입(프로메테우스 응답의 원본 문자열과 float64 값을 함께 보관)
+// NaN, +Inf, -Inf 를 파싱하고, JSON 으로는 유한한 값은 숫자, 그 외에는 "NaN", "+Inf", "-Inf" 문자열로 변환한다.
+// float64 로 표현할 수 없는 큰 카운터 값은 Raw 로 원본 자릿수를 유지한다.
+type Number struct {
+	Value float64
+	Raw   string // 원본 10진수 문자열(없으면 Value 로 표현)
+}
+
+// NewNumber float64 값으로 Number 를 생성
+func NewNumber(value float64) Number {
+	return Number{Value: value}
+}
+
+// ParseNumber 문자열, 숫자 타입, json.Number 를 Number 로 변환하는 함수
+// 프로메테우스의 특수값(NaN, +Inf, -Inf)을 지원하며, float64 범위를 넘는 값은 ±Inf 와 원본 문자열로 보관한다.
+/* ParseNumber("1.261144727299423")    => {1.261144727299423 1.261144727299423}
+ * ParseNumber("+Inf")                 => {+Inf +Inf}
+ * ParseNumber("18446744073709551615") => {1.8446744073709552e+19 18446744073709551615}
+ */
+func ParseNumber(value interface{}) (Number, error) {
+	switch v := value.(type) {
+	case Number:
+		return v, nil
+	case string:
+		return parseNumberString(v)
+	case json.Number:
+		return parseNumberString(string(v))
+	case nil:
+		return Number{}, fmt.Errorf("failed to parse number, err=%w, value=nil", ErrInvalidNumber)
+	}
+	if float, ok := toFloat(value); ok && isNumber(value) {
+		return Number{Value: float}, nil
+	}
+	return Number{}, fmt.Errorf("failed to parse number, err=%w, value=%v", ErrInvalidNumber, value)
+}
+
+func parseNumberString(value string) (Number, error) {
+	text := strings.TrimSpace(value)
+	float, err := strconv.ParseFloat(text, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return Number{}, fmt.Errorf("failed to parse number, err=%w, value=%s", ErrInvalidNumber, value)
+	}
+	if math.IsNaN(float) || (math.IsInf(float, 0) && err == nil) {
+		// 표기법(nan, inf, Infinity 등)을 프로메테우스 표기로 맞춤
+		return Number{Value: float, Raw: formatFloat(float)}, nil
+	}
+	return Number{Value: float, Raw: text}, nil
+}
+
+// IsNaN 값이 NaN 인지 확인
+func (n Number) IsNaN() bool {
+	return math.IsNaN(n.Value)
+}
+
+// IsInf 값이 +Inf 또는 -Inf 인지 확인
+func (n Number) IsInf() bool {
+	return math.IsInf(n.Value, 0)
+}
+
+// IsFinite 값이 NaN, ±Inf 가 아닌지 확인(원본이 float64 범위를 넘는 경우에도 false)
+func (n Number) IsFinite() bool {
+	return !n.IsNaN() && !n.IsInf()
+}
+
+// Float64 float64 값
+func (n Number) Float64() float64 {
+	return n.Value
+}
+
+// BigFloat 원본 자릿수를 유지한 임의 정밀도 값을 반환(NaN 은 nil, false)
+func (n Number) BigFloat() (*big.Float, bool) {
+	if n.IsNaN() {
+		return nil, false
+	}
+	if n.Raw != "" {
+		if float, _, err := big.ParseFloat(n.Raw, 10, 256, big.ToNearestEven); err == nil {
+			return float, true
+		}
+	}
+	return big.NewFloat(n.Value), true
+}
+
+// String 원본 문자열(없으면 프로메테우스 표기의 값: NaN, +Inf, -Inf, 1.5)
+func (n Number) String() string {
+	if n.Raw != "" {
+		return n.Raw
+	}
+	return formatFloat(n.Value)
+}
+
+// Round 소수점 places 자리로 반올림한 Number 를 반환(원본 10진수 문자열에서 반올림하므로 큰 값도 정확함)
+func (n Number) Round(places uint, mode RoundingMode) Number {
+	if !n.IsFinite() && (n.Raw == "" || n.IsNaN() || strings.ContainsAny(n.Raw, "Ii")) {
+		return n
+	}
+	rounded := roundDecimal(decimalText(n), places, mode)
+	float, _ := strconv.ParseFloat(rounded, 64)
+	return Number{Value: float, Raw: rounded}
+}
+
+// MarshalJSON 유한한 값은 숫자, NaN 과 ±Inf 는 "NaN", "+Inf", "-Inf" 문자열로 변환
+func (n Number) MarshalJSON() ([]byte, error) {
+	if !n.IsFinite() {
+		return json.Marshal(formatFloat(n.Value))
+	}
+	if n.Raw != "" && json.Valid([]byte(n.Raw)) {
+		return []byte(n.Raw), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+// UnmarshalJSON 숫자 또는 숫자 문자열("NaN", "+Inf" 포함)을 Number 로 변환
+func (n *Number) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	number, err := ParseNumber(value)
+	if err != nil {
+		return err
+	}
+	*n = number
+	return nil
+}
+
+// formatFloat 프로메테우스 표기로 float64 를 문자열로 변환(NaN, +Inf, -Inf)
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// decimalText 지수 표기가 없는 10진수 문자열을 반환
+func decimalText(n Number) string {
+	if n.Raw == "" || (n.IsInf() && strings.ContainsAny(n.Raw, "Ii")) {
+		return formatFloat(n.Value)
+	}
+	text := strings.TrimPrefix(n.Raw, "+")
+	index := strings.IndexAny(text, "eE")
+	if index == -1 {
+		return text
+	}
+	exponent, err := strconv.Atoi(text[index+1:])
+	if err != nil {
+		return formatFloat(n.Value)
+	}
+	return shiftDecimal(text[:index], exponent)
+}
+
+// shiftDecimal 10진수 문자열의 소수점을 exponent 만큼 이동(1.5, 3 => 1500)
+func shiftDecimal(mantissa string, exponent int) string {
+	negative := strings.HasPrefix(mantissa, "-")
+	mantissa = strings.TrimLeft(mantissa, "+-")
+	integer, fraction, _ := strings.Cut(mantissa, ".")
+	digits := integer + fraction
+	point := len(integer) + exponent
+	switch {
+	case point <= 0:
+		digits = "0." + strings.Repeat("0", -point) + digits
+	case point >= len(digits):
+		digits += strings.Repeat("0", point-len(digits))
+	default:
+		digits = digits[:point] + "." + digits[point:]
+	}
+	if negative {
+		return "-" + digits
+	}
+	return digits
+}
+
+// roundDecimal 10진수 문자열을 소수점 places 자리로 반올림한 문자열을 반환(소수점 이하 끝의 0 은 제거)
+func roundDecimal(text string, places uint, mode RoundingMode) string {
+	negative := strings.HasPrefix(text, "-")
+	integer, fraction, _ := strings.Cut(strings.TrimLeft(text, "+-"), ".")
+	if integer == "" {
+		integer = "0"
+	}
+	if uint(len(fraction)) > places {
+		next := fraction[places]
+		rest := strings.Trim(fraction[places+1:], "0")
+		digits := []byte(integer + fraction[:places])
+
+		roundUp := next > '5' || (next == '5' && (mode == RoundHalfUp || rest != "" || (digits[len(digits)-1]-'0')%2 == 1))
+		if roundUp {
+			i := len(digits) - 1
+			for ; i >= 0 && digits[i] == '9'; i-- {
+				digits[i] = '0'
+			}
+			if i < 0 {
+				digits = append([]byte{'1'}, digits...)
+			} else {
+				digits[i]++
+			}
+		}
+		integer, fraction = string(digits[:len(digits)-int(places)]), string(digits[len(digits)-int(places):])
+	}
+
+	result := strings.TrimLeft(integer, "0")
+	if result == "" {
+		result = "0"
+	}
+	if fraction = strings.TrimRight(fraction, "0"); fraction != "" {
+		result += "." + fraction
+	}
+	if negative && strings.Trim(result, "0.") != "" {
+		result = "-" + result
+	}
+	return result
+}
@@ -0,0 +1,121 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+		finite   bool
+	}{
+		{"1.261144727299423", "1.261144727299423", true},
+		{"NaN", "NaN", false},
+		{"+Inf", "+Inf", false},
+		{"-Inf", "-Inf", false},
+		{"inf", "+Inf", false},
+		{"18446744073709551615", "18446744073709551615", true},
+		{"1e400", "1e400", false},
+		{2.5, "2.5", true},
+		{int64(3), "3", true},
+		{json.Number("4.5"), "4.5", true},
+	}
+	for _, test := range tests {
+		number, err := ParseNumber(test.value)
+		if err != nil {
+			t.Fatalf("%v: %s", test.value, err)
+		}
+		if number.String() != test.expected || number.IsFinite() != test.finite {
+			t.Errorf("%v: expected %s (finite=%v), got %s (finite=%v)", test.value, test.expected, test.finite, number, number.IsFinite())
+		}
+	}
+
+	for _, value := range []interface{}{nil, "", "abc", true, []int{1}} {
+		if _, err := ParseNumber(value); !errors.Is(err, ErrInvalidNumber) {
+			t.Errorf("%v: expected invalid number, got %v", value, err)
+		}
+	}
+
+	counter, _ := ParseNumber("18446744073709551615")
+	float, _ := counter.BigFloat()
+	if integer, _ := float.Int(nil); integer.String() != "18446744073709551615" {
+		t.Errorf("expected exact big value, got %s", integer)
+	}
+}
+
+func TestNumberRound(t *testing.T) {
+	tests := []struct {
+		value    string
+		places   uint
+		mode     RoundingMode
+		expected string
+	}{
+		{"1.005", 2, RoundHalfUp, "1.01"},
+		{"2.5", 0, RoundHalfUp, "3"},
+		{"-2.5", 0, RoundHalfUp, "-3"},
+		{"2.5", 0, RoundHalfEven, "2"},
+		{"3.5", 0, RoundHalfEven, "4"},
+		{"2.5000001", 0, RoundHalfEven, "3"},
+		{"9.995", 2, RoundHalfUp, "10"},
+		{"-0.001", 2, RoundHalfUp, "0"},
+		{"1.5e-3", 3, RoundHalfEven, "0.002"},
+		{"18446744073709551615.5", 0, RoundHalfUp, "18446744073709551616"},
+		{"1e400", 0, RoundHalfUp, "1" + zeros(400)},
+		{"+Inf", 2, RoundHalfUp, "+Inf"},
+		{"-Inf", 2, RoundHalfUp, "-Inf"},
+		{"NaN", 2, RoundHalfUp, "NaN"},
+	}
+	for _, test := range tests {
+		number, _ := ParseNumber(test.value)
+		if rounded := number.Round(test.places, test.mode); rounded.String() != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.value, test.expected, rounded)
+		}
+	}
+
+	if value := RoundFloat(1e300, 2); value != 1e300 {
+		t.Errorf("expected large value unchanged, got %v", value)
+	}
+	if value := RoundFloat(math.Inf(1), 2); !math.IsInf(value, 1) {
+		t.Errorf("expected +Inf unchanged, got %v", value)
+	}
+	if value := RoundFloatMode(0.125, 2, RoundHalfEven); value != 0.12 {
+		t.Errorf("expected banker's rounding, got %v", value)
+	}
+}
+
+func zeros(n int) string {
+	result := make([]byte, n)
+	for i := range result {
+		result[i] = '0'
+	}
+	return string(result)
+}
+
+func TestNumberJSON(t *testing.T) {
+	counter, _ := ParseNumber("18446744073709551615")
+	data, err := json.Marshal(map[string]interface{}{
+		"nan":     NewNumber(math.NaN()),
+		"inf":     NewNumber(math.Inf(-1)),
+		"value":   NewNumber(1.5),
+		"counter": counter,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"counter":18446744073709551615,"inf":"-Inf","nan":"NaN","value":1.5}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	var decoded map[string]Number
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded["nan"].IsNaN() || !math.IsInf(decoded["inf"].Value, -1) || decoded["counter"].String() != "18446744073709551615" {
+		t.Errorf("unexpected round trip %v", decoded)
+	}
+}
@@ -0,0 +1,360 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrTestFailed JSON Patch 의 test 연산이 실패한 경우의 에러
+var ErrTestFailed = errors.New("test operation failed")
+
+// PatchOperation RFC 6902 JSON Patch 연산
+/* [{"op":"replace","path":"/data/result/0/value/1","value":"0"},{"op":"remove","path":"/data/warnings"}]
+ */
+type PatchOperation struct {
+	Op    string      `json:"op"`             // add, remove, replace, move, copy, test
+	Path  string      `json:"path"`           // JSON Pointer(RFC 6901)
+	From  string      `json:"from,omitempty"` // move, copy 의 원본 경로
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON value 가 필요한 연산(add, replace, test)은 null 인 경우에도 value 를 포함한다
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	type operation PatchOperation
+	switch o.Op {
+	case "add", "replace", "test":
+		return json.Marshal(struct {
+			operation
+			Value interface{} `json:"value"`
+		}{operation(o), o.Value})
+	}
+	return json.Marshal(operation(o))
+}
+
+// ApplyJSONPatch JSON 문서에 RFC 6902 JSON Patch 를 적용한 새 문서를 반환
+// 연산 중 하나라도 실패하면 원본 문서와 에러를 반환한다(원본 문서는 수정하지 않음).
+func ApplyJSONPatch(document interface{}, patch []PatchOperation) (interface{}, error) {
+	result := DeepCopy(document)
+	for i, operation := range patch {
+		var err error
+		if result, err = applyOperation(result, operation); err != nil {
+			return document, fmt.Errorf("failed to apply patch operation, index=%d, op=%s, path=%s, err=%w", i, operation.Op, operation.Path, err)
+		}
+	}
+	return result, nil
+}
+
+func applyOperation(document interface{}, operation PatchOperation) (interface{}, error) {
+	tokens, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch operation.Op {
+	case "add":
+		return addPointer(document, tokens, DeepCopy(operation.Value))
+	case "remove":
+		result, _, err := removePointer(document, tokens)
+		return result, err
+	case "replace":
+		if _, err = getPointer(document, tokens); err != nil {
+			return nil, err
+		}
+		if result, _, err := removePointer(document, tokens); err == nil {
+			document = result
+		}
+		return addPointer(document, tokens, DeepCopy(operation.Value))
+	case "move", "copy":
+		fromTokens, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		if operation.Op == "move" && isPrefix(fromTokens, tokens) && len(fromTokens) < len(tokens) {
+			return nil, fmt.Errorf("%w, cannot move a value into its child", ErrInvalidPath)
+		}
+		value, err := getPointer(document, fromTokens)
+		if err != nil {
+			return nil, err
+		}
+		if operation.Op == "move" {
+			if document, _, err = removePointer(document, fromTokens); err != nil {
+				return nil, err
+			}
+		} else {
+			value = DeepCopy(value)
+		}
+		return addPointer(document, tokens, value)
+	case "test":
+		value, err := getPointer(document, tokens)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(value, operation.Value) {
+			return nil, ErrTestFailed
+		}
+		return document, nil
+	}
+	return nil, fmt.Errorf("unsupported patch operation, op=%s", operation.Op)
+}
+
+// parsePointer RFC 6901 JSON Pointer 를 토큰 목록으로 변환하는 함수(~1 은 /, ~0 은 ~)
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w, json pointer must start with /, pointer=%s", ErrInvalidPath, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// formatPointer 토큰 목록을 JSON Pointer 로 변환하는 함수
+func formatPointer(tokens []string) string {
+	var pointer strings.Builder
+	for _, token := range tokens {
+		pointer.WriteString("/")
+		pointer.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return pointer.String()
+}
+
+func isPrefix(prefix []string, tokens []string) bool {
+	if len(prefix) > len(tokens) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != tokens[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// arrayIndex JSON Pointer 의 배열 인덱스 토큰을 검증하여 변환하는 함수(선행 0 과 음수는 허용하지 않음)
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w, invalid array index, index=%s", ErrInvalidPath, token)
+	}
+	if index > length || (!allowEnd && index == length) {
+		return 0, fmt.Errorf("%w, array index out of range, index=%s", ErrPathNotFound, token)
+	}
+	return index, nil
+}
+
+func getPointer(document interface{}, tokens []string) (interface{}, error) {
+	value := document
+	for _, token := range tokens {
+		switch container := value.(type) {
+		case map[string]interface{}:
+			child, ok := container[token]
+			if !ok {
+				return nil, fmt.Errorf("%w, pointer=%s", ErrPathNotFound, formatPointer(tokens))
+			}
+			value = child
+		case []interface{}:
+			index, err := arrayIndex(token, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			value = container[index]
+		default:
+			return nil, fmt.Errorf("%w, pointer=%s", ErrPathNotFound, formatPointer(tokens))
+		}
+	}
+	return value, nil
+}
+
+// addPointer 객체에는 키를 추가(또는 교체)하고 배열에는 인덱스 위치에 삽입하는 함수
+func addPointer(document interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	token := tokens[0]
+	switch container := document.(type) {
+	case map[string]interface{}:
+		if len(tokens) == 1 {
+			container[token] = value
+			return container, nil
+		}
+		child, ok := container[token]
+		if !ok {
+			return nil, fmt.Errorf("%w, parent does not exist, key=%s", ErrPathNotFound, token)
+		}
+		child, err := addPointer(child, tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = child
+		return container, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(container), len(tokens) == 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(tokens) == 1 {
+			container = append(container, nil)
+			copy(container[index+1:], container[index:])
+			container[index] = value
+			return container, nil
+		}
+		child, err := addPointer(container[index], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = child
+		return container, nil
+	}
+	return nil, fmt.Errorf("%w, key=%s", ErrTypeMismatch, token)
+}
+
+// removePointer 경로의 값을 삭제하고 수정된 문서와 삭제된 값을 반환하는 함수
+func removePointer(document interface{}, tokens []string) (interface{}, interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("%w, cannot remove the root", ErrInvalidPath)
+	}
+	token := tokens[0]
+	switch container := document.(type) {
+	case map[string]interface{}:
+		child, ok := container[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w, key=%s", ErrPathNotFound, token)
+		}
+		if len(tokens) == 1 {
+			delete(container, token)
+			return container, child, nil
+		}
+		child, removed, err := removePointer(child, tokens[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		container[token] = child
+		return container, removed, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(container), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(tokens) == 1 {
+			removed := container[index]
+			return append(container[:index], container[index+1:]...), removed, nil
+		}
+		child, removed, err := removePointer(container[index], tokens[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		container[index] = child
+		return container, removed, nil
+	}
+	return nil, nil, fmt.Errorf("%w, key=%s", ErrPathNotFound, token)
+}
+
+// DiffJSONPatch 원본 문서를 수정된 문서로 변환하는 RFC 6902 JSON Patch 를 생성
+// 객체는 키 단위로 비교하고, 길이가 같은 배열은 원소 단위로, 길이가 다른 배열은 배열 전체를 교체한다.
+func DiffJSONPatch(original interface{}, modified interface{}) []PatchOperation {
+	return diffJSONPatch(nil, original, modified, []PatchOperation{})
+}
+
+func diffJSONPatch(tokens []string, original interface{}, modified interface{}, patch []PatchOperation) []PatchOperation {
+	if jsonEqual(original, modified) {
+		return patch
+	}
+	switch originalValue := original.(type) {
+	case map[string]interface{}:
+		if modifiedValue, ok := modified.(map[string]interface{}); ok {
+			for _, key := range sortedKeys(originalValue) {
+				if _, ok := modifiedValue[key]; !ok {
+					childTokens := append(append([]string{}, tokens...), key)
+					patch = append(patch, PatchOperation{Op: "remove", Path: formatPointer(childTokens)})
+				}
+			}
+			for _, key := range sortedKeys(modifiedValue) {
+				childTokens := append(append([]string{}, tokens...), key)
+				if originalChild, ok := originalValue[key]; ok {
+					patch = diffJSONPatch(childTokens, originalChild, modifiedValue[key], patch)
+				} else {
+					patch = append(patch, PatchOperation{Op: "add", Path: formatPointer(childTokens), Value: DeepCopy(modifiedValue[key])})
+				}
+			}
+			return patch
+		}
+	case []interface{}:
+		if modifiedValue, ok := modified.([]interface{}); ok && len(originalValue) == len(modifiedValue) {
+			for i := range originalValue {
+				childTokens := append(append([]string{}, tokens...), strconv.Itoa(i))
+				patch = diffJSONPatch(childTokens, originalValue[i], modifiedValue[i], patch)
+			}
+			return patch
+		}
+	}
+	return append(patch, PatchOperation{Op: "replace", Path: formatPointer(tokens), Value: DeepCopy(modified)})
+}
+
+// ApplyMergePatch JSON 문서에 RFC 7386 JSON Merge Patch 를 적용한 새 문서를 반환(원본 문서는 수정하지 않음)
+// 패치의 null 값은 키를 삭제하고, 객체는 재귀적으로 적용하며, 그 외의 값(배열 포함)은 교체한다.
+func ApplyMergePatch(document interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return DeepCopy(patch)
+	}
+	result, ok := DeepCopy(document).(map[string]interface{})
+	if !ok {
+		result = make(map[string]interface{})
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+		result[key] = ApplyMergePatch(result[key], value)
+	}
+	return result
+}
+
+// DiffMergePatch 원본 문서를 수정된 문서로 변환하는 RFC 7386 JSON Merge Patch 를 생성
+// Merge Patch 는 null 값을 표현할 수 없으므로 수정된 문서의 null 값은 키 삭제로 표현된다.
+func DiffMergePatch(original interface{}, modified interface{}) interface{} {
+	originalObject, originalOk := original.(map[string]interface{})
+	modifiedObject, modifiedOk := modified.(map[string]interface{})
+	if !originalOk || !modifiedOk {
+		return DeepCopy(modified)
+	}
+	patch := make(map[string]interface{})
+	for key := range originalObject {
+		if _, ok := modifiedObject[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	for key, value := range modifiedObject {
+		originalValue, ok := originalObject[key]
+		if ok && jsonEqual(originalValue, value) {
+			continue
+		}
+		if ok {
+			patch[key] = DiffMergePatch(originalValue, value)
+		} else {
+			patch[key] = DeepCopy(value)
+		}
+	}
+	return patch
+}
+
+// sortedKeys 맵의 키를 정렬하여 반환하는 함수(패치 생성 결과를 일정하게 유지)
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,89 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		patch    string
+		expected string
+	}{
+		{"add to array", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"append to array", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":"baz"}]`, `{"foo":["bar","baz"]}`},
+		{"remove", `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{"replace", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"move", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"copy", `{"foo":{"bar":1}}`, `[{"op":"copy","from":"/foo","path":"/baz"}]`, `{"foo":{"bar":1},"baz":{"bar":1}}`},
+		{"escaped pointer", `{"a/b":{"m~n":1}}`, `[{"op":"test","path":"/a~1b/m~0n","value":1},{"op":"remove","path":"/a~1b/m~0n"}]`, `{"a/b":{}}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var patch []PatchOperation
+			if err := json.Unmarshal([]byte(test.patch), &patch); err != nil {
+				t.Fatal(err)
+			}
+			result, err := ApplyJSONPatch(decodeJSON(t, test.document), patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := decodeJSON(t, test.expected); !jsonEqual(result, expected) {
+				t.Errorf("expected %v, got %v", expected, result)
+			}
+		})
+	}
+
+	document := decodeJSON(t, `{"foo":"bar"}`)
+	result, err := ApplyJSONPatch(document, []PatchOperation{
+		{Op: "remove", Path: "/foo"},
+		{Op: "test", Path: "/foo", Value: "bar"},
+	})
+	if !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("expected path not found, got %v", err)
+	}
+	if !jsonEqual(result, decodeJSON(t, `{"foo":"bar"}`)) || !jsonEqual(document, result) {
+		t.Errorf("expected original document on failure, got %v", result)
+	}
+	if _, err = ApplyJSONPatch(document, []PatchOperation{{Op: "test", Path: "/foo", Value: "baz"}}); !errors.Is(err, ErrTestFailed) {
+		t.Errorf("expected test failed, got %v", err)
+	}
+	if _, err = ApplyJSONPatch(decodeJSON(t, `{"a":{"b":1}}`), []PatchOperation{{Op: "move", From: "/a", Path: "/a/c"}}); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("expected invalid path for moving into a child, got %v", err)
+	}
+}
+
+func TestDiffJSONPatch(t *testing.T) {
+	original := decodeJSON(t, `{"status":"success","data":{"result":[{"value":[1,"0.5"]},{"value":[1,"0.2"]}],"warnings":["w"]}}`)
+	modified := decodeJSON(t, `{"status":"success","data":{"result":[{"value":[1,"0.7"]},{"value":[1,"0.2"]}],"resultType":"vector"}}`)
+
+	patch := DiffJSONPatch(original, modified)
+	result, err := ApplyJSONPatch(original, patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jsonEqual(result, modified) {
+		t.Errorf("expected %v, got %v (patch=%v)", modified, result, patch)
+	}
+	if len(patch) != 3 {
+		t.Errorf("expected 3 operations, got %v", patch)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	document := decodeJSON(t, `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`)
+	patch := decodeJSON(t, `{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`)
+	expected := decodeJSON(t, `{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`)
+
+	result := ApplyMergePatch(document, patch)
+	if !jsonEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+	if diff := DiffMergePatch(document, expected); !jsonEqual(ApplyMergePatch(document, diff), expected) {
+		t.Errorf("expected merge patch round trip, got %v", diff)
+	}
+}
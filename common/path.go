@@ -0,0 +1,514 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 경로 문법(gjson 과 유사)
+/* 예시 데이터: {"data":{"result":[{"metric":{"namespace":"a","app.kubernetes.io/name":"x"},"value":[1654736096.03,"1.26"]},
+ *                                {"metric":{"namespace":"b"},"value":[1654736096.03,"0.51"]}]}}
+ * data.result.0.value.1                         => "1.26"             (키와 인덱스를 . 으로 구분)
+ * data.result.#                                 => 2                  (마지막 # 은 배열의 길이)
+ * data.result.#.metric.namespace                => ["a", "b"]         (중간의 # 은 모든 원소에 나머지 경로를 적용)
+ * data.result.0.metric.app\.kubernetes\.io/name => "x"                (\ 로 . # ( ) \ 문자를 이스케이프)
+ * data.result.#(metric.namespace=="b").value.1  => "0.51"             (#(조건) 은 조건을 만족하는 첫 번째 원소)
+ * data.result.#(value.1>"1")#.metric.namespace  => ["a"]              (#(조건)# 은 조건을 만족하는 모든 원소)
+ * 조건의 연산자는 ==, !=, <, <=, >, >=, %(glob 패턴 일치, * 와 ? 사용) 이며, 값은 "문자열", 숫자, true, false, null 중 하나
+ * 연산자 없이 경로만 작성하면(#(metric.namespace)) 경로가 존재하는 원소를 선택한다.
+ */
+
+var (
+	// ErrPathNotFound 경로에 해당하는 값이 없는 경우의 에러
+	ErrPathNotFound = errors.New("path not found")
+	// ErrInvalidPath 경로 문법이 올바르지 않은 경우의 에러
+	ErrInvalidPath = errors.New("invalid path")
+)
+
+// PathError 경로 조회 실패 시 실패한 경로 구간을 포함하는 에러
+type PathError struct {
+	Path    string
+	Segment string
+	Err     error
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("%s, path=%s, segment=%s", e.Err, e.Path, e.Segment)
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// segmentType 경로 구간의 종류
+type segmentType int
+
+const (
+	keySegment      segmentType = iota // 맵의 키, 구조체의 필드, 배열의 인덱스
+	wildcardSegment                    // # (배열의 길이 또는 모든 원소)
+	filterSegment                      // #(조건) 또는 #(조건)#
+)
+
+// segment 경로 구간
+type segment struct {
+	kind      segmentType
+	raw       string
+	key       string
+	condition *condition
+	all       bool // #(조건)# 인 경우 조건을 만족하는 모든 원소를 선택
+}
+
+// condition #(조건) 의 조건
+type condition struct {
+	path     []segment
+	operator string // 빈 문자열이면 경로의 존재 여부만 확인
+	value    interface{}
+}
+
+// conditionOperators 조건의 연산자 목록(긴 연산자를 먼저 확인)
+var conditionOperators = []string{"==", "!=", "<=", ">=", "<", ">", "%"}
+
+// GetE 경로에 해당하는 값을 반환하며, 값이 없거나 경로가 올바르지 않으면 에러를 반환
+// 맵(키 타입이 문자열 또는 숫자), 슬라이스, 배열, 구조체(필드 이름 또는 json 태그 이름), 포인터를 리플렉션으로 탐색한다.
+func GetE(data interface{}, path string) (interface{}, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, &PathError{Path: path, Segment: path, Err: err}
+	}
+	value, err := walk(data, segments)
+	if pathErr, ok := err.(*PathError); ok {
+		pathErr.Path = path
+	}
+	return value, err
+}
+
+// GetString 경로에 해당하는 값을 문자열로 반환(값이 없으면 빈 문자열)
+func GetString(data interface{}, path string) string {
+	value, err := GetE(data, path)
+	if err != nil || value == nil {
+		return ""
+	}
+	return toString(value)
+}
+
+// GetFloat 경로에 해당하는 값을 실수로 반환(값이 없거나 숫자로 변환할 수 없으면 0)
+func GetFloat(data interface{}, path string) float64 {
+	value, err := GetE(data, path)
+	if err != nil {
+		return 0
+	}
+	float, _ := toFloat(value)
+	return float
+}
+
+// GetSlice 경로에 해당하는 값을 슬라이스로 반환(값이 없으면 nil, 배열이 아닌 값은 원소 하나의 슬라이스)
+func GetSlice(data interface{}, path string) []interface{} {
+	value, err := GetE(data, path)
+	if err != nil || value == nil {
+		return nil
+	}
+	v := indirect(reflect.ValueOf(value))
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return []interface{}{value}
+	}
+	slice := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		slice = append(slice, v.Index(i).Interface())
+	}
+	return slice
+}
+
+// parsePath 경로 문자열을 경로 구간 목록으로 변환하는 함수
+func parsePath(path string) ([]segment, error) {
+	if path == "" {
+		return nil, nil
+	}
+	var segments []segment
+	for i := 0; i <= len(path); {
+		// #(조건) 또는 #(조건)#
+		if strings.HasPrefix(path[i:], "#(") {
+			start := i
+			end, err := closingParenthesis(path, i+1)
+			if err != nil {
+				return nil, err
+			}
+			cond, err := parseCondition(path[i+2 : end])
+			if err != nil {
+				return nil, err
+			}
+			s := segment{kind: filterSegment, condition: cond}
+			i = end + 1
+			if i < len(path) && path[i] == '#' {
+				s.all = true
+				i++
+			}
+			s.raw = path[start:i]
+			if i < len(path) && path[i] != '.' {
+				return nil, fmt.Errorf("%w, unexpected character after filter, path=%s", ErrInvalidPath, path)
+			}
+			segments = append(segments, s)
+			i++
+			continue
+		}
+
+		var key strings.Builder
+		start := i
+		escaped := false
+		for ; i < len(path) && path[i] != '.'; i++ {
+			if path[i] == '\\' && i+1 < len(path) {
+				i++
+				escaped = true
+			}
+			key.WriteByte(path[i])
+		}
+		if key.String() == "#" && !escaped {
+			segments = append(segments, segment{kind: wildcardSegment, raw: "#"})
+		} else {
+			segments = append(segments, segment{kind: keySegment, raw: path[start:i], key: key.String()})
+		}
+		i++
+	}
+	return segments, nil
+}
+
+// closingParenthesis 여는 괄호에 대응하는 닫는 괄호의 위치를 찾는 함수(따옴표 안의 괄호는 무시)
+func closingParenthesis(path string, open int) (int, error) {
+	depth := 0
+	inQuote := false
+	for i := open; i < len(path); i++ {
+		switch c := path[i]; {
+		case c == '\\':
+			i++
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%w, unclosed filter, path=%s", ErrInvalidPath, path)
+}
+
+// parseCondition #(조건) 의 조건 문자열을 파싱하는 함수
+func parseCondition(expression string) (*condition, error) {
+	inQuote := false
+	for i := 0; i < len(expression); i++ {
+		switch c := expression[i]; {
+		case c == '\\':
+			i++
+			continue
+		case c == '"':
+			inQuote = !inQuote
+			continue
+		case inQuote:
+			continue
+		}
+		for _, operator := range conditionOperators {
+			if !strings.HasPrefix(expression[i:], operator) {
+				continue
+			}
+			path, err := parsePath(strings.TrimSpace(expression[:i]))
+			if err != nil {
+				return nil, err
+			}
+			value, err := parseLiteral(strings.TrimSpace(expression[i+len(operator):]))
+			if err != nil {
+				return nil, err
+			}
+			return &condition{path: path, operator: operator, value: value}, nil
+		}
+	}
+	path, err := parsePath(strings.TrimSpace(expression))
+	if err != nil {
+		return nil, err
+	}
+	return &condition{path: path}, nil
+}
+
+// parseLiteral 조건의 값("문자열", 숫자, true, false, null)을 파싱하는 함수
+func parseLiteral(literal string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(literal, "\""):
+		value, err := strconv.Unquote(literal)
+		if err != nil {
+			return nil, fmt.Errorf("%w, invalid string literal, literal=%s", ErrInvalidPath, literal)
+		}
+		return value, nil
+	case literal == "true":
+		return true, nil
+	case literal == "false":
+		return false, nil
+	case literal == "null":
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w, invalid literal, literal=%s", ErrInvalidPath, literal)
+	}
+	return value, nil
+}
+
+// walk 경로 구간을 순서대로 따라가며 값을 찾는 함수
+func walk(data interface{}, segments []segment) (interface{}, error) {
+	for i, s := range segments {
+		value := indirect(reflect.ValueOf(data))
+		if !value.IsValid() {
+			return nil, &PathError{Segment: s.raw, Err: ErrPathNotFound}
+		}
+
+		switch s.kind {
+		case wildcardSegment:
+			if i == len(segments)-1 {
+				switch value.Kind() {
+				case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+					return value.Len(), nil
+				}
+				return nil, &PathError{Segment: s.raw, Err: ErrPathNotFound}
+			}
+			if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+				return nil, &PathError{Segment: s.raw, Err: ErrPathNotFound}
+			}
+			// 나머지 경로가 없는 원소는 결과에서 제외
+			results := make([]interface{}, 0, value.Len())
+			for j := 0; j < value.Len(); j++ {
+				if result, err := walk(value.Index(j).Interface(), segments[i+1:]); err == nil {
+					results = append(results, result)
+				} else if errors.Is(err, ErrInvalidPath) {
+					return nil, err
+				}
+			}
+			return results, nil
+		case filterSegment:
+			if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+				return nil, &PathError{Segment: s.raw, Err: ErrPathNotFound}
+			}
+			results := make([]interface{}, 0)
+			for j := 0; j < value.Len(); j++ {
+				element := value.Index(j).Interface()
+				if !s.condition.match(element) {
+					continue
+				}
+				result, err := walk(element, segments[i+1:])
+				if err != nil {
+					if !s.all {
+						return nil, err
+					}
+					continue
+				}
+				if !s.all {
+					return result, nil
+				}
+				results = append(results, result)
+			}
+			if !s.all {
+				return nil, &PathError{Segment: s.raw, Err: ErrPathNotFound}
+			}
+			return results, nil
+		}
+
+		next, ok := child(value, s.key)
+		if !ok {
+			return nil, &PathError{Segment: s.raw, Err: ErrPathNotFound}
+		}
+		data = next
+	}
+	return data, nil
+}
+
+// child 값의 종류에 따라 키(맵의 키, 구조체의 필드, 배열과 문자열의 인덱스)에 해당하는 하위 값을 반환하는 함수
+func child(value reflect.Value, key string) (interface{}, bool) {
+	switch value.Kind() {
+	case reflect.Map:
+		mapKey, ok := convertMapKey(key, value.Type().Key())
+		if !ok {
+			return nil, false
+		}
+		element := value.MapIndex(mapKey)
+		if !element.IsValid() {
+			return nil, false
+		}
+		return element.Interface(), true
+	case reflect.Slice, reflect.Array:
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= value.Len() {
+			return nil, false
+		}
+		return value.Index(index).Interface(), true
+	case reflect.String:
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= value.Len() {
+			return nil, false
+		}
+		return value.String()[index : index+1], true
+	case reflect.Struct:
+		field, ok := structField(value, key)
+		if !ok || !field.CanInterface() {
+			return nil, false
+		}
+		return field.Interface(), true
+	}
+	return nil, false
+}
+
+// convertMapKey 경로의 키를 맵의 키 타입으로 변환하는 함수
+func convertMapKey(key string, keyType reflect.Type) (reflect.Value, bool) {
+	switch keyType.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(key).Convert(keyType), true
+	case reflect.Interface:
+		if keyType.NumMethod() == 0 {
+			return reflect.ValueOf(key), true
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if number, err := strconv.ParseInt(key, 10, 64); err == nil {
+			return reflect.ValueOf(number).Convert(keyType), true
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if number, err := strconv.ParseUint(key, 10, 64); err == nil {
+			return reflect.ValueOf(number).Convert(keyType), true
+		}
+	case reflect.Float32, reflect.Float64:
+		if number, err := strconv.ParseFloat(key, 64); err == nil {
+			return reflect.ValueOf(number).Convert(keyType), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// structField 필드 이름 또는 json 태그 이름이 키와 같은 구조체 필드를 반환하는 함수
+func structField(value reflect.Value, key string) (reflect.Value, bool) {
+	if field := value.FieldByName(key); field.IsValid() {
+		return field, true
+	}
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		if name := strings.Split(valueType.Field(i).Tag.Get("json"), ",")[0]; name != "" && name == key {
+			return value.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// indirect 포인터와 인터페이스를 따라가 실제 값을 반환하는 함수(nil 이면 유효하지 않은 값)
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+// match 원소가 조건을 만족하는지 확인하는 함수
+func (c *condition) match(element interface{}) bool {
+	value, err := walk(element, c.path)
+	if err != nil {
+		return false
+	}
+	if c.operator == "" {
+		return true
+	}
+
+	switch expected := c.value.(type) {
+	case nil:
+		isNil := !indirect(reflect.ValueOf(value)).IsValid()
+		return (c.operator == "==" && isNil) || (c.operator == "!=" && !isNil)
+	case bool:
+		actual, ok := indirect(reflect.ValueOf(value)).Interface().(bool)
+		return ok && ((c.operator == "==" && actual == expected) || (c.operator == "!=" && actual != expected))
+	case float64:
+		actual, ok := toFloat(value)
+		if !ok {
+			return false
+		}
+		return compare(c.operator, actual-expected)
+	case string:
+		actual := toString(value)
+		if c.operator == "%" {
+			return globMatch(expected, actual)
+		}
+		return compare(c.operator, float64(strings.Compare(actual, expected)))
+	}
+	return false
+}
+
+// compare 비교 결과(차이값)가 연산자를 만족하는지 확인하는 함수
+func compare(operator string, difference float64) bool {
+	switch operator {
+	case "==":
+		return difference == 0
+	case "!=":
+		return difference != 0
+	case "<":
+		return difference < 0
+	case "<=":
+		return difference <= 0
+	case ">":
+		return difference > 0
+	case ">=":
+		return difference >= 0
+	}
+	return false
+}
+
+// globMatch * (임의의 문자열)와 ? (임의의 문자 하나)를 사용하는 패턴과 문자열이 일치하는지 확인하는 함수
+func globMatch(pattern string, value string) bool {
+	expression := regexp.QuoteMeta(pattern)
+	expression = strings.ReplaceAll(expression, `\*`, ".*")
+	expression = strings.ReplaceAll(expression, `\?`, ".")
+	matched, _ := regexp.MatchString("^(?s:"+expression+")$", value)
+	return matched
+}
+
+// toString 값을 문자열로 변환하는 함수
+func toString(value interface{}) string {
+	v := indirect(reflect.ValueOf(value))
+	if !v.IsValid() {
+		return ""
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	if stringer, ok := value.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+// toFloat 숫자 또는 숫자 문자열을 실수로 변환하는 함수
+func toFloat(value interface{}) (float64, bool) {
+	v := indirect(reflect.ValueOf(value))
+	if !v.IsValid() {
+		return 0, false
+	}
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.String:
+		float, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64)
+		return float, err == nil
+	case reflect.Bool:
+		if v.Bool() {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,123 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const queryResult = `{"status":"success","data":{"resultType":"vector","result":[
+	{"metric":{"namespace":"openshift-monitoring","app.kubernetes.io/name":"prometheus"},"value":[1654736096.03,"1.261144727299423"]},
+	{"metric":{"namespace":"openshift-etcd"},"value":[1654736096.03,"0.35481809519734603"]},
+	{"metric":{"namespace":"neis"},"value":[1654736096.03,"0.09351117828365008"]}]}}`
+
+type pathMetric struct {
+	Label  string            `json:"label"`
+	Labels map[string]string `json:"labels"`
+	Values []float64
+	Next   *pathMetric
+	hidden string
+}
+
+func TestGetE(t *testing.T) {
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(queryResult), &response); err != nil {
+		t.Fatal(err)
+	}
+	metric := &pathMetric{
+		Label:  "CPU",
+		Labels: map[string]string{"node": "worker1", "topology.kubernetes.io/zone": "a"},
+		Values: []float64{1.5, 2.5},
+		Next:   &pathMetric{Label: "MEMORY"},
+		hidden: "hidden",
+	}
+
+	tests := []struct {
+		name     string
+		data     interface{}
+		path     string
+		expected interface{}
+	}{
+		{"key and index", response, "data.result.0.value.1", "1.261144727299423"},
+		{"array length", response, "data.result.#", 3},
+		{"wildcard", response, "data.result.#.metric.namespace", []interface{}{"openshift-monitoring", "openshift-etcd", "neis"}},
+		{"wildcard skips missing", response, "data.result.#.metric.app\\.kubernetes\\.io/name", []interface{}{"prometheus"}},
+		{"escaped dot", response, "data.result.0.metric.app\\.kubernetes\\.io/name", "prometheus"},
+		{"filter first", response, `data.result.#(metric.namespace=="neis").value.1`, "0.09351117828365008"},
+		{"filter all by number", response, `data.result.#(value.1>0.3)#.metric.namespace`, []interface{}{"openshift-monitoring", "openshift-etcd"}},
+		{"filter glob", response, `data.result.#(metric.namespace%"openshift-*")#.metric.namespace`, []interface{}{"openshift-monitoring", "openshift-etcd"}},
+		{"filter exists", response, `data.result.#(metric.app\.kubernetes\.io/name)#.metric.namespace`, []interface{}{"openshift-monitoring"}},
+		{"struct field", metric, "Label", "CPU"},
+		{"struct json tag", metric, "labels.node", "worker1"},
+		{"map of strings with escaped key", metric, "Labels.topology\\.kubernetes\\.io/zone", "a"},
+		{"typed slice", metric, "Values.1", 2.5},
+		{"pointer", metric, "Next.label", "MEMORY"},
+		{"map with int keys", map[int]string{1: "one"}, "1", "one"},
+		{"string index", "abc", "1", "b"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := GetE(test.data, test.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(value, test.expected) {
+				t.Errorf("expected %#v, got %#v", test.expected, value)
+			}
+		})
+	}
+
+	errorTests := []struct {
+		name string
+		data interface{}
+		path string
+		err  error
+	}{
+		{"missing key", response, "data.missing", ErrPathNotFound},
+		{"index out of range", response, "data.result.3", ErrPathNotFound},
+		{"no filter match", response, `data.result.#(metric.namespace=="default")`, ErrPathNotFound},
+		{"unexported field", metric, "hidden", ErrPathNotFound},
+		{"nil pointer", metric, "Next.Next.Label", ErrPathNotFound},
+		{"unclosed filter", response, `data.result.#(metric.namespace=="neis"`, ErrInvalidPath},
+		{"invalid literal", response, `data.result.#(metric.namespace==neis)`, ErrInvalidPath},
+	}
+	for _, test := range errorTests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := GetE(test.data, test.path)
+			if !errors.Is(err, test.err) {
+				t.Errorf("expected %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	var response map[string]interface{}
+	_ = json.Unmarshal([]byte(queryResult), &response)
+
+	if value := Get(response, "data.resultType"); value != "vector" {
+		t.Errorf("expected vector, got %v", value)
+	}
+	if value := Get(response, "data.result.0.metric.missing", "fallback"); value != "fallback" {
+		t.Errorf("expected fallback for missing path, got %v", value)
+	}
+	if value := Get(response, "data.resultType.missing", "fallback"); value != "fallback" {
+		t.Errorf("expected fallback for path through a string, got %v", value)
+	}
+	if value := Get(response); value != nil {
+		t.Errorf("expected nil without path, got %v", value)
+	}
+	if value := GetString(response, "data.result.0.value.0"); value != "1654736096.03" {
+		t.Errorf("expected formatted number, got %q", value)
+	}
+	if value := GetFloat(response, "data.result.2.value.1"); value != 0.09351117828365008 {
+		t.Errorf("expected parsed number, got %v", value)
+	}
+	if value := GetSlice(response, "data.result.#.value.1"); len(value) != 3 {
+		t.Errorf("expected 3 values, got %v", value)
+	}
+	if value := GetSlice(response, "status"); !reflect.DeepEqual(value, []interface{}{"success"}) {
+		t.Errorf("expected single element slice, got %v", value)
+	}
+}
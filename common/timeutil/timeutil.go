@@ -0,0 +1,242 @@
+// Package timeutil 타임존을 고려한 시간 파싱, 스텝 정렬, 포맷 헬퍼
+// 범위 조회 파라미터(start, end, step)를 사용자의 IANA 타임존 기준으로 해석할 때 사용한다.
+package timeutil
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTime 시간, 기간 문자열을 해석할 수 없는 경우의 에러
+var ErrInvalidTime = errors.New("invalid time")
+
+// unixMillisThreshold 이 값 이상의 숫자는 밀리초 단위 unix timestamp 로 해석(초 단위로는 5138 년 이후)
+const unixMillisThreshold = 1e11
+
+var (
+	durationPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)(ms|s|m|h|d|w|y)`)
+	relativePattern = regexp.MustCompile(`^(?i:(now|today|yesterday|tomorrow|start of (?:day|week|month|year)))?((?:\s*[+-]\s*(?:\d+(?:\.\d+)?(?:ms|s|m|h|d|w|M|y))+)*)$`)
+	offsetPattern   = regexp.MustCompile(`([+-])\s*((?:\d+(?:\.\d+)?(?:ms|s|m|h|d|w|M|y))+)`)
+	amountPattern   = regexp.MustCompile(`(\d+(?:\.\d+)?)(ms|s|m|h|d|w|M|y)`)
+	localLayouts    = []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"}
+)
+
+// LoadLocation IANA 타임존 이름(Asia/Seoul), UTC, 고정 오프셋(+09:00)을 *time.Location 으로 변환하는 함수(빈 문자열은 time.Local)
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	if strings.HasPrefix(name, "+") || strings.HasPrefix(name, "-") {
+		offset, err := time.Parse("-07:00", name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load location, tz=%s, err=%w", name, ErrInvalidTime)
+		}
+		_, seconds := offset.Zone()
+		return time.FixedZone(name, seconds), nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load location, tz=%s, err=%s", name, err)
+	}
+	return location, nil
+}
+
+// Parse 시간 문자열을 타임존(location) 기준으로 해석하는 함수
+// 지원 형식: unix 초(1658970600, 1658970600.5), unix 밀리초(1658970600000), RFC3339, 타임존이 없는 날짜/시간(2022-07-28 10:00),
+// 상대 표현(now-1h, today, yesterday+9h, start of week, start of month-1M)
+/* Parse("today", now, seoul)          => 오늘 00:00 (Asia/Seoul)
+ * Parse("now-1h30m", now, seoul)      => now 에서 1시간 30분 전
+ * Parse("start of week", now, seoul)  => 이번 주 월요일 00:00 (Asia/Seoul)
+ */
+func Parse(value string, now time.Time, location *time.Location) (time.Time, error) {
+	if location == nil {
+		location = time.Local
+	}
+	text := strings.TrimSpace(value)
+	if text == "" {
+		return time.Time{}, fmt.Errorf("failed to parse time, err=%w, value is empty", ErrInvalidTime)
+	}
+
+	if seconds, err := strconv.ParseFloat(text, 64); err == nil && !math.IsNaN(seconds) && !math.IsInf(seconds, 0) {
+		if math.Abs(seconds) >= unixMillisThreshold {
+			seconds /= 1000
+		}
+		whole, fraction := math.Modf(seconds)
+		return time.Unix(int64(whole), int64(fraction*float64(time.Second))).In(location), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, text); err == nil {
+		return t.In(location), nil
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, text, location); err == nil {
+			return t, nil
+		}
+	}
+	return parseRelative(text, now.In(location))
+}
+
+// parseRelative 기준 표현(now, today, start of week 등)과 오프셋(-1h, +2d, -1M)으로 된 상대 시간을 해석하는 함수
+func parseRelative(text string, now time.Time) (time.Time, error) {
+	matches := relativePattern.FindStringSubmatch(text)
+	if matches == nil || (matches[1] == "" && matches[2] == "") {
+		return time.Time{}, fmt.Errorf("failed to parse time, err=%w, value=%s", ErrInvalidTime, text)
+	}
+
+	t := now
+	switch strings.ToLower(matches[1]) {
+	case "today", "start of day":
+		t = StartOfDay(now)
+	case "yesterday":
+		t = StartOfDay(now).AddDate(0, 0, -1)
+	case "tomorrow":
+		t = StartOfDay(now).AddDate(0, 0, 1)
+	case "start of week":
+		t = StartOfWeek(now)
+	case "start of month":
+		t = StartOfMonth(now)
+	case "start of year":
+		t = StartOfYear(now)
+	}
+
+	for _, offset := range offsetPattern.FindAllStringSubmatch(matches[2], -1) {
+		for _, component := range amountPattern.FindAllStringSubmatch(offset[2], -1) {
+			amount, _ := strconv.ParseFloat(component[1], 64)
+			if offset[1] == "-" {
+				amount = -amount
+			}
+			t = addOffset(t, amount, component[2])
+		}
+	}
+	return t, nil
+}
+
+// addOffset 시간에 단위(ms, s, m, h, d, w, M, y)의 양만큼 더하는 함수(M, y, d 는 달력 기준)
+func addOffset(t time.Time, amount float64, unit string) time.Time {
+	switch unit {
+	case "M":
+		return t.AddDate(0, int(amount), 0)
+	case "y":
+		return t.AddDate(int(amount), 0, 0)
+	case "d":
+		// 일 단위는 DST 와 관계없이 같은 시각을 유지
+		days, fraction := math.Modf(amount)
+		return t.AddDate(0, 0, int(days)).Add(time.Duration(fraction * float64(24*time.Hour)))
+	}
+	return t.Add(time.Duration(amount * float64(unitDuration(unit))))
+}
+
+// ParseDuration 기간 문자열을 파싱하는 함수(초 단위 숫자, Go duration, 프로메테우스 duration(1d, 1w, 1y, 1h30m) 지원)
+func ParseDuration(value string) (time.Duration, error) {
+	text := strings.TrimSpace(value)
+	if seconds, err := strconv.ParseFloat(text, 64); err == nil && !math.IsNaN(seconds) && !math.IsInf(seconds, 0) {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	if text != "" && durationPattern.ReplaceAllString(text, "") == "" {
+		var duration time.Duration
+		for _, match := range durationPattern.FindAllStringSubmatch(text, -1) {
+			amount, _ := strconv.ParseFloat(match[1], 64)
+			duration += time.Duration(amount * float64(unitDuration(match[2])))
+		}
+		return duration, nil
+	}
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse duration, err=%w, value=%s", ErrInvalidTime, value)
+	}
+	return duration, nil
+}
+
+func unitDuration(unit string) time.Duration {
+	switch unit {
+	case "ms":
+		return time.Millisecond
+	case "s":
+		return time.Second
+	case "m":
+		return time.Minute
+	case "h":
+		return time.Hour
+	case "d":
+		return 24 * time.Hour
+	case "w":
+		return 7 * 24 * time.Hour
+	case "y":
+		return 365 * 24 * time.Hour
+	}
+	return 0
+}
+
+// StartOfDay t 의 타임존 기준 자정
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek t 의 타임존 기준 이번 주 월요일 자정(ISO 8601)
+func StartOfWeek(t time.Time) time.Time {
+	weekday := (int(t.Weekday()) + 6) % 7 // 월요일 0 ~ 일요일 6
+	return StartOfDay(t).AddDate(0, 0, -weekday)
+}
+
+// StartOfMonth t 의 타임존 기준 이번 달 1일 자정
+func StartOfMonth(t time.Time) time.Time {
+	year, month, _ := t.Date()
+	return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfYear t 의 타임존 기준 올해 1월 1일 자정
+func StartOfYear(t time.Time) time.Time {
+	return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+}
+
+// epochMonday 주 단위 스텝 경계의 기준이 되는 1970-01-05(월요일) 자정의 unix 시간(나노초)
+const epochMonday = int64(4 * 24 * time.Hour)
+
+// AlignToStep t 를 t 의 타임존 기준 step 경계로 내림하는 함수
+// 1d 스텝은 해당 타임존의 자정, 1h 스텝은 정시에 맞춰지므로 사용자의 타임존에서 구간이 일관되게 나뉜다.
+// 1w 이상의 스텝은 이번 주 월요일 자정(StartOfWeek)부터 1970-01-05(월요일) 기준의 step 경계로 내림한다.
+func AlignToStep(t time.Time, step time.Duration) time.Time {
+	if step <= 0 {
+		return t
+	}
+	if step >= unitDuration("w") {
+		start := StartOfWeek(t)
+		_, offset := start.Zone()
+		local := start.UnixNano() + int64(offset)*int64(time.Second) - epochMonday
+		remainder := local % int64(step)
+		if remainder < 0 {
+			remainder += int64(step)
+		}
+		// 일광 절약 시간으로 하루가 24시간이 아닌 경우에도 자정에 맞춰지도록 날짜 단위로 내림
+		return start.AddDate(0, 0, -int(remainder/int64(24*time.Hour)))
+	}
+	_, offset := t.Zone()
+	local := t.UnixNano() + int64(offset)*int64(time.Second)
+	remainder := local % int64(step)
+	if remainder < 0 {
+		remainder += int64(step)
+	}
+	return t.Add(-time.Duration(remainder))
+}
+
+// FormatUnix unix timestamp(초)를 타임존 기준 layout 으로 포맷하는 함수(layout 이 빈 문자열이면 RFC3339)
+func FormatUnix(seconds float64, location *time.Location, layout string) string {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	if location == nil {
+		location = time.Local
+	}
+	whole, fraction := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(fraction*float64(time.Second))).In(location).Format(layout)
+}
+
+// UnixString 프로메테우스 API 파라미터 형식의 unix timestamp(초) 문자열
+func UnixString(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
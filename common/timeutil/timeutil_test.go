@@ -0,0 +1,108 @@
+package timeutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	seoul, err := LoadLocation("Asia/Seoul")
+	if err != nil {
+		t.Skipf("tzdata is not available, err=%s", err)
+	}
+	// 2022-07-28(목) 10:30:00 KST
+	now := time.Date(2022, 7, 28, 10, 30, 0, 0, seoul)
+
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"1658970600", time.Unix(1658970600, 0)},
+		{"1658970600000", time.Unix(1658970600, 0)},
+		{"1658970600.5", time.Unix(1658970600, int64(500*time.Millisecond))},
+		{"2022-07-28T01:00:00Z", time.Date(2022, 7, 28, 1, 0, 0, 0, time.UTC)},
+		{"2022-07-28 09:00", time.Date(2022, 7, 28, 9, 0, 0, 0, seoul)},
+		{"2022-07-28", time.Date(2022, 7, 28, 0, 0, 0, 0, seoul)},
+		{"now", now},
+		{"now-1h30m", now.Add(-90 * time.Minute)},
+		{"-15m", now.Add(-15 * time.Minute)},
+		{"today", time.Date(2022, 7, 28, 0, 0, 0, 0, seoul)},
+		{"Yesterday+9h", time.Date(2022, 7, 27, 9, 0, 0, 0, seoul)},
+		{"start of week", time.Date(2022, 7, 25, 0, 0, 0, 0, seoul)},
+		{"start of month-1M", time.Date(2022, 6, 1, 0, 0, 0, 0, seoul)},
+		{"start of year", time.Date(2022, 1, 1, 0, 0, 0, 0, seoul)},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			parsed, err := Parse(test.value, now, seoul)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !parsed.Equal(test.expected) {
+				t.Errorf("expected %s, got %s", test.expected, parsed)
+			}
+		})
+	}
+
+	for _, value := range []string{"", "later", "now-1x", "start of decade"} {
+		if _, err := Parse(value, now, seoul); !errors.Is(err, ErrInvalidTime) {
+			t.Errorf("%q: expected invalid time, got %v", value, err)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"120":   2 * time.Minute,
+		"1d":    24 * time.Hour,
+		"1w2d":  9 * 24 * time.Hour,
+		"1h30m": 90 * time.Minute,
+		"1.5h":  90 * time.Minute,
+		"500ms": 500 * time.Millisecond,
+		"10us":  10 * time.Microsecond,
+	}
+	for value, expected := range tests {
+		if duration, err := ParseDuration(value); err != nil || duration != expected {
+			t.Errorf("%s: expected %s, got %s (%v)", value, expected, duration, err)
+		}
+	}
+	if _, err := ParseDuration("week"); !errors.Is(err, ErrInvalidTime) {
+		t.Errorf("expected invalid time, got %v", err)
+	}
+}
+
+func TestAlignToStep(t *testing.T) {
+	seoul, err := LoadLocation("Asia/Seoul")
+	if err != nil {
+		t.Skipf("tzdata is not available, err=%s", err)
+	}
+	value := time.Date(2022, 7, 28, 10, 37, 12, 0, seoul)
+	if aligned := AlignToStep(value, 24*time.Hour); !aligned.Equal(time.Date(2022, 7, 28, 0, 0, 0, 0, seoul)) {
+		t.Errorf("expected local midnight, got %s", aligned)
+	}
+	if aligned := AlignToStep(value, 15*time.Minute); !aligned.Equal(time.Date(2022, 7, 28, 10, 30, 0, 0, seoul)) {
+		t.Errorf("expected 10:30, got %s", aligned)
+	}
+
+	// 주 단위 스텝은 epoch(목요일)가 아닌 월요일 자정 기준
+	value = time.Date(2026, 10, 21, 15, 4, 5, 0, seoul)
+	if aligned := AlignToStep(value, 7*24*time.Hour); !aligned.Equal(time.Date(2026, 10, 19, 0, 0, 0, 0, seoul)) {
+		t.Errorf("expected Monday 2026-10-19, got %s", aligned)
+	}
+	if aligned := AlignToStep(time.Date(2026, 10, 19, 0, 0, 0, 0, seoul), 7*24*time.Hour); !aligned.Equal(time.Date(2026, 10, 19, 0, 0, 0, 0, seoul)) {
+		t.Errorf("expected Monday 2026-10-19, got %s", aligned)
+	}
+	if aligned := AlignToStep(value, 14*24*time.Hour); !aligned.Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, seoul)) {
+		t.Errorf("expected Monday 2026-10-12, got %s", aligned)
+	}
+
+	india, _ := LoadLocation("+05:30")
+	if aligned := AlignToStep(value.In(india), time.Hour); aligned.In(india).Minute() != 0 {
+		t.Errorf("expected aligned to the hour in +05:30, got %s", aligned.In(india))
+	}
+
+	if formatted := FormatUnix(1658970600, seoul, ""); formatted != "2022-07-28T10:10:00+09:00" {
+		t.Errorf("unexpected format %s", formatted)
+	}
+}
@@ -0,0 +1,137 @@
+package common
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-practice/common/collection"
+)
+
+// UnitPolicy 여러 값(시계열, 여러 시계열, Top N 표)에 공통으로 사용할 단위를 고르는 방식
+type UnitPolicy string
+
+const (
+	UnitPolicyMax       = UnitPolicy("max")    // 절대값이 가장 큰 값에 맞는 단위
+	UnitPolicyMedian    = UnitPolicy("median") // 0 이 아닌 값의 절대값 중앙값에 맞는 단위
+	UnitPolicyMinDigits = UnitPolicy("digits") // 0 이 아닌 값이 0 으로 반올림되지 않는 단위 중 표시되는 숫자 자릿수의 합이 가장 작은 단위
+)
+
+// ParseUnitPolicy 문자열을 UnitPolicy 로 변환하는 함수(빈 문자열은 max)
+func ParseUnitPolicy(policy string) (UnitPolicy, error) {
+	switch UnitPolicy(policy) {
+	case "":
+		return UnitPolicyMax, nil
+	case UnitPolicyMax, UnitPolicyMedian, UnitPolicyMinDigits:
+		return UnitPolicy(policy), nil
+	}
+	return "", fmt.Errorf("failed to parse unit policy, policy=%s, err=must be one of max, median, digits", policy)
+}
+
+// ChooseUnit 여러 값의 목록에서 정책에 따라 하나의 공통 단위를 반환하는 함수
+// options.InitialUnit 은 값의 단위, options.Precision 은 digits 정책에서 반올림 자릿수로 사용한다.
+/* ChooseUnit(BinaryBytes, UnitPolicyMax, &HumanizeOptions{Precision: 2}, []float64{1536, 3 << 30})        => GiB
+ * ChooseUnit(BinaryBytes, UnitPolicyMinDigits, &HumanizeOptions{Precision: 2}, []float64{1536, 2048000}) => KiB
+ */
+func ChooseUnit(unitTypeKey UnitTypeKey, policy UnitPolicy, options *HumanizeOptions, values ...[]float64) string {
+	unitType, _ := LookupUnitType(unitTypeKey)
+	magnitudes := make([]float64, 0)
+	for _, series := range values {
+		for _, value := range series {
+			if value != 0 && !math.IsNaN(value) && !math.IsInf(value, 0) {
+				magnitudes = append(magnitudes, math.Abs(value))
+			}
+		}
+	}
+	humanizeOptions := &HumanizeOptions{InitialUnit: options.InitialUnit}
+	if len(magnitudes) == 0 {
+		return Humanize(0, unitTypeKey, humanizeOptions).Unit
+	}
+	sort.Float64s(magnitudes)
+
+	switch policy {
+	case UnitPolicyMedian:
+		return Humanize(magnitudes[len(magnitudes)/2], unitTypeKey, humanizeOptions).Unit
+	case UnitPolicyMinDigits:
+		return chooseMinDigitsUnit(unitType, magnitudes, options)
+	}
+	return Humanize(magnitudes[len(magnitudes)-1], unitTypeKey, humanizeOptions).Unit
+}
+
+// chooseMinDigitsUnit 값이 0 으로 반올림되지 않는 단위 중 표시되는 숫자 자릿수의 합이 가장 작은 단위를 반환
+func chooseMinDigitsUnit(unitType UnitType, magnitudes []float64, options *HumanizeOptions) string {
+	initialIndex := 0
+	if options.InitialUnit != "" {
+		if initialIndex = collection.IndexOf(unitType.Units, options.InitialUnit); initialIndex == -1 {
+			return ""
+		}
+	}
+	if initialIndex >= len(unitType.Units) {
+		return ""
+	}
+
+	bestUnit, bestDigits := unitType.Units[initialIndex], -1
+	for index := initialIndex; index < len(unitType.Units); index++ {
+		scale := unitType.scale(initialIndex, index)
+		digits := 0
+		for _, magnitude := range magnitudes {
+			rounded := RoundFloat(magnitude/scale, options.Precision)
+			if rounded == 0 {
+				digits = -1
+				break
+			}
+			digits += len(strings.Replace(strconv.FormatFloat(rounded, 'f', -1, 64), ".", "", 1))
+		}
+		if digits == -1 {
+			break
+		}
+		if bestDigits == -1 || digits <= bestDigits {
+			bestUnit, bestDigits = unitType.Units[index], digits
+		}
+	}
+	return bestUnit
+}
+
+// HumanizeCommon 여러 값의 목록을 정책에 따라 고른 공통 단위로 변환하여 단위와 변환된 값의 목록을 반환하는 함수
+// 하나의 차트 축, 표에서 MiB 와 GiB 가 섞이지 않도록 할 때 사용한다.
+func HumanizeCommon(unitTypeKey UnitTypeKey, policy UnitPolicy, options *HumanizeOptions, values ...[]float64) (string, [][]float64) {
+	unit := ChooseUnit(unitTypeKey, policy, options, values...)
+	humanizeOptions := *options
+	humanizeOptions.PreferredUnit = unit
+
+	converted := make([][]float64, len(values))
+	for i, series := range values {
+		converted[i] = make([]float64, len(series))
+		for j, value := range series {
+			converted[i][j] = Humanize(value, unitTypeKey, &humanizeOptions).Value
+		}
+	}
+	return unit, converted
+}
+
+// toFloatSlice float64, []float64, [][]float64, []interface{} 의 값을 []float64 로 변환하는 함수
+func toFloatSlice(values interface{}) []float64 {
+	switch v := values.(type) {
+	case float64:
+		return []float64{v}
+	case []float64:
+		return v
+	case [][]float64:
+		result := make([]float64, 0)
+		for _, series := range v {
+			result = append(result, series...)
+		}
+		return result
+	case []interface{}:
+		result := make([]float64, 0, len(v))
+		for _, value := range v {
+			if float, ok := toFloat(value); ok {
+				result = append(result, float)
+			}
+		}
+		return result
+	}
+	return nil
+}
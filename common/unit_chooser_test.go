@@ -0,0 +1,50 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChooseUnit(t *testing.T) {
+	values := []float64{1536, 2048000, 0, 3 << 20}
+	tests := []struct {
+		policy   UnitPolicy
+		expected string
+	}{
+		{UnitPolicyMax, "MiB"},
+		{UnitPolicyMedian, "MiB"},
+		{UnitPolicyMinDigits, "KiB"},
+	}
+	for _, test := range tests {
+		t.Run(string(test.policy), func(t *testing.T) {
+			if unit := ChooseUnit(BinaryBytes, test.policy, &HumanizeOptions{Precision: 2}, values); unit != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, unit)
+			}
+		})
+	}
+
+	if unit := ChooseUnit(BinaryBytes, UnitPolicyMedian, &HumanizeOptions{}, []float64{10, 20}, []float64{5 << 30}); unit != "B" {
+		t.Errorf("expected median across series to be B, got %s", unit)
+	}
+	if unit := ChooseUnit(Seconds, UnitPolicyMax, &HumanizeOptions{InitialUnit: "s"}); unit != "s" {
+		t.Errorf("expected initial unit without values, got %s", unit)
+	}
+	if _, err := ParseUnitPolicy("mean"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+	if unit := FindMaxUnitByValues(DecimalBytes, [][]float64{{1000}, {2e9}}); unit != "GB" {
+		t.Errorf("expected GB, got %s", unit)
+	}
+}
+
+func TestHumanizeCommon(t *testing.T) {
+	unit, converted := HumanizeCommon(BinaryBytes, UnitPolicyMax, &HumanizeOptions{Precision: 2},
+		[]float64{512 << 20, 1 << 30}, []float64{1 << 20})
+	if unit != "GiB" {
+		t.Errorf("expected GiB, got %s", unit)
+	}
+	expected := [][]float64{{0.5, 1}, {0.00098}}
+	if !reflect.DeepEqual(converted, expected) {
+		t.Errorf("expected %v, got %v", expected, converted)
+	}
+}
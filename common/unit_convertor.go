@@ -5,6 +5,9 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"sync"
+
+	"go-practice/common/collection"
 )
 
 const (
@@ -18,6 +21,9 @@ const (
 	BinaryBytesWithoutB  = UnitTypeKey("BinaryBytesWithoutB")
 	SI                   = UnitTypeKey("SI")
 	DecimalBytesPerSec   = UnitTypeKey("DecimalBytesPerSec")
+	DecimalBitsPerSec    = UnitTypeKey("DecimalBitsPerSec")
+	BinaryBytesPerSec    = UnitTypeKey("BinaryBytesPerSec")
+	OpsPerSec            = UnitTypeKey("OpsPerSec")
 	PacketsPerSec        = UnitTypeKey("PacketsPerSec")
 	Seconds              = UnitTypeKey("Seconds")
 )
@@ -29,11 +35,43 @@ type UnitTypeKey string
 type UnitType struct {
 	Units   []string
 	Divisor float64
+	Factors []float64 // 연속된 단위 사이의 배수(예: s→min 60), 없으면 모든 단위 사이에 Divisor 를 사용
+}
+
+// factor i 번째 단위와 i+1 번째 단위 사이의 배수를 반환
+func (u UnitType) factor(i int) float64 {
+	if i < len(u.Factors) {
+		return u.Factors[i]
+	}
+	return u.Divisor
+}
+
+// scale from 번째 단위를 기준으로 to 번째 단위 1 의 값을 반환(to 가 from 보다 작으면 역수)
+func (u UnitType) scale(from int, to int) float64 {
+	scale := 1.0
+	for i := from; i < to; i++ {
+		scale *= u.factor(i)
+	}
+	for i := to; i < from; i++ {
+		scale /= u.factor(i)
+	}
+	return scale
+}
+
+// CompositeUnitType 단위 타입의 각 단위에 접미사를 붙인 합성 단위 타입을 반환(예: BinaryBytes + "/s" => B/s, KiB/s, ...)
+func CompositeUnitType(unitTypeKey UnitTypeKey, suffix string) UnitType {
+	base, _ := LookupUnitType(unitTypeKey)
+	units := make([]string, len(base.Units))
+	for i, unit := range base.Units {
+		units[i] = unit + suffix
+	}
+	return UnitType{Units: units, Divisor: base.Divisor, Factors: base.Factors}
 }
 
-// UnitTypes 단위 타입 키에 따른 단위 타입 정의 상수
+// unitTypes 단위 타입 키에 따른 단위 타입 정의 상수(unitTypesMutex 로 보호)
+// 실행 중에 단위 타입을 추가하는 경우 RegisterUnitType, 조회하는 경우 LookupUnitType 또는 UnitTypes 를 사용한다.
 var (
-	UnitTypes = map[UnitTypeKey]UnitType{
+	unitTypes = map[UnitTypeKey]UnitType{
 		Count: {
 			Units:   []string{""},
 			Divisor: 1,
@@ -59,7 +97,7 @@ var (
 			Divisor: 1000,
 		},
 		BinaryBytes: {
-			Units:   []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"},
+			Units:   []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"},
 			Divisor: 1024,
 		},
 		BinaryBytesWithoutB: {
@@ -74,27 +112,106 @@ var (
 			Units:   []string{"Bps", "KBps", "MBps", "GBps", "TBps", "PBps", "EBps"},
 			Divisor: 1000,
 		},
+		DecimalBitsPerSec: {
+			Units:   []string{"bps", "kbps", "Mbps", "Gbps", "Tbps", "Pbps", "Ebps"},
+			Divisor: 1000,
+		},
+		BinaryBytesPerSec: {
+			Units:   []string{"B/s", "KiB/s", "MiB/s", "GiB/s", "TiB/s", "PiB/s", "EiB/s"},
+			Divisor: 1024,
+		},
+		OpsPerSec: {
+			Units:   []string{"ops/s", "kops/s", "Mops/s", "Gops/s", "Tops/s"},
+			Divisor: 1000,
+		},
 		PacketsPerSec: {
-			Units:   []string{"pps", "kpps"},
+			Units:   []string{"pps", "kpps", "Mpps", "Gpps"},
 			Divisor: 1000,
 		},
 		Seconds: {
-			Units:   []string{"ns", "μs", "ms", "s"},
+			Units:   []string{"ns", "μs", "ms", "s", "min", "h", "d"},
 			Divisor: 1000,
+			Factors: []float64{1000, 1000, 1000, 60, 60, 24},
 		},
 	}
+	unitTypesMutex sync.RWMutex
 )
 
+// RegisterUnitType 단위 타입을 추가(또는 교체)하는 함수
+/* RegisterUnitType("Requests", UnitType{Units: []string{"req", "kreq"}, Divisor: 1000})
+ * RegisterUnitType("BitsPerMin", CompositeUnitType(SI, "bit/min"))
+ */
+func RegisterUnitType(unitTypeKey UnitTypeKey, unitType UnitType) error {
+	if unitTypeKey == "" || len(unitType.Units) == 0 {
+		return fmt.Errorf("failed to register unit type, unitTypeKey=%s, err=unit type key and units are required", unitTypeKey)
+	}
+	for i := 0; i < len(unitType.Units)-1; i++ {
+		if unitType.factor(i) <= 0 {
+			return fmt.Errorf("failed to register unit type, unitTypeKey=%s, err=divisor or factors must be positive", unitTypeKey)
+		}
+	}
+	for i, unit := range unitType.Units {
+		if collection.IndexOf(unitType.Units[:i], unit) != -1 {
+			return fmt.Errorf("failed to register unit type, unitTypeKey=%s, err=duplicated unit, unit=%s", unitTypeKey, unit)
+		}
+	}
+
+	unitTypesMutex.Lock()
+	defer unitTypesMutex.Unlock()
+	unitTypes[unitTypeKey] = unitType
+	return nil
+}
+
+// LookupUnitType 단위 타입 키에 해당하는 단위 타입을 반환하는 함수
+func LookupUnitType(unitTypeKey UnitTypeKey) (UnitType, bool) {
+	unitTypesMutex.RLock()
+	defer unitTypesMutex.RUnlock()
+	unitType, ok := unitTypes[unitTypeKey]
+	return unitType, ok
+}
+
+// UnitTypes 등록된 단위 타입 정의의 복사본을 반환하는 함수(읽기 전용)
+// 이전의 UnitTypes 변수를 직접 읽던 코드는 UnitTypes()[키] 또는 LookupUnitType 으로 옮긴다.
+func UnitTypes() map[UnitTypeKey]UnitType {
+	unitTypesMutex.RLock()
+	defer unitTypesMutex.RUnlock()
+	copied := make(map[UnitTypeKey]UnitType, len(unitTypes))
+	for key, unitType := range unitTypes {
+		copied[key] = UnitType{
+			Units:   append([]string(nil), unitType.Units...),
+			Divisor: unitType.Divisor,
+			Factors: append([]float64(nil), unitType.Factors...),
+		}
+	}
+	return copied
+}
+
 // HumanizeOptions Humanize 함수의 세 번째 인자의 타입으로 Humanize 함수에서 사용하는 옵션을 정의
 type HumanizeOptions struct {
-	Precision     uint
-	InitialUnit   string
-	PreferredUnit string
+	Precision          uint   // 소수점 자릿수
+	InitialUnit        string // 입력값의 단위(없으면 단위 타입의 첫 번째 단위)
+	PreferredUnit      string // 변환할 단위(없으면 값에 맞는 가장 큰 단위)
+	SignificantFigures uint   // 유효 숫자 자릿수(0 이 아니면 Precision 대신 사용)
+	KeepTrailingZeros  bool   // 문자열 변환 시 소수점 이하의 0 을 유지(예: 1.50)
+	Locale             string // 문자열 변환 시 소수점 구분자를 결정하는 로케일(예: ko-KR, de-DE)
 }
 
 type humanizeValue struct {
-	Unit  string
-	Value float64
+	Unit    string
+	Value   float64
+	options HumanizeOptions
+}
+
+// Number 변환된 값을 Number 로 반환(NaN, ±Inf 를 JSON 으로 직렬화할 때 사용)
+func (h *humanizeValue) Number() Number {
+	return NewNumber(h.Value)
+}
+
+// String 값과 단위를 붙인 문자열을 반환(ParseHumanized 로 다시 파싱 가능)
+/* Humanize(1536, BinaryBytes, &HumanizeOptions{Precision: 2, KeepTrailingZeros: true, Locale: "de-DE"}).String() => 1,50KiB
+ */
+func (h *humanizeValue) String() string {
+	return FormatNumber(h.Value, &h.options) + h.Unit
 }
 
 type convertedValue struct {
@@ -109,87 +226,98 @@ func shift(pToSlice *[]string) string {
 	return sValue
 }
 
-// indexOf string 배열에 대상값 인덱스 반환
-func indexOf(strings []string, val string) int {
-	for idx, v := range strings {
-		if v == val {
-			return idx
-		}
+// RoundFloat 인자로 받은 value 를 소수점 precision 자리에서 반올림(0.5 는 올림)하는 함수
+// 10진수 표기에서 반올림하므로 1.005 => 1.01 과 같이 표기대로 반올림되고, 큰 값에서도 오버플로가 발생하지 않는다.
+func RoundFloat(val float64, precision uint) float64 {
+	return RoundFloatMode(val, precision, RoundHalfUp)
+}
+
+// RoundFloatMode 인자로 받은 value 를 반올림 방식에 따라 소수점 precision 자리에서 반올림하는 함수(NaN, ±Inf 는 그대로 반환)
+func RoundFloatMode(val float64, precision uint, mode RoundingMode) float64 {
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return val
 	}
-	return -1
+	return NewNumber(val).Round(precision, mode).Value
 }
 
-// RoundFloat https://gosamples.dev/round-float/ 인자로 받은 value 를 소수점 precision 자리에서 반올림하는 함수
-func RoundFloat(val float64, precision uint) float64 {
-	ratio := math.Pow(10, float64(precision))
-	return math.Round(val*ratio) / ratio
+// RoundSignificant 인자로 받은 value 를 유효 숫자 figures 자리로 반올림하는 함수
+func RoundSignificant(val float64, figures uint) float64 {
+	if val == 0 || figures == 0 || math.IsNaN(val) || math.IsInf(val, 0) {
+		return val
+	}
+	value, _ := strconv.ParseFloat(strconv.FormatFloat(val, 'g', int(figures), 64), 64)
+	return value
 }
 
 // convertBaseValueToUnits value의 단위를 조정하는 함수
-func convertBaseValueToUnits(value float64, unitArray []string, divisor float64, initialUnit string, preferredUnit string) *convertedValue {
+func convertBaseValueToUnits(value float64, unitType UnitType, initialUnit string, preferredUnit string) *convertedValue {
 	var sliceIndex = 0
 	var unit = ""
 	if initialUnit != "" {
-		sliceIndex = indexOf(unitArray, initialUnit)
+		sliceIndex = collection.IndexOf(unitType.Units, initialUnit)
 	}
-	if sliceIndex != -1 {
-		units := unitArray[sliceIndex:]
+	// 단위가 없는 단위 타입(단위 타입 키가 "")은 값을 그대로 반환
+	if sliceIndex != -1 && sliceIndex < len(unitType.Units) {
+		units := unitType.Units[sliceIndex:]
 
-		unitIndex := indexOf(units, preferredUnit)
+		unitIndex := collection.IndexOf(units, preferredUnit)
 		if unitIndex != -1 {
-			return &convertedValue{value / math.Pow(divisor, float64(unitIndex)), preferredUnit}
+			return &convertedValue{value / unitType.scale(sliceIndex, sliceIndex+unitIndex), preferredUnit}
 		}
 
+		index := sliceIndex
 		unit = shift(&units)
-		for value >= divisor && len(units) > 0 {
-			value = value / divisor
+		for value >= unitType.factor(index) && len(units) > 0 {
+			value = value / unitType.factor(index)
 			unit = shift(&units)
+			index++
 		}
 	}
 	return &convertedValue{value, unit}
 }
 
 // Humanize 인자로 들어온 값을 단위 타입 키에 따라 변환된 humanizeValue 를 반환하는 함수
+// SignificantFigures 가 있으면 유효 숫자로, 없으면 Precision 자리의 소수점으로 반올림한다.
 func Humanize(value float64, unitTypeKey UnitTypeKey, options *HumanizeOptions) *humanizeValue {
-	types := UnitTypes[unitTypeKey]
+	types, _ := LookupUnitType(unitTypeKey)
+
+	convertedValue := convertBaseValueToUnits(value, types, options.InitialUnit, options.PreferredUnit)
 
-	convertedValue := convertBaseValueToUnits(value, types.Units, types.Divisor, options.InitialUnit, options.PreferredUnit)
+	if options.SignificantFigures > 0 {
+		return &humanizeValue{
+			convertedValue.unit,
+			RoundSignificant(convertedValue.value.(float64), options.SignificantFigures),
+			*options,
+		}
+	}
 
-	result := RoundFloat(convertedValue.value.(float64), options.Precision)
+	converted := convertedValue.value.(float64)
+	result := RoundFloat(converted, options.Precision)
 
-	if value != 0 && result == 0 {
+	// 반올림하여 0 이 되는 작은 값은 0 이 아닌 첫 자리의 다음 자리까지 유지
+	if converted != 0 && result == 0 {
 		var offset int
-		for i, v := range strings.Split(strconv.FormatFloat(value, 'f', -1, 64), ".")[1] {
+		for i, v := range strings.SplitN(strconv.FormatFloat(converted, 'f', -1, 64), ".", 2)[1] {
 			if string(v) != "0" {
 				offset = i + 2
 				break
 			}
 		}
 
-		result = RoundFloat(value, uint(offset))
+		result = RoundFloat(converted, uint(offset))
 	}
 
 	return &humanizeValue{
 		convertedValue.unit,
 		result,
+		*options,
 	}
 }
 
-// FindMaxUnitByValues 인자로 들어온 값(배열)의 최대 단위를 반환하는 함수
+// FindMaxUnitByValues 인자로 들어온 값(float64, []float64, [][]float64, []interface{})의 최대 단위를 반환하는 함수
+// 최대값 이외의 정책으로 단위를 고르는 경우 ChooseUnit 을 사용한다.
 func FindMaxUnitByValues(unitTypeKey UnitTypeKey, values interface{}) string {
-	var maxFloat64 float64 = 0
-	if _, ok := values.(float64); ok {
-		maxFloat64 = values.(float64)
-	}
-	if _, ok := values.([]float64); ok {
-		for _, float := range values.([]float64) {
-			if maxFloat64 < float {
-				maxFloat64 = float
-			}
-		}
-	}
-
-	return Humanize(maxFloat64, unitTypeKey, &HumanizeOptions{Precision: 2}).Unit
+	return ChooseUnit(unitTypeKey, UnitPolicyMax, &HumanizeOptions{Precision: 2}, toFloatSlice(values))
 }
 
 func main() {
@@ -202,7 +330,7 @@ func main() {
 	//number := 12.00
 	//fmt.Println(RoundFloat(number, 2))
 	//
-	fmt.Println(fmt.Sprintf("%v", Humanize(0.00002, DecimalBytes, &HumanizeOptions{2, "", ""})))
+	fmt.Println(fmt.Sprintf("%v", Humanize(0.00002, DecimalBytes, &HumanizeOptions{Precision: 2})))
 
 	value := 0.0
 
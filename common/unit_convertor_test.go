@@ -0,0 +1,111 @@
+package common
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestHumanize(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       float64
+		unitTypeKey UnitTypeKey
+		options     *HumanizeOptions
+		expected    string
+	}{
+		{"binary bytes", 1536, BinaryBytes, &HumanizeOptions{Precision: 2}, "1.5KiB"},
+		{"exbibytes", 3 * (1 << 60), BinaryBytes, &HumanizeOptions{Precision: 2}, "3EiB"},
+		{"bits per second", 3e8, DecimalBitsPerSec, &HumanizeOptions{Precision: 2}, "300Mbps"},
+		{"composite bytes per second", 2 * (1 << 20), BinaryBytesPerSec, &HumanizeOptions{Precision: 2}, "2MiB/s"},
+		{"ops per second", 1500, OpsPerSec, &HumanizeOptions{Precision: 1}, "1.5kops/s"},
+		{"million packets", 2.5e6, PacketsPerSec, &HumanizeOptions{Precision: 2}, "2.5Mpps"},
+		{"minutes", 90, Seconds, &HumanizeOptions{Precision: 2, InitialUnit: "s"}, "1.5min"},
+		{"days", 3 * 86400, Seconds, &HumanizeOptions{Precision: 2, InitialUnit: "s"}, "3d"},
+		{"milliseconds", 1.5e6, Seconds, &HumanizeOptions{Precision: 2}, "1.5ms"},
+		{"preferred hours", 1800, Seconds, &HumanizeOptions{Precision: 2, InitialUnit: "s", PreferredUnit: "h"}, "0.5h"},
+		{"significant figures", 123456, DecimalBytes, &HumanizeOptions{SignificantFigures: 2}, "120KB"},
+		{"trailing zeros", 1536, BinaryBytes, &HumanizeOptions{Precision: 2, KeepTrailingZeros: true}, "1.50KiB"},
+		{"significant trailing zeros", 2e6, DecimalBytes, &HumanizeOptions{SignificantFigures: 3, KeepTrailingZeros: true}, "2.00MB"},
+		{"locale", 1536, BinaryBytes, &HumanizeOptions{Precision: 2, Locale: "de_DE"}, "1,5KiB"},
+		{"locale region", 1536, BinaryBytes, &HumanizeOptions{Precision: 2, Locale: "de-CH"}, "1.5KiB"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if value := Humanize(test.value, test.unitTypeKey, test.options).String(); value != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, value)
+			}
+		})
+	}
+}
+
+func TestHumanizeLocaleRoundTrip(t *testing.T) {
+	options := &HumanizeOptions{Precision: 2, InitialUnit: "s", KeepTrailingZeros: true, Locale: "fr-FR"}
+	humanized := Humanize(5400, Seconds, options)
+	if humanized.String() != "1,50h" {
+		t.Fatalf("expected 1,50h, got %s", humanized)
+	}
+	value, err := ParseHumanized(humanized.String(), Seconds, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != 5400 {
+		t.Errorf("expected 5400, got %v", value)
+	}
+}
+
+func TestRegisterUnitType(t *testing.T) {
+	const requests = UnitTypeKey("TestRequests")
+	defer func() {
+		unitTypesMutex.Lock()
+		delete(unitTypes, requests)
+		delete(unitTypes, "TestRequestsPerMin")
+		unitTypesMutex.Unlock()
+	}()
+
+	if err := RegisterUnitType(requests, UnitType{Units: []string{"req", "kreq", "Mreq"}, Divisor: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterUnitType("TestRequestsPerMin", CompositeUnitType(requests, "/min")); err != nil {
+		t.Fatal(err)
+	}
+	if value := Humanize(2500, "TestRequestsPerMin", &HumanizeOptions{Precision: 2}).String(); value != "2.5kreq/min" {
+		t.Errorf("expected 2.5kreq/min, got %s", value)
+	}
+
+	invalid := []UnitType{
+		{},
+		{Units: []string{"a", "b"}},
+		{Units: []string{"a", "a"}, Divisor: 10},
+		{Units: []string{"a", "b", "c"}, Factors: []float64{10}},
+	}
+	for _, unitType := range invalid {
+		if err := RegisterUnitType("TestInvalid", unitType); err == nil {
+			t.Errorf("expected error for %v", unitType)
+		}
+	}
+
+	// UnitTypes 는 복사본을 반환하므로 수정해도 등록된 정의는 바뀌지 않는다
+	snapshot := UnitTypes()
+	if units := snapshot[requests].Units; len(units) != 3 || units[1] != "kreq" {
+		t.Errorf("unexpected snapshot units %v", units)
+	}
+	snapshot[requests].Units[1] = "changed"
+	delete(snapshot, BinaryBytes)
+	if unitType, _ := LookupUnitType(requests); unitType.Units[1] != "kreq" {
+		t.Errorf("expected registered units to be unchanged, got %v", unitType.Units)
+	}
+	if _, ok := LookupUnitType(BinaryBytes); !ok {
+		t.Error("expected BinaryBytes to stay registered")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = RegisterUnitType(requests, UnitType{Units: []string{"req", "kreq"}, Divisor: 1000})
+			Humanize(1000, requests, &HumanizeOptions{})
+		}()
+	}
+	wg.Wait()
+}
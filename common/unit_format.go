@@ -0,0 +1,77 @@
+package common
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// decimalSeparators 로케일(언어 또는 언어-지역)별 소수점 구분자, 없으면 "." 을 사용
+	decimalSeparators = map[string]string{
+		"cs": ",", "da": ",", "de": ",", "es": ",", "fi": ",", "fr": ",", "id": ",", "it": ",",
+		"nb": ",", "nl": ",", "pl": ",", "pt": ",", "ru": ",", "sv": ",", "tr": ",", "uk": ",",
+		"de-ch": ".",
+	}
+	decimalSeparatorsMutex sync.RWMutex
+)
+
+// RegisterDecimalSeparator 로케일의 소수점 구분자를 추가(또는 교체)하는 함수
+/* RegisterDecimalSeparator("ar", "٫")
+ */
+func RegisterDecimalSeparator(locale string, separator string) {
+	decimalSeparatorsMutex.Lock()
+	defer decimalSeparatorsMutex.Unlock()
+	decimalSeparators[normalizeLocale(locale)] = separator
+}
+
+// decimalSeparator 로케일의 소수점 구분자를 반환(언어-지역, 언어 순으로 찾음)
+func decimalSeparator(locale string) string {
+	if locale == "" {
+		return "."
+	}
+	locale = normalizeLocale(locale)
+	decimalSeparatorsMutex.RLock()
+	defer decimalSeparatorsMutex.RUnlock()
+	if separator, ok := decimalSeparators[locale]; ok {
+		return separator
+	}
+	if separator, ok := decimalSeparators[strings.SplitN(locale, "-", 2)[0]]; ok {
+		return separator
+	}
+	return "."
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// FormatNumber 옵션(자릿수, 소수점 이하 0 유지, 로케일)에 따라 숫자를 문자열로 변환하는 함수
+/* FormatNumber(1.5, &HumanizeOptions{Precision: 2, KeepTrailingZeros: true})          => 1.50
+ * FormatNumber(1234.5, &HumanizeOptions{SignificantFigures: 3, KeepTrailingZeros: true}) => 1234.5 (자릿수보다 긴 정수부는 유지)
+ * FormatNumber(1.5, &HumanizeOptions{Locale: "de-DE"})                                  => 1,5
+ */
+func FormatNumber(value float64, options *HumanizeOptions) string {
+	text := strconv.FormatFloat(value, 'f', -1, 64)
+	if options.KeepTrailingZeros && !math.IsNaN(value) && !math.IsInf(value, 0) {
+		decimals := int(options.Precision)
+		if options.SignificantFigures > 0 {
+			decimals = int(options.SignificantFigures) - 1
+			if value != 0 {
+				decimals -= int(math.Floor(math.Log10(math.Abs(value))))
+			}
+		}
+		// 반올림된 값보다 자릿수를 줄이지 않음(예: 작은 값은 Humanize 에서 Precision 보다 많은 자릿수를 유지)
+		if index := strings.IndexByte(text, '.'); index != -1 && len(text)-index-1 > decimals {
+			decimals = len(text) - index - 1
+		}
+		if decimals > 0 {
+			text = strconv.FormatFloat(value, 'f', decimals, 64)
+		}
+	}
+	if separator := decimalSeparator(options.Locale); separator != "." {
+		text = strings.Replace(text, ".", separator, 1)
+	}
+	return text
+}
@@ -0,0 +1,144 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go-practice/common/collection"
+)
+
+// ErrUnknownUnit 단위 타입에 정의되지 않은 단위인 경우의 에러
+var ErrUnknownUnit = errors.New("unknown unit")
+
+var (
+	humanizedPattern = regexp.MustCompile(`^([+-]?(?:\d+\.?\d*|\.\d+)(?:[eE][+-]?\d+)?)\s*(.*)$`)
+	quantityPattern  = regexp.MustCompile(`^([+-]?(?:\d+\.?\d*|\.\d+))(.*)$`)
+)
+
+// quantitySuffixes 쿠버네티스 리소스 수량(resource.Quantity)의 접미사별 배수
+var quantitySuffixes = map[string]float64{
+	"":   1,
+	"n":  1e-9,
+	"u":  1e-6,
+	"m":  1e-3,
+	"k":  1e3,
+	"M":  1e6,
+	"G":  1e9,
+	"T":  1e12,
+	"P":  1e15,
+	"E":  1e18,
+	"Ki": 1 << 10,
+	"Mi": 1 << 20,
+	"Gi": 1 << 30,
+	"Ti": 1 << 40,
+	"Pi": 1 << 50,
+	"Ei": 1 << 60,
+}
+
+// ParseHumanized Humanize 의 역변환으로 단위가 붙은 문자열을 단위 타입 키에 따라 기본 단위의 값으로 변환하는 함수
+// 숫자와 단위 사이의 공백은 무시하며, 단위는 대소문자가 일치하는 것을 우선하고 없으면 대소문자를 무시하여 하나만 일치하는 경우 사용한다.
+// options.InitialUnit 이 있으면 Humanize 와 같이 해당 단위를 기준으로 한 값을 반환하고, options.Locale 의 소수점 구분자를 사용한다.
+/* ParseHumanized("1.5GiB", BinaryBytes, nil)               => 1610612736
+ * ParseHumanized("1.2k pps", PacketsPerSec, nil)           => 1200
+ * ParseHumanized("300Mbps", DecimalBitsPerSec, nil)        => 300000000
+ * ParseHumanized("1,5 min", Seconds, &HumanizeOptions{InitialUnit: "s", Locale: "de-DE"}) => 90
+ */
+func ParseHumanized(value string, unitTypeKey UnitTypeKey, options *HumanizeOptions) (float64, error) {
+	types, ok := LookupUnitType(unitTypeKey)
+	if !ok {
+		return 0, fmt.Errorf("failed to parse value, value=%s, unitTypeKey=%s, err=%w", value, unitTypeKey, ErrUnknownUnit)
+	}
+	text := strings.TrimSpace(value)
+	if options != nil {
+		if separator := decimalSeparator(options.Locale); separator != "." {
+			text = strings.Replace(text, separator, ".", 1)
+		}
+	}
+	matches := humanizedPattern.FindStringSubmatch(text)
+	if matches == nil {
+		return 0, fmt.Errorf("failed to parse value, value=%s, err=invalid number", value)
+	}
+	number, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse value, value=%s, err=%s", value, err)
+	}
+
+	unit := strings.Join(strings.Fields(matches[2]), "")
+	unitIndex := findUnit(types.Units, unit)
+	if unitIndex == -1 && unit == "" {
+		// 단위가 생략된 경우 기본 단위로 간주
+		unitIndex = 0
+	}
+	if unitIndex == -1 {
+		return 0, fmt.Errorf("failed to parse value, value=%s, unitTypeKey=%s, err=%w, unit=%s", value, unitTypeKey, ErrUnknownUnit, unit)
+	}
+
+	initialIndex := 0
+	if options != nil && options.InitialUnit != "" {
+		if initialIndex = collection.IndexOf(types.Units, options.InitialUnit); initialIndex == -1 {
+			return 0, fmt.Errorf("failed to parse value, value=%s, unitTypeKey=%s, err=%w, unit=%s", value, unitTypeKey, ErrUnknownUnit, options.InitialUnit)
+		}
+	}
+	return number * types.scale(initialIndex, unitIndex), nil
+}
+
+// findUnit 단위 목록에서 단위의 인덱스를 반환(대소문자가 일치하는 단위가 없으면 대소문자를 무시하여 하나만 일치하는 경우의 인덱스)
+func findUnit(units []string, unit string) int {
+	if index := collection.IndexOf(units, unit); index != -1 {
+		return index
+	}
+	found := -1
+	for i, u := range units {
+		if strings.EqualFold(u, unit) {
+			if found != -1 {
+				return -1
+			}
+			found = i
+		}
+	}
+	return found
+}
+
+// ParseQuantity 쿠버네티스 리소스 수량 문법(500m, 1Gi, 1e3, 1.5E-3)의 문자열을 기본 단위의 값으로 변환하는 함수
+// ResourceQuota, LimitRange 의 값을 Prometheus 결과와 같은 단위로 변환할 때 사용한다.
+/* ParseQuantity("500m") => 0.5
+ * ParseQuantity("1Gi")  => 1073741824
+ * ParseQuantity("2E")   => 2000000000000000000
+ * ParseQuantity("2e3")  => 2000
+ */
+func ParseQuantity(quantity string) (float64, error) {
+	matches := quantityPattern.FindStringSubmatch(quantity)
+	if matches == nil {
+		return 0, fmt.Errorf("failed to parse quantity, quantity=%s, err=invalid number", quantity)
+	}
+	number, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse quantity, quantity=%s, err=%s", quantity, err)
+	}
+
+	suffix := matches[2]
+	if multiplier, ok := quantitySuffixes[suffix]; ok {
+		return number * multiplier, nil
+	}
+	if len(suffix) > 1 && (suffix[0] == 'e' || suffix[0] == 'E') {
+		if exponent, err := strconv.Atoi(suffix[1:]); err == nil {
+			return number * math.Pow10(exponent), nil
+		}
+	}
+	return 0, fmt.Errorf("failed to parse quantity, quantity=%s, err=%w, suffix=%s", quantity, ErrUnknownUnit, suffix)
+}
+
+// HumanizeQuantity 쿠버네티스 리소스 수량을 단위 타입 키에 따라 Humanize 한 값을 반환하는 함수
+/* HumanizeQuantity("1536Mi", BinaryBytes, &HumanizeOptions{Precision: 2}) => {GiB 1.5}
+ */
+func HumanizeQuantity(quantity string, unitTypeKey UnitTypeKey, options *HumanizeOptions) (*humanizeValue, error) {
+	value, err := ParseQuantity(quantity)
+	if err != nil {
+		return nil, err
+	}
+	return Humanize(value, unitTypeKey, options), nil
+}
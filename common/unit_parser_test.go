@@ -0,0 +1,105 @@
+package common
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseHumanized(t *testing.T) {
+	tests := []struct {
+		value       string
+		unitTypeKey UnitTypeKey
+		options     *HumanizeOptions
+		expected    float64
+	}{
+		{"1.5GiB", BinaryBytes, nil, 1.5 * (1 << 30)},
+		{"2Ki", BinaryBytesWithoutB, nil, 2048},
+		{"300Mbps", DecimalBitsPerSec, nil, 3e8},
+		{"1.2k pps", PacketsPerSec, nil, 1200},
+		{" 10 MB ", DecimalBytes, nil, 1e7},
+		{"1.5m", Numeric, nil, 1.5e6},
+		{"42", Count, nil, 42},
+		{"1e3KB", DecimalBytes, nil, 1e6},
+		{"1.5gib", BinaryBytes, nil, 1.5 * (1 << 30)},
+		{"2s", Seconds, &HumanizeOptions{InitialUnit: "ms"}, 2000},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			value, err := ParseHumanized(test.value, test.unitTypeKey, test.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, value)
+			}
+		})
+	}
+
+	for _, value := range []string{"1.5XB", "GiB", "1.5 GiB B"} {
+		if _, err := ParseHumanized(value, BinaryBytes, nil); err == nil {
+			t.Errorf("expected error for %q", value)
+		}
+	}
+	if _, err := ParseHumanized("1.5XB", BinaryBytes, nil); !errors.Is(err, ErrUnknownUnit) {
+		t.Errorf("expected unknown unit, got %v", err)
+	}
+}
+
+func TestParseHumanizedRoundTrip(t *testing.T) {
+	for _, unitTypeKey := range []UnitTypeKey{BinaryBytes, DecimalBytes, DecimalBitsPerSec, PacketsPerSec, SI, Numeric} {
+		for _, value := range []float64{0, 0.5, 999, 1234, 1536000, 7.25e9} {
+			options := &HumanizeOptions{Precision: 2}
+			humanized := Humanize(value, unitTypeKey, options)
+			parsed, err := ParseHumanized(humanized.String(), unitTypeKey, options)
+			if err != nil {
+				t.Fatalf("%s %v: %s", unitTypeKey, value, err)
+			}
+			if math.Abs(parsed-value) > math.Max(value*0.005, 0.005) {
+				t.Errorf("%s: expected %v, got %v from %q", unitTypeKey, value, parsed, humanized.String())
+			}
+		}
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		quantity string
+		expected float64
+	}{
+		{"500m", 0.5},
+		{"2", 2},
+		{"1Gi", 1 << 30},
+		{"1.5Mi", 1.5 * (1 << 20)},
+		{"2k", 2000},
+		{"2E", 2e18},
+		{"2e3", 2000},
+		{"15E-1", 1.5},
+		{"-100u", -1e-4},
+	}
+	for _, test := range tests {
+		t.Run(test.quantity, func(t *testing.T) {
+			value, err := ParseQuantity(test.quantity)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(value-test.expected) > math.Abs(test.expected)*1e-12 {
+				t.Errorf("expected %v, got %v", test.expected, value)
+			}
+		})
+	}
+
+	for _, quantity := range []string{"", "Gi", "1 Gi", "1GiB", "1e"} {
+		if _, err := ParseQuantity(quantity); err == nil {
+			t.Errorf("expected error for %q", quantity)
+		}
+	}
+
+	humanized, err := HumanizeQuantity("1536Mi", BinaryBytes, &HumanizeOptions{Precision: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if humanized.String() != "1.5GiB" {
+		t.Errorf("expected 1.5GiB, got %s", humanized)
+	}
+}
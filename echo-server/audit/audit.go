@@ -0,0 +1,125 @@
+// Package audit Istio 설정 변경 기록(누가, 언제, 어느 클러스터의 어떤 객체를 어떻게 바꿨는지)과 기록 저장소
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Action 변경 종류
+type Action string
+
+const (
+	ActionCreate = Action("create")
+	ActionUpdate = Action("update")
+	ActionDelete = Action("delete")
+)
+
+// Record Istio 설정 변경 기록 한 건
+/*
+{"time":"2023-07-25T10:00:00Z","user":"secloudit-admin","cluster":"east","namespace":"default","objectType":"virtualservices",
+ "object":"reviews","action":"update","dryRun":false,"status":200,"diff":[{"op":"replace","path":"/spec/hosts/0","from":"a","to":"b"}],...}
+*/
+type Record struct {
+	Time       time.Time       `json:"time"`
+	User       string          `json:"user"`
+	RemoteAddr string          `json:"remoteAddr"`
+	Cluster    string          `json:"cluster"`
+	Namespace  string          `json:"namespace"`
+	ObjectType string          `json:"objectType"`
+	Object     string          `json:"object"`
+	Action     Action          `json:"action"`
+	DryRun     bool            `json:"dryRun"`
+	Status     int             `json:"status"` // Kiali 응답 상태 코드(dry-run 이나 검증 실패면 echo-server 의 응답 상태 코드)
+	Error      string          `json:"error,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	Diff       []Change        `json:"diff,omitempty"`
+}
+
+// Sink 변경 기록 저장소(추가만 가능)
+type Sink interface {
+	Write(ctx context.Context, record Record) error
+}
+
+// Change 변경 전후 JSON 의 차이 한 건, 경로는 JSON Pointer(RFC 6901) 형식
+type Change struct {
+	Op   string      `json:"op"` // add, remove, replace
+	Path string      `json:"path"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// Diff 변경 전후 JSON 의 차이, 객체는 필드별로 비교하고 배열이나 값이 다르면 통째로 replace 로 기록
+/* Diff({"spec":{"hosts":["a"]}}, {"spec":{"hosts":["b"],"gateways":["g"]}})
+   => [{"op":"add","path":"/spec/gateways","to":["g"]},{"op":"replace","path":"/spec/hosts","from":["a"],"to":["b"]}]
+*/
+func Diff(before json.RawMessage, after json.RawMessage) []Change {
+	var beforeValue, afterValue interface{}
+	if len(before) > 0 {
+		_ = json.Unmarshal(before, &beforeValue)
+	}
+	if len(after) > 0 {
+		_ = json.Unmarshal(after, &afterValue)
+	}
+	var changes []Change
+	diff("", beforeValue, afterValue, &changes)
+	return changes
+}
+
+func diff(path string, before interface{}, after interface{}, changes *[]Change) {
+	beforeObject, beforeIsObject := before.(map[string]interface{})
+	afterObject, afterIsObject := after.(map[string]interface{})
+	switch {
+	case beforeIsObject && afterIsObject:
+		keys := make([]string, 0, len(beforeObject)+len(afterObject))
+		for key := range beforeObject {
+			keys = append(keys, key)
+		}
+		for key := range afterObject {
+			if _, ok := beforeObject[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			diff(path+"/"+escapePointer(key), beforeObject[key], afterObject[key], changes)
+		}
+	case before == nil && after != nil:
+		*changes = append(*changes, Change{Op: "add", Path: path, To: after})
+	case before != nil && after == nil:
+		*changes = append(*changes, Change{Op: "remove", Path: path, From: before})
+	case !reflect.DeepEqual(before, after):
+		*changes = append(*changes, Change{Op: "replace", Path: path, From: before, To: after})
+	}
+}
+
+// escapePointer JSON Pointer 의 특수 문자(~, /)를 이스케이프
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// Strip 기록에 남길 필요가 없는 메타데이터(managedFields 등)를 제거한 객체
+func Strip(object json.RawMessage) json.RawMessage {
+	if len(object) == 0 {
+		return nil
+	}
+	var value map[string]interface{}
+	if json.Unmarshal(object, &value) != nil {
+		return object
+	}
+	if metadata, ok := value["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"managedFields", "resourceVersion", "generation", "uid", "creationTimestamp"} {
+			delete(metadata, field)
+		}
+	}
+	stripped, err := json.Marshal(value)
+	if err != nil {
+		return object
+	}
+	return stripped
+}
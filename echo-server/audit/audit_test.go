@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	changes := Diff(
+		[]byte(`{"metadata":{"name":"a","labels":{"x/y":"1"}},"spec":{"hosts":["a"]}}`),
+		[]byte(`{"metadata":{"name":"a"},"spec":{"hosts":["b"],"gateways":["g"]}}`),
+	)
+	actual, _ := json.Marshal(changes)
+	expected := `[{"op":"remove","path":"/metadata/labels","from":{"x/y":"1"}},{"op":"add","path":"/spec/gateways","to":["g"]},{"op":"replace","path":"/spec/hosts","from":["a"],"to":["b"]}]`
+	if string(actual) != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+	if changes = Diff(nil, []byte(`{"spec":{}}`)); len(changes) != 1 || changes[0].Op != "add" || changes[0].Path != "" {
+		t.Errorf("unexpected create diff %+v", changes)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	for i := 0; i < 2; i++ {
+		sink, err := NewFileSink(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record := Record{Time: time.Unix(0, 0).UTC(), User: "user", Cluster: "east", Action: ActionDelete, Status: 200}
+		if err = sink.Write(context.Background(), record); err != nil {
+			t.Fatal(err)
+		}
+		if err = sink.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 다시 열어도 기존 기록 뒤에 추가
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	lines := 0
+	for scanner := bufio.NewScanner(file); scanner.Scan(); lines++ {
+		var record Record
+		if err = json.Unmarshal(scanner.Bytes(), &record); err != nil || record.User != "user" || record.Action != ActionDelete {
+			t.Errorf("unexpected line %s", scanner.Text())
+		}
+	}
+	if lines != 2 {
+		t.Errorf("expected 2 lines, got %d", lines)
+	}
+}
+
+func TestNewSQLSinkTableName(t *testing.T) {
+	for _, table := range []string{"audit; DROP TABLE users", "1audit", ""} {
+		if _, err := NewSQLSink(&sql.DB{}, table); err == nil {
+			t.Errorf("expected error for table %q", table)
+		}
+	}
+	if _, err := NewSQLSink(&sql.DB{}, "istio_config_audit"); err != nil {
+		t.Error(err)
+	}
+}
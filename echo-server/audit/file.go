@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileSink 변경 기록을 JSON Lines 파일 끝에 추가하는 저장소
+type FileSink struct {
+	mutex sync.Mutex
+	file  *os.File
+}
+
+// NewFileSink 기록 파일을 추가 모드로 열어 저장소를 생성(파일이 없으면 생성)
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file, path=%s, err=%s", path, err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Write 기록 한 건을 한 줄로 추가하고 디스크에 기록
+func (f *FileSink) Write(ctx context.Context, record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record, err=%s", err)
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err = f.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record, err=%s", err)
+	}
+	return f.file.Sync()
+}
+
+// Close 기록 파일을 닫음
+func (f *FileSink) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.file.Close()
+}
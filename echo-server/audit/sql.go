@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// tableNamePattern 쿼리에 그대로 쓰는 테이블 이름 형식
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLSink 변경 기록을 데이터베이스(MySQL) 테이블에 추가하는 저장소, 드라이버는 main 에서 import 해야 함
+/*
+CREATE TABLE istio_config_audit (
+  id          BIGINT AUTO_INCREMENT PRIMARY KEY,
+  time        DATETIME(3)  NOT NULL,
+  user        VARCHAR(255) NOT NULL,
+  remote_addr VARCHAR(64)  NOT NULL,
+  cluster     VARCHAR(255) NOT NULL,
+  namespace   VARCHAR(255) NOT NULL,
+  object_type VARCHAR(64)  NOT NULL,
+  object      VARCHAR(255) NOT NULL,
+  action      VARCHAR(16)  NOT NULL,
+  dry_run     BOOLEAN      NOT NULL,
+  status      INT          NOT NULL,
+  error       TEXT,
+  before_json JSON,
+  after_json  JSON,
+  diff_json   JSON
+);
+*/
+type SQLSink struct {
+	db    *sql.DB
+	query string
+}
+
+// NewSQLSink 테이블에 기록을 추가하는 저장소를 생성
+func NewSQLSink(db *sql.DB, table string) (*SQLSink, error) {
+	if !tableNamePattern.MatchString(table) {
+		return nil, fmt.Errorf("invalid audit table name, table=%s", table)
+	}
+	return &SQLSink{
+		db: db,
+		query: "INSERT INTO " + table + " (time, user, remote_addr, cluster, namespace, object_type, object, action, dry_run, status, error, before_json, after_json, diff_json)" +
+			" VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+	}, nil
+}
+
+// Write 기록 한 건을 추가
+func (s *SQLSink) Write(ctx context.Context, record Record) error {
+	diff, err := json.Marshal(record.Diff)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit diff, err=%s", err)
+	}
+	_, err = s.db.ExecContext(ctx, s.query,
+		record.Time, record.User, record.RemoteAddr, record.Cluster, record.Namespace, record.ObjectType, record.Object,
+		string(record.Action), record.DryRun, record.Status, nullString(record.Error),
+		nullString(string(record.Before)), nullString(string(record.After)), string(diff))
+	if err != nil {
+		return fmt.Errorf("failed to insert audit record, err=%s", err)
+	}
+	return nil
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
@@ -0,0 +1,260 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var (
+	secret = []byte("hello-jwt")
+	now    = time.Unix(1663752900, 0)
+)
+
+// consoleClaims 콘솔 토큰과 같은 형태의 claims
+func consoleClaims(exp time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"sub":    "admin",
+		"userId": "secloudit-admin",
+		"iat":    now.Unix(),
+		"exp":    exp.Unix(),
+		"infos":  map[string]interface{}{"entCode": "SEL", "unitCode": "002", "username": "admin", "isOSManager": true},
+	}
+}
+
+func encode(value interface{}) string {
+	bytes, _ := json.Marshal(value)
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+func signHS256(header map[string]string, claims map[string]interface{}, key []byte) string {
+	input := encode(header) + "." + encode(claims)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(kid string, claims map[string]interface{}, key *rsa.PrivateKey) string {
+	input := encode(map[string]string{"alg": "RS256", "kid": kid}) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(input))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerifyHS256(t *testing.T) {
+	verifier := &Verifier{HMACKey: secret, Now: func() time.Time { return now }}
+	hs256 := map[string]string{"alg": "HS256"}
+
+	claims, err := verifier.Verify(context.Background(), signHS256(hs256, consoleClaims(now.Add(time.Hour)), secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != "secloudit-admin" || claims.Infos.EntCode != "SEL" || !claims.HasRole(RoleOSManager) {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		code  string
+	}{
+		{"malformed", "not-a-token", ErrCodeTokenMalformed},
+		{"wrong secret", signHS256(hs256, consoleClaims(now.Add(time.Hour)), []byte("other")), ErrCodeTokenSignatureInvalid},
+		{"expired", signHS256(hs256, consoleClaims(now.Add(-time.Minute)), secret), ErrCodeTokenExpired},
+		{"none algorithm", encode(map[string]string{"alg": "none"}) + "." + encode(consoleClaims(now.Add(time.Hour))) + ".", ErrCodeTokenUnverifiable},
+		{"rs256 without keys", signHS256(map[string]string{"alg": "RS256"}, consoleClaims(now.Add(time.Hour)), secret), ErrCodeTokenUnverifiable},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := verifier.Verify(context.Background(), test.token); err == nil || err.Code != test.code {
+				t.Errorf("expected %s, got %v", test.code, err)
+			}
+		})
+	}
+
+	withoutExpiration := consoleClaims(now.Add(time.Hour))
+	delete(withoutExpiration, "exp")
+	if _, err := verifier.Verify(context.Background(), signHS256(hs256, withoutExpiration, secret)); err == nil || err.Code != ErrCodeTokenInvalidClaims {
+		t.Errorf("expected %s, got %v", ErrCodeTokenInvalidClaims, err)
+	}
+
+	notBefore := consoleClaims(now.Add(time.Hour))
+	notBefore["nbf"] = now.Add(time.Minute).Unix()
+	if _, err := verifier.Verify(context.Background(), signHS256(hs256, notBefore, secret)); err == nil || err.Code != ErrCodeTokenNotValidYet {
+		t.Errorf("expected %s, got %v", ErrCodeTokenNotValidYet, err)
+	}
+
+	verifier.Issuer, verifier.Audience = "console", "echo-server"
+	scoped := consoleClaims(now.Add(time.Hour))
+	scoped["iss"], scoped["aud"] = "console", []string{"kiali", "echo-server"}
+	if _, err := verifier.Verify(context.Background(), signHS256(hs256, scoped, secret)); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	scoped["aud"] = "kiali"
+	if _, err := verifier.Verify(context.Background(), signHS256(hs256, scoped, secret)); err == nil || err.Code != ErrCodeTokenInvalidClaims {
+		t.Errorf("expected %s, got %v", ErrCodeTokenInvalidClaims, err)
+	}
+}
+
+func TestVerifyRS256JWKS(t *testing.T) {
+	oldKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	newKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	jwk := func(kid string, key *rsa.PrivateKey) map[string]string {
+		return map[string]string{
+			"kty": "RSA", "kid": kid, "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}
+	}
+	var rotated atomic.Bool
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		keys := []map[string]string{jwk("old", oldKey)}
+		if rotated.Load() {
+			keys = append(keys, jwk("new", newKey))
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	defer server.Close()
+
+	jwks := &JWKS{URL: server.URL}
+	verifier := &Verifier{Keys: jwks, Now: func() time.Time { return now }}
+	claims := consoleClaims(now.Add(time.Hour))
+	if _, err := verifier.Verify(context.Background(), signRS256("old", claims, oldKey)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.Verify(context.Background(), signRS256("old", claims, newKey)); err == nil || err.Code != ErrCodeTokenSignatureInvalid {
+		t.Errorf("expected %s, got %v", ErrCodeTokenSignatureInvalid, err)
+	}
+
+	// 키 교체 직후 모르는 kid 는 최소 갱신 간격이 지난 후 다시 받아서 검증
+	rotated.Store(true)
+	if _, err := verifier.Verify(context.Background(), signRS256("new", claims, newKey)); err == nil || err.Code != ErrCodeTokenUnverifiable {
+		t.Errorf("expected %s, got %v", ErrCodeTokenUnverifiable, err)
+	}
+	jwks.fetchedAt = jwks.fetchedAt.Add(-minJWKSRefreshInterval - time.Second)
+	if _, err := verifier.Verify(context.Background(), signRS256("new", claims, newKey)); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	if count := fetches.Load(); count != 2 {
+		t.Errorf("expected 2 jwks fetches, got %d", count)
+	}
+}
+
+func TestJWKSRefreshFailure(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keys := map[string]interface{}{"keys": []map[string]string{{
+		"kty": "RSA", "kid": "console",
+		"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	var available atomic.Bool
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if !available.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		<-release
+		_ = json.NewEncoder(w).Encode(keys)
+	}))
+	defer server.Close()
+
+	// 장애 중에는 한 번만 받고 minJWKSRefreshInterval 동안 마지막 에러를 반환
+	jwks := &JWKS{URL: server.URL}
+	for i := 0; i < 5; i++ {
+		if _, err := jwks.Key(context.Background(), "console"); err == nil || !strings.Contains(err.Error(), "status=503") {
+			t.Errorf("expected jwks fetch error, got %v", err)
+		}
+	}
+	if count := fetches.Load(); count != 1 {
+		t.Errorf("expected 1 jwks fetch during backoff, got %d", count)
+	}
+
+	// 백오프가 지난 후 동시에 들어온 요청은 한 번의 조회 결과를 함께 사용
+	available.Store(true)
+	jwks.failedAt = jwks.failedAt.Add(-minJWKSRefreshInterval - time.Second)
+	errs := make(chan error, 10)
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			_, err := jwks.Key(context.Background(), "console")
+			errs <- err
+		}()
+	}
+	for fetches.Load() != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("unexpected error %s", err)
+		}
+	}
+	if count := fetches.Load(); count != 2 {
+		t.Errorf("expected 2 jwks fetches, got %d", count)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	verifier := &Verifier{HMACKey: secret, Now: func() time.Time { return now }}
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error {
+		if authErr := Authorize(c, []Role{RoleOSManager}); authErr != nil {
+			return WriteError(c, authErr)
+		}
+		claims, _ := ClaimsFrom(c)
+		return c.String(http.StatusOK, claims.UserID)
+	}, Middleware(verifier, "console-token"))
+
+	serve := func(setup func(request *http.Request)) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		setup(request)
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		return recorder
+	}
+	manager := signHS256(map[string]string{"alg": "HS256"}, consoleClaims(now.Add(time.Hour)), secret)
+	userClaims := consoleClaims(now.Add(time.Hour))
+	userClaims["infos"] = map[string]interface{}{"entCode": "SEL"}
+	user := signHS256(map[string]string{"alg": "HS256"}, userClaims, secret)
+
+	recorder := serve(func(request *http.Request) {})
+	if recorder.Code != http.StatusUnauthorized || recorder.Header().Get("WWW-Authenticate") != `Bearer realm="echo-server"` {
+		t.Errorf("unexpected response for missing token %d %v", recorder.Code, recorder.Header())
+	}
+	if !strings.Contains(recorder.Body.String(), `"code":"token_missing"`) {
+		t.Errorf("unexpected body %s", recorder.Body)
+	}
+
+	recorder = serve(func(request *http.Request) { request.Header.Set("Authorization", "Bearer "+manager+"x") })
+	if recorder.Code != http.StatusUnauthorized || !strings.Contains(recorder.Header().Get("WWW-Authenticate"), `error="invalid_token"`) {
+		t.Errorf("unexpected response for invalid token %d %v", recorder.Code, recorder.Header())
+	}
+
+	recorder = serve(func(request *http.Request) { request.Header.Set("Authorization", "Bearer "+manager) })
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "secloudit-admin" {
+		t.Errorf("unexpected response %d %s", recorder.Code, recorder.Body)
+	}
+
+	recorder = serve(func(request *http.Request) { request.AddCookie(&http.Cookie{Name: "console-token", Value: user}) })
+	if recorder.Code != http.StatusForbidden || !strings.Contains(recorder.Body.String(), `"requiredRoles":["os-manager"]`) {
+		t.Errorf("unexpected response for insufficient role %d %s", recorder.Code, recorder.Body)
+	}
+}
@@ -0,0 +1,80 @@
+// Package auth 콘솔에서 발급한 JWT 를 검증하여 사용자 정보를 echo 컨텍스트에 설정하고 경로별 권한을 확인
+package auth
+
+import (
+	"github.com/golang-jwt/jwt/v4"
+	"time"
+)
+
+// Role 경로별 접근 권한(proxy.Proxy.Roles)에 사용하는 역할
+type Role string
+
+const (
+	RoleUser      = Role("user")       // 인증된 모든 사용자
+	RoleOSManager = Role("os-manager") // 운영 관리자(infos.isOSManager)
+)
+
+// Claims 콘솔 JWT 의 claims
+/*
+{"sub":"admin","userId":"secloudit-admin","iat":1663752857,"exp":1695288857,
+ "infos":{"entCode":"SEL","unitCode":"002","username":"admin","isOSManager":true,...}}
+*/
+type Claims struct {
+	UserID string `json:"userId"`
+	Infos  Infos  `json:"infos"`
+	jwt.RegisteredClaims
+}
+
+// Infos 콘솔 JWT 의 사용자 부가 정보
+type Infos struct {
+	Username      string `json:"username,omitempty"`
+	LoginUserName string `json:"loginUserName,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EntCode       string `json:"entCode,omitempty"` // 기관 코드
+	EntName       string `json:"entName,omitempty"`
+	UnitCode      string `json:"unitCode,omitempty"` // 부서 코드
+	UnitName      string `json:"unitName,omitempty"`
+	IsOSManager   bool   `json:"isOSManager,omitempty"`
+	IsSSOLogin    bool   `json:"isSSOLogin,omitempty"`
+	LoginTime     int64  `json:"loginTime,omitempty"` // 밀리초
+}
+
+// Roles 사용자의 역할, 인증된 사용자는 모두 RoleUser 를 가진다
+func (c *Claims) Roles() []Role {
+	roles := []Role{RoleUser}
+	if c.Infos.IsOSManager {
+		roles = append(roles, RoleOSManager)
+	}
+	return roles
+}
+
+// HasRole 역할 보유 여부
+func (c *Claims) HasRole(role Role) bool {
+	for _, item := range c.Roles() {
+		if item == role {
+			return true
+		}
+	}
+	return false
+}
+
+// validate 만료 시간, 활성 시간, 발급자, audience 를 검사(leeway 만큼 시계 오차 허용)
+// 만료 시간(exp)이 없는 토큰은 거부한다.
+func (c *Claims) validate(now time.Time, leeway time.Duration, issuer, audience string) *Error {
+	if c.ExpiresAt == nil {
+		return newError(ErrCodeTokenInvalidClaims, "token has no expiration")
+	}
+	if !now.Before(c.ExpiresAt.Add(leeway)) {
+		return newError(ErrCodeTokenExpired, "token is expired")
+	}
+	if c.NotBefore != nil && now.Add(leeway).Before(c.NotBefore.Time) {
+		return newError(ErrCodeTokenNotValidYet, "token is not valid yet")
+	}
+	if issuer != "" && !c.VerifyIssuer(issuer, true) {
+		return newError(ErrCodeTokenInvalidClaims, "unexpected token issuer")
+	}
+	if audience != "" && !c.VerifyAudience(audience, true) {
+		return newError(ErrCodeTokenInvalidClaims, "unexpected token audience")
+	}
+	return nil
+}
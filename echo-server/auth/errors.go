@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// 인증, 권한 에러 코드
+const (
+	ErrCodeTokenMissing          = "token_missing"
+	ErrCodeTokenMalformed        = "token_malformed"
+	ErrCodeTokenUnverifiable     = "token_unverifiable" // 지원하지 않는 알고리즘이거나 검증 키를 찾을 수 없음
+	ErrCodeTokenSignatureInvalid = "token_signature_invalid"
+	ErrCodeTokenExpired          = "token_expired"
+	ErrCodeTokenNotValidYet      = "token_not_valid_yet"
+	ErrCodeTokenInvalidClaims    = "token_invalid_claims"
+	ErrCodeForbidden             = "forbidden"
+	ErrCodeNamespaceForbidden    = "namespace_forbidden"
+	ErrCodeAuthzUnavailable      = "authorization_unavailable" // 네임스페이스 권한을 확인할 수 없음
+)
+
+// Error 인증(401), 권한(403) 에러 응답
+/* 401 {"code":"token_expired","message":"token is expired"}
+   403 {"code":"forbidden","message":"insufficient role","requiredRoles":["os-manager"]}
+   403 {"code":"namespace_forbidden","message":"namespace access denied","namespace":"kube-system"}
+*/
+type Error struct {
+	Status        int    `json:"-"`
+	Code          string `json:"code"`
+	Message       string `json:"message"`
+	RequiredRoles []Role `json:"requiredRoles,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`
+}
+
+func newError(code string, message string) *Error {
+	return &Error{Status: http.StatusUnauthorized, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// WriteError 에러 응답을 작성, 401 이면 WWW-Authenticate 헤더를 함께 작성
+func WriteError(c echo.Context, err *Error) error {
+	if err.Status == http.StatusUnauthorized {
+		challenge := `Bearer realm="echo-server"`
+		if err.Code != ErrCodeTokenMissing {
+			challenge += `, error="invalid_token", error_description="` + err.Message + `"`
+		}
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, challenge)
+	}
+	return c.JSON(err.Status, err)
+}
@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultJWKSRefreshInterval = time.Hour
+	minJWKSRefreshInterval     = 30 * time.Second // 모르는 kid 로 JWKS 를 반복 조회하지 않도록 제한
+)
+
+// KeySet kid 로 RS256 공개키를 찾는 인터페이스
+type KeySet interface {
+	Key(ctx context.Context, keyID string) (*rsa.PublicKey, error)
+}
+
+// StaticKeys kid 별 고정 공개키, 키가 하나이면 kid 가 없는 토큰에도 사용
+type StaticKeys map[string]*rsa.PublicKey
+
+// Key kid 에 해당하는 공개키
+func (k StaticKeys) Key(ctx context.Context, keyID string) (*rsa.PublicKey, error) {
+	return findKey(k, keyID)
+}
+
+// ParseRSAPublicKey PEM 형식(PUBLIC KEY, RSA PUBLIC KEY)의 RSA 공개키를 파싱
+func ParseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no pem block found")
+	}
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("not an rsa public key")
+	}
+	return rsaKey, nil
+}
+
+// JWKS JWKS URL 에서 공개키를 받아 캐시하는 KeySet
+// RefreshInterval 마다 다시 받으며, 모르는 kid 가 오면 키 교체로 보고 minJWKSRefreshInterval 이 지났을 때 다시 받는다.
+// 받기에 실패하면 minJWKSRefreshInterval 동안 다시 받지 않고, 동시에 들어온 요청은 한 번의 조회 결과를 함께 기다린다.
+type JWKS struct {
+	URL             string
+	Client          *http.Client  // nil 이면 http.DefaultClient
+	RefreshInterval time.Duration // 0 이면 1시간
+
+	mutex      sync.Mutex
+	keys       map[string]*rsa.PublicKey
+	fetchedAt  time.Time
+	failedAt   time.Time
+	fetchErr   error         // 마지막 조회 실패 에러(성공하면 nil)
+	refreshing chan struct{} // 조회 중이면 조회가 끝날 때 닫히는 채널
+}
+
+// jsonWebKey JWKS 의 RSA 키 항목
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+}
+
+// Key kid 에 해당하는 공개키, 캐시가 만료되었거나 모르는 kid 이면 JWKS 를 다시 받음
+// 조회는 잠금 밖에서 한 번만 수행하고, 다른 요청은 조회가 끝날 때까지 기다린 후 캐시에서 찾는다.
+func (j *JWKS) Key(ctx context.Context, keyID string) (*rsa.PublicKey, error) {
+	for {
+		j.mutex.Lock()
+		key, err := findKey(j.keys, keyID)
+		if !j.shouldRefresh(err != nil, time.Now()) {
+			if key == nil && j.keys == nil && j.fetchErr != nil {
+				err = j.fetchErr
+			}
+			j.mutex.Unlock()
+			return key, err
+		}
+		if done := j.refreshing; done != nil {
+			j.mutex.Unlock()
+			select {
+			case <-done:
+				continue
+			case <-ctx.Done():
+				if key != nil {
+					return key, nil
+				}
+				return nil, fmt.Errorf("failed to wait for jwks, url=%s, err=%s", j.URL, ctx.Err())
+			}
+		}
+		done := make(chan struct{})
+		j.refreshing = done
+		j.mutex.Unlock()
+
+		keys, fetchErr := j.fetch(ctx)
+
+		j.mutex.Lock()
+		if fetchErr == nil {
+			j.keys, j.fetchedAt, j.fetchErr = keys, time.Now(), nil
+		} else if ctx.Err() == nil {
+			// 요청이 취소되어 실패한 경우는 JWKS 장애가 아니므로 기록하지 않음
+			j.failedAt, j.fetchErr = time.Now(), fetchErr
+		}
+		j.refreshing = nil
+		close(done)
+		if fetchErr == nil {
+			key, err = findKey(j.keys, keyID)
+		}
+		j.mutex.Unlock()
+
+		if fetchErr != nil {
+			if key != nil {
+				// 갱신에 실패해도 캐시된 키로 검증
+				return key, nil
+			}
+			return nil, fetchErr
+		}
+		return key, err
+	}
+}
+
+// shouldRefresh JWKS 를 다시 받아야 하는지 여부, 마지막 실패 후 minJWKSRefreshInterval 동안은 다시 받지 않음
+func (j *JWKS) shouldRefresh(unknownKey bool, now time.Time) bool {
+	if now.Sub(j.failedAt) < minJWKSRefreshInterval {
+		return false
+	}
+	refreshInterval := j.RefreshInterval
+	if refreshInterval <= 0 {
+		refreshInterval = defaultJWKSRefreshInterval
+	}
+	age := now.Sub(j.fetchedAt)
+	return j.keys == nil || age > refreshInterval || (unknownKey && age > minJWKSRefreshInterval)
+}
+
+// fetch JWKS 를 받아 RSA 서명 키를 반환
+func (j *JWKS) fetch(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, j.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create jwks request, err=%s", err)
+	}
+	client := j.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks, url=%s, err=%s", j.URL, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch jwks, url=%s, status=%d", j.URL, response.StatusCode)
+	}
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err = json.NewDecoder(response.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode jwks, url=%s, err=%s", j.URL, err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, webKey := range set.Keys {
+		if webKey.KeyType != "RSA" || (webKey.Use != "" && webKey.Use != "sig") {
+			continue
+		}
+		key, err := webKey.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jwks key, kid=%s, err=%s", webKey.KeyID, err)
+		}
+		keys[webKey.KeyID] = key
+	}
+	return keys, nil
+}
+
+// publicKey JWK 의 modulus(n), exponent(e)로 RSA 공개키를 생성
+func (k jsonWebKey) publicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("invalid exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+// findKey kid 에 해당하는 키, 키가 하나이면서 토큰이나 키에 kid 가 없으면 그 키를 사용
+func findKey(keys map[string]*rsa.PublicKey, keyID string) (*rsa.PublicKey, error) {
+	if key, ok := keys[keyID]; ok {
+		return key, nil
+	}
+	if _, anonymous := keys[""]; len(keys) == 1 && (keyID == "" || anonymous) {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key, kid=%s", keyID)
+}
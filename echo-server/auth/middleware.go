@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strings"
+)
+
+// claimsKey echo 컨텍스트에 claims 를 저장하는 키
+const claimsKey = "auth.claims"
+
+// Middleware Authorization: Bearer 헤더(없으면 cookieName 쿠키)의 토큰을 검증하여 claims 를 컨텍스트에 설정
+// 토큰이 없거나 유효하지 않으면 401 을 응답한다.
+func Middleware(verifier *Verifier, cookieName string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token := bearerToken(c.Request())
+			if token == "" && cookieName != "" {
+				if cookie, err := c.Cookie(cookieName); err == nil {
+					token = cookie.Value
+				}
+			}
+			if token == "" {
+				return WriteError(c, newError(ErrCodeTokenMissing, "authentication is required"))
+			}
+			claims, err := verifier.Verify(c.Request().Context(), token)
+			if err != nil {
+				return WriteError(c, err)
+			}
+			c.Set(claimsKey, claims)
+			return next(c)
+		}
+	}
+}
+
+// bearerToken Authorization 헤더의 Bearer 토큰
+func bearerToken(request *http.Request) string {
+	scheme, token, ok := strings.Cut(request.Header.Get(echo.HeaderAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// ClaimsFrom Middleware 가 설정한 claims
+func ClaimsFrom(c echo.Context) (*Claims, bool) {
+	claims, ok := c.Get(claimsKey).(*Claims)
+	return claims, ok
+}
+
+// Authorize 사용자가 roles 중 하나를 가지고 있는지 확인, roles 가 비어 있으면 모두 허용
+// 인증되지 않은 요청은 401, 역할이 없으면 403 에러를 반환한다.
+func Authorize(c echo.Context, roles []Role) *Error {
+	if len(roles) == 0 {
+		return nil
+	}
+	claims, ok := ClaimsFrom(c)
+	if !ok {
+		return newError(ErrCodeTokenMissing, "authentication is required")
+	}
+	for _, role := range roles {
+		if claims.HasRole(role) {
+			return nil
+		}
+	}
+	return &Error{Status: http.StatusForbidden, Code: ErrCodeForbidden, Message: "insufficient role", RequiredRoles: roles}
+}
@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sync"
+	"time"
+)
+
+const (
+	namespaceAccessKey         = "auth.namespaceAccess" // echo 컨텍스트에 NamespaceAccess 를 저장하는 키
+	defaultNamespaceAccessTTL  = 30 * time.Second
+	maxConcurrentAccessReviews = 8
+)
+
+// Username Kubernetes 권한 확인에 사용할 사용자 이름(userId, 없으면 sub)
+func (c *Claims) Username() string {
+	if c.UserID != "" {
+		return c.UserID
+	}
+	return c.Subject
+}
+
+// NamespaceAuthorizer SubjectAccessReview 로 사용자의 네임스페이스 접근 권한을 확인하고 TTL 동안 캐시
+// 네임스페이스에서 Resource 에 Verb 권한이 있으면 접근할 수 있는 네임스페이스로 본다.
+type NamespaceAuthorizer struct {
+	Verb     string        // 기본값 list
+	Resource string        // 기본값 pods
+	TTL      time.Duration // 기본값 30초
+	Now      func() time.Time
+
+	mutex sync.Mutex
+	cache map[accessKey]accessEntry
+}
+
+// accessKey 클러스터, 사용자, 네임스페이스별 캐시 키
+type accessKey struct {
+	cluster   string
+	user      string
+	namespace string
+}
+
+type accessEntry struct {
+	allowed   bool
+	expiresAt time.Time
+}
+
+// NamespaceAccess 한 요청에서 사용하는 클러스터와 사용자의 네임스페이스 접근 권한 확인기
+type NamespaceAccess struct {
+	authorizer *NamespaceAuthorizer
+	clientset  kubernetes.Interface
+	cluster    string
+	user       string
+}
+
+// For 클러스터(clientset)와 사용자의 NamespaceAccess 를 생성
+func (a *NamespaceAuthorizer) For(clientset kubernetes.Interface, cluster string, user string) *NamespaceAccess {
+	return &NamespaceAccess{authorizer: a, clientset: clientset, cluster: cluster, user: user}
+}
+
+// Allowed 네임스페이스별 접근 가능 여부, 캐시에 없는 네임스페이스는 동시에 확인
+func (n *NamespaceAccess) Allowed(ctx context.Context, namespaces ...string) (map[string]bool, error) {
+	allowed := make(map[string]bool, len(namespaces))
+	var pending []string
+	for _, namespace := range namespaces {
+		if _, ok := allowed[namespace]; ok {
+			continue
+		}
+		if cached, ok := n.authorizer.cached(n.key(namespace)); ok {
+			allowed[namespace] = cached
+			continue
+		}
+		allowed[namespace] = false
+		pending = append(pending, namespace)
+	}
+
+	var (
+		wait     sync.WaitGroup
+		mutex    sync.Mutex
+		firstErr error
+		limit    = make(chan struct{}, maxConcurrentAccessReviews)
+	)
+	for _, namespace := range pending {
+		wait.Add(1)
+		limit <- struct{}{}
+		go func(namespace string) {
+			defer func() {
+				<-limit
+				wait.Done()
+			}()
+			ok, err := n.review(ctx, namespace)
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			allowed[namespace] = ok
+			n.authorizer.store(n.key(namespace), ok)
+		}(namespace)
+	}
+	wait.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return allowed, nil
+}
+
+// review SubjectAccessReview 로 네임스페이스 접근 권한을 확인
+/* {"kind":"SubjectAccessReview","spec":{"user":"secloudit-admin","resourceAttributes":{"namespace":"bookinfo","verb":"list","resource":"pods"}}}
+ */
+func (n *NamespaceAccess) review(ctx context.Context, namespace string) (bool, error) {
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User: n.user,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      n.authorizer.verb(),
+				Resource:  n.authorizer.resource(),
+			},
+		},
+	}
+	result, err := n.clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to review namespace access, cluster=%s, namespace=%s, err=%s", n.cluster, namespace, err)
+	}
+	return result.Status.Allowed, nil
+}
+
+func (n *NamespaceAccess) key(namespace string) accessKey {
+	return accessKey{cluster: n.cluster, user: n.user, namespace: namespace}
+}
+
+func (a *NamespaceAuthorizer) cached(key accessKey) (bool, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	entry, ok := a.cache[key]
+	if !ok || !a.now().Before(entry.expiresAt) {
+		return false, false
+	}
+	return entry.allowed, true
+}
+
+func (a *NamespaceAuthorizer) store(key accessKey, allowed bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.cache == nil {
+		a.cache = make(map[accessKey]accessEntry)
+	}
+	now := a.now()
+	// 만료된 항목이 쌓이지 않도록 저장할 때 정리
+	for cachedKey, entry := range a.cache {
+		if !now.Before(entry.expiresAt) {
+			delete(a.cache, cachedKey)
+		}
+	}
+	ttl := a.TTL
+	if ttl <= 0 {
+		ttl = defaultNamespaceAccessTTL
+	}
+	a.cache[key] = accessEntry{allowed: allowed, expiresAt: now.Add(ttl)}
+}
+
+func (a *NamespaceAuthorizer) verb() string {
+	if a.Verb != "" {
+		return a.Verb
+	}
+	return "list"
+}
+
+func (a *NamespaceAuthorizer) resource() string {
+	if a.Resource != "" {
+		return a.Resource
+	}
+	return "pods"
+}
+
+func (a *NamespaceAuthorizer) now() time.Time {
+	if a.Now != nil {
+		return a.Now()
+	}
+	return time.Now()
+}
+
+// SetNamespaceAccess 요청의 네임스페이스 접근 권한 확인기를 컨텍스트에 설정
+func SetNamespaceAccess(c echo.Context, access *NamespaceAccess) {
+	c.Set(namespaceAccessKey, access)
+}
+
+// NamespaceAccessFrom 컨텍스트의 네임스페이스 접근 권한 확인기, 없으면 네임스페이스 권한을 확인하지 않는 요청
+func NamespaceAccessFrom(c echo.Context) (*NamespaceAccess, bool) {
+	access, ok := c.Get(namespaceAccessKey).(*NamespaceAccess)
+	return access, ok
+}
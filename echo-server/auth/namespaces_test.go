@@ -0,0 +1,36 @@
+package auth_test
+
+import (
+	"context"
+	"echo-server/auth"
+	"echo-server/proxy/proxytest"
+	"testing"
+	"time"
+)
+
+func TestNamespaceAccess(t *testing.T) {
+	stub := proxytest.NewAccessReviewServer(t, "bookinfo")
+	now := time.Unix(1663752900, 0)
+	authorizer := &auth.NamespaceAuthorizer{TTL: time.Minute, Now: func() time.Time { return now }}
+	access := authorizer.For(stub.Clientset, "east", "secloudit-admin")
+
+	allowed, err := access.Allowed(context.Background(), "bookinfo", "kube-system", "bookinfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowed["bookinfo"] || allowed["kube-system"] || stub.Requests.Load() != 2 {
+		t.Errorf("unexpected access %v, requests=%d", allowed, stub.Requests.Load())
+	}
+
+	// TTL 동안은 캐시된 결과를 사용하고, 다른 사용자는 따로 확인
+	if _, err = access.Allowed(context.Background(), "bookinfo", "kube-system"); err != nil || stub.Requests.Load() != 2 {
+		t.Errorf("expected cached access, requests=%d, err=%v", stub.Requests.Load(), err)
+	}
+	if _, err = authorizer.For(stub.Clientset, "east", "other").Allowed(context.Background(), "bookinfo"); err != nil || stub.Requests.Load() != 3 {
+		t.Errorf("expected review for other user, requests=%d, err=%v", stub.Requests.Load(), err)
+	}
+	now = now.Add(time.Minute)
+	if _, err = access.Allowed(context.Background(), "bookinfo"); err != nil || stub.Requests.Load() != 4 {
+		t.Errorf("expected review after ttl, requests=%d, err=%v", stub.Requests.Load(), err)
+	}
+}
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v4"
+	"time"
+)
+
+// signingMethods 허용하는 서명 알고리즘(none 등 그 외 알고리즘은 거부)
+var signingMethods = []string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}
+
+// errUnsupportedSigningMethod 알고리즘에 해당하는 검증 키가 설정되지 않음
+var errUnsupportedSigningMethod = errors.New("unsupported signing method")
+
+// Verifier 콘솔 JWT 의 서명과 claims 를 검증
+// HMACKey 가 있으면 HS256, Keys 가 있으면 RS256 토큰을 허용하며 그 외 알고리즘(none 포함)은 거부한다.
+type Verifier struct {
+	HMACKey  []byte        // HS256 공유 키
+	Keys     KeySet        // RS256 공개키(고정 키 또는 JWKS)
+	Issuer   string        // 비어 있지 않으면 iss 가 같아야 함
+	Audience string        // 비어 있지 않으면 aud 에 포함되어야 함
+	Leeway   time.Duration // exp, nbf 검사 시 허용하는 시계 오차
+	Now      func() time.Time
+}
+
+// Verify 토큰을 검증하여 claims 를 반환
+// 서명은 jwt.ParseWithClaims 로 검증하고, claims 는 leeway 를 적용하기 위해 validate 로 검사한다.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, *Error) {
+	claims := &Claims{}
+	parser := jwt.NewParser(jwt.WithValidMethods(signingMethods), jwt.WithoutClaimsValidation())
+	if _, err := parser.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return v.key(ctx, token)
+	}); err != nil {
+		return nil, parseError(err)
+	}
+	if authErr := claims.validate(v.now(), v.Leeway, v.Issuer, v.Audience); authErr != nil {
+		return nil, authErr
+	}
+	return claims, nil
+}
+
+// key 토큰의 알고리즘에 해당하는 검증 키를 반환
+func (v *Verifier) key(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	switch {
+	case token.Method == jwt.SigningMethodHS256 && len(v.HMACKey) > 0:
+		return v.HMACKey, nil
+	case token.Method == jwt.SigningMethodRS256 && v.Keys != nil:
+		keyID, _ := token.Header["kid"].(string)
+		return v.Keys.Key(ctx, keyID)
+	default:
+		return nil, errUnsupportedSigningMethod
+	}
+}
+
+// parseError jwt 파싱 에러를 인증 에러로 변환
+func parseError(err error) *Error {
+	var validationErr *jwt.ValidationError
+	if !errors.As(err, &validationErr) {
+		return newError(ErrCodeTokenMalformed, err.Error())
+	}
+	switch {
+	case validationErr.Errors&jwt.ValidationErrorMalformed != 0:
+		return newError(ErrCodeTokenMalformed, validationErr.Error())
+	case validationErr.Errors&jwt.ValidationErrorUnverifiable != 0:
+		return newError(ErrCodeTokenUnverifiable, validationErr.Error())
+	case validationErr.Errors&jwt.ValidationErrorSignatureInvalid != 0 && validationErr.Inner == nil:
+		// WithValidMethods 에 없는 알고리즘은 서명 검증 전에 거부됨
+		return newError(ErrCodeTokenUnverifiable, errUnsupportedSigningMethod.Error())
+	case validationErr.Errors&jwt.ValidationErrorSignatureInvalid != 0:
+		return newError(ErrCodeTokenSignatureInvalid, "signature is invalid")
+	default:
+		return newError(ErrCodeTokenMalformed, validationErr.Error())
+	}
+}
+
+func (v *Verifier) now() time.Time {
+	if v.Now != nil {
+		return v.Now()
+	}
+	return time.Now()
+}
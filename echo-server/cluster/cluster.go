@@ -0,0 +1,163 @@
+// Package cluster 콘솔이 관리하는 클러스터별 Kiali 주소, 인증 설정, 상태를 관리하는 레지스트리
+package cluster
+
+import (
+	"fmt"
+	"k8s.io/client-go/kubernetes"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuthStrategy Kiali 인증 전략
+type AuthStrategy string
+
+const (
+	AnonymousStrategy = AuthStrategy("anonymous") // 인증 없이 접근(기본값)
+	TokenStrategy     = AuthStrategy("token")     // ServiceAccount 토큰 또는 고정 토큰으로 인증
+)
+
+const (
+	DefaultServiceAccountNamespace = "istio-system"
+	DefaultServiceAccountName      = "kiali-service-account"
+)
+
+// Auth 클러스터별 Kiali 인증 설정
+type Auth struct {
+	Strategy                AuthStrategy `json:"strategy,omitempty"`
+	Token                   string       `json:"token,omitempty"`                   // 고정 토큰(없으면 ServiceAccount TokenRequest 로 발급)
+	ServiceAccountNamespace string       `json:"serviceAccountNamespace,omitempty"` // 기본값 istio-system
+	ServiceAccountName      string       `json:"serviceAccountName,omitempty"`      // 기본값 kiali-service-account
+}
+
+// Cluster Kiali 를 프록시할 클러스터
+type Cluster struct {
+	Name         string               `json:"name"`
+	KialiAddress string               `json:"kialiAddress"`          // 예: http://192.168.49.100/kiali
+	KubeContext  string               `json:"kubeContext,omitempty"` // ServiceAccount 토큰 발급에 사용할 kubeconfig 컨텍스트
+	Auth         Auth                 `json:"auth"`
+	Clientset    kubernetes.Interface `json:"-"` // ServiceAccount 토큰 발급에 사용하는 클라이언트
+}
+
+// Validate 클러스터 설정을 검사
+func (c Cluster) Validate() error {
+	if c.Name == "" || strings.Contains(c.Name, "/") {
+		return fmt.Errorf("invalid cluster name, name=%q", c.Name)
+	}
+	if c.KialiAddress == "" {
+		return fmt.Errorf("kiali address is required, cluster=%s", c.Name)
+	}
+	switch c.Auth.Strategy {
+	case "", AnonymousStrategy:
+	case TokenStrategy:
+		if c.Auth.Token == "" && c.Clientset == nil {
+			return fmt.Errorf("token strategy requires a token or a kubernetes client, cluster=%s", c.Name)
+		}
+	default:
+		return fmt.Errorf("unsupported auth strategy, cluster=%s, strategy=%s", c.Name, c.Auth.Strategy)
+	}
+	return nil
+}
+
+// Status 클러스터 Kiali 의 상태 확인 결과
+type Status struct {
+	Healthy   bool      `json:"healthy"`
+	CheckedAt time.Time `json:"checkedAt,omitempty"` // 상태를 확인하지 않았으면 zero
+	Error     string    `json:"error,omitempty"`
+}
+
+// Registry 이름으로 클러스터를 찾는 레지스트리(동시 사용 가능)
+type Registry struct {
+	mutex       sync.RWMutex
+	clusters    map[string]Cluster
+	statuses    map[string]Status
+	defaultName string
+}
+
+// NewRegistry 빈 레지스트리를 생성
+func NewRegistry() *Registry {
+	return &Registry{clusters: make(map[string]Cluster), statuses: make(map[string]Status)}
+}
+
+// Add 클러스터를 등록(같은 이름이면 교체), 처음 등록한 클러스터가 기본 클러스터가 된다
+func (r *Registry) Add(cluster Cluster) error {
+	if err := cluster.Validate(); err != nil {
+		return err
+	}
+	cluster.KialiAddress = strings.TrimSuffix(cluster.KialiAddress, "/")
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.clusters[cluster.Name] = cluster
+	delete(r.statuses, cluster.Name)
+	if r.defaultName == "" {
+		r.defaultName = cluster.Name
+	}
+	return nil
+}
+
+// Remove 클러스터를 삭제
+func (r *Registry) Remove(name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.clusters, name)
+	delete(r.statuses, name)
+	if r.defaultName == name {
+		r.defaultName = ""
+	}
+}
+
+// SetDefault 클러스터를 지정하지 않은 요청에 사용할 기본 클러스터를 설정
+func (r *Registry) SetDefault(name string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.clusters[name]; !ok {
+		return fmt.Errorf("unknown cluster, cluster=%s", name)
+	}
+	r.defaultName = name
+	return nil
+}
+
+// Get 이름에 해당하는 클러스터를 반환, 이름이 비어 있으면 기본 클러스터
+func (r *Registry) Get(name string) (Cluster, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if name == "" {
+		name = r.defaultName
+	}
+	cluster, ok := r.clusters[name]
+	return cluster, ok
+}
+
+// List 등록된 클러스터를 이름 순서로 반환
+func (r *Registry) List() []Cluster {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	clusters := make([]Cluster, 0, len(r.clusters))
+	for _, cluster := range r.clusters {
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
+	return clusters
+}
+
+// Status 클러스터의 마지막 상태 확인 결과를 반환, 확인 전이면 정상으로 간주
+func (r *Registry) Status(name string) Status {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	status, ok := r.statuses[name]
+	if !ok {
+		return Status{Healthy: true}
+	}
+	return status
+}
+
+// setStatus 상태 확인 결과를 기록(확인 중 삭제된 클러스터는 무시)
+func (r *Registry) setStatus(name string, status Status) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.clusters[name]; ok {
+		r.statuses[name] = status
+	}
+}
@@ -0,0 +1,144 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	config, err := LoadConfig("testdata/clusters.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := NewRegistry()
+	if err = config.Apply(registry); err != nil {
+		t.Fatal(err)
+	}
+
+	defaultCluster, ok := registry.Get("")
+	if !ok || defaultCluster.Name != "west" || defaultCluster.Auth.Token != "static-token" {
+		t.Errorf("unexpected default cluster %+v", defaultCluster)
+	}
+	east, ok := registry.Get("east")
+	if !ok || east.KialiAddress != "http://192.168.49.100/kiali" {
+		t.Errorf("unexpected cluster %+v", east)
+	}
+	if clusters := registry.List(); len(clusters) != 2 || clusters[0].Name != "east" {
+		t.Errorf("unexpected clusters %+v", clusters)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	invalid := []Cluster{
+		{Name: "", KialiAddress: "http://kiali"},
+		{Name: "a/b", KialiAddress: "http://kiali"},
+		{Name: "east"},
+		{Name: "east", KialiAddress: "http://kiali", Auth: Auth{Strategy: TokenStrategy}},
+		{Name: "east", KialiAddress: "http://kiali", Auth: Auth{Strategy: "openid"}},
+	}
+	registry := NewRegistry()
+	for _, cluster := range invalid {
+		if err := registry.Add(cluster); err == nil {
+			t.Errorf("expected error for %+v", cluster)
+		}
+	}
+	if err := registry.SetDefault("east"); err == nil {
+		t.Error("expected error for unknown default cluster")
+	}
+}
+
+// fakeAPIServer 경로별 응답을 지정한 Kubernetes API 서버 스텁
+func fakeAPIServer(t *testing.T, responses map[string]interface{}) kubernetes.Interface {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clientset
+}
+
+func TestDiscover(t *testing.T) {
+	servicePath := "/api/v1/namespaces/istio-system/services/kiali"
+	routePath := "/apis/route.openshift.io/v1/namespaces/istio-system/routes/kiali"
+	service := func(ingress []map[string]string) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": "v1", "kind": "Service",
+			"metadata": map[string]interface{}{"name": "kiali", "namespace": "istio-system"},
+			"spec": map[string]interface{}{"ports": []map[string]interface{}{
+				{"name": "http-metrics", "port": 9090}, {"name": "http", "port": 20001},
+			}},
+			"status": map[string]interface{}{"loadBalancer": map[string]interface{}{"ingress": ingress}},
+		}
+	}
+	tests := []struct {
+		name      string
+		responses map[string]interface{}
+		address   string
+	}{
+		{"cluster ip service", map[string]interface{}{servicePath: service(nil)}, "http://kiali.istio-system.svc:20001/kiali"},
+		{"load balancer service", map[string]interface{}{servicePath: service([]map[string]string{{"ip": "192.168.49.100"}})}, "http://192.168.49.100:20001/kiali"},
+		{"openshift route", map[string]interface{}{
+			servicePath: service(nil),
+			routePath:   map[string]interface{}{"spec": map[string]interface{}{"host": "kiali.apps.example.com", "tls": map[string]string{"termination": "reencrypt"}}},
+		}, "https://kiali.apps.example.com"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cluster, err := Discover(context.Background(), "east", fakeAPIServer(t, test.responses), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cluster.KialiAddress != test.address || cluster.Auth.Strategy != TokenStrategy || cluster.Clientset == nil {
+				t.Errorf("unexpected cluster %+v", cluster)
+			}
+			if err = NewRegistry().Add(cluster); err != nil {
+				t.Errorf("discovered cluster is invalid, err=%s", err)
+			}
+		})
+	}
+
+	if _, err := Discover(context.Background(), "east", fakeAPIServer(t, nil), ""); err == nil {
+		t.Error("expected error when kiali is not installed")
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer healthy.Close()
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unhealthy.Close()
+
+	registry := NewRegistry()
+	_ = registry.Add(Cluster{Name: "east", KialiAddress: healthy.URL})
+	_ = registry.Add(Cluster{Name: "west", KialiAddress: unhealthy.URL})
+	if !registry.Status("west").Healthy {
+		t.Error("expected unchecked cluster to be healthy")
+	}
+
+	registry.CheckHealth(context.Background(), http.DefaultClient)
+	if status := registry.Status("east"); !status.Healthy || status.CheckedAt.IsZero() {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if status := registry.Status("west"); status.Healthy || status.Error == "" {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
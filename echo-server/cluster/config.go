@@ -0,0 +1,99 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"os"
+	"sigs.k8s.io/yaml"
+)
+
+// Config 클러스터 설정 파일(YAML 또는 JSON)
+/*
+defaultCluster: east
+clusters:
+  - name: east
+    kialiAddress: http://192.168.49.100/kiali
+    kubeContext: east
+    auth:
+      strategy: token
+  - name: west
+    kialiAddress: http://172.18.255.200/kiali
+*/
+type Config struct {
+	DefaultCluster string    `json:"defaultCluster,omitempty"`
+	Clusters       []Cluster `json:"clusters"`
+}
+
+// LoadConfig 클러스터 설정 파일을 읽음
+func LoadConfig(path string) (*Config, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster config, path=%s, err=%s", path, err)
+	}
+	config := &Config{}
+	if err = yaml.Unmarshal(bytes, config); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster config, path=%s, err=%s", path, err)
+	}
+	return config, nil
+}
+
+// Apply 설정의 클러스터를 레지스트리에 등록
+// token 전략이면서 고정 토큰이 없는 클러스터는 kubeContext 로 ServiceAccount 토큰을 발급할 클라이언트를 생성한다.
+func (c *Config) Apply(registry *Registry) error {
+	for _, cluster := range c.Clusters {
+		if cluster.Auth.Strategy == TokenStrategy && cluster.Auth.Token == "" && cluster.Clientset == nil {
+			clientset, err := ClientsetForContext(cluster.KubeContext)
+			if err != nil {
+				return fmt.Errorf("failed to create kubernetes client, cluster=%s, err=%s", cluster.Name, err)
+			}
+			cluster.Clientset = clientset
+		}
+		if err := registry.Add(cluster); err != nil {
+			return err
+		}
+	}
+	if c.DefaultCluster != "" {
+		return registry.SetDefault(c.DefaultCluster)
+	}
+	return nil
+}
+
+// ClientsetForContext kubeconfig 컨텍스트(비어 있으면 현재 컨텍스트)의 클라이언트를 생성
+func ClientsetForContext(kubeContext string) (kubernetes.Interface, error) {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{CurrentContext: kubeContext}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(config)
+}
+
+// DiscoverKubeconfig kubeconfig 의 모든 컨텍스트에서 Kiali 를 찾아 컨텍스트 이름을 클러스터 이름으로 등록
+// Kiali 를 찾지 못한 컨텍스트는 건너뛰고 에러 목록으로 반환한다.
+func DiscoverKubeconfig(ctx context.Context, registry *Registry, namespace string) []error {
+	rawConfig, err := clientcmd.NewDefaultClientConfigLoadingRules().Load()
+	if err != nil {
+		return []error{fmt.Errorf("failed to load kubeconfig, err=%s", err)}
+	}
+	var errs []error
+	for name := range rawConfig.Contexts {
+		clientset, err := ClientsetForContext(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to create kubernetes client, context=%s, err=%s", name, err))
+			continue
+		}
+		cluster, err := Discover(ctx, name, clientset, namespace)
+		if err == nil {
+			err = registry.Add(cluster)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if rawConfig.CurrentContext != "" {
+		_ = registry.SetDefault(rawConfig.CurrentContext)
+	}
+	return errs
+}
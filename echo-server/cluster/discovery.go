@@ -0,0 +1,105 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"net"
+	"strconv"
+)
+
+const (
+	kialiName       = "kiali"
+	kialiWebRoot    = "/kiali" // Kiali 기본 web_root
+	kialiPortName   = "http"
+	routeAPIPathFmt = "/apis/route.openshift.io/v1/namespaces/%s/routes/%s"
+)
+
+// route OpenShift Route 중 Kiali 주소를 만드는 데 필요한 필드
+type route struct {
+	Spec struct {
+		Host string `json:"host"`
+		Path string `json:"path"`
+		TLS  *struct {
+			Termination string `json:"termination"`
+		} `json:"tls"`
+	} `json:"spec"`
+}
+
+// Discover Kubernetes API 로 네임스페이스(기본값 istio-system)의 Kiali 주소를 찾아 클러스터를 생성
+// OpenShift Route(kiali)가 있으면 Route 의 호스트를, 없으면 Service(kiali)의 LoadBalancer 주소 또는 클러스터 내부 주소를 사용한다.
+// 생성한 클러스터는 clientset 으로 ServiceAccount 토큰을 발급하는 token 전략을 사용한다.
+/* Service kiali(LoadBalancer 192.168.49.100:80) => http://192.168.49.100:80/kiali
+   Service kiali(ClusterIP, port http 20001)     => http://kiali.istio-system.svc:20001/kiali
+*/
+func Discover(ctx context.Context, name string, clientset kubernetes.Interface, namespace string) (Cluster, error) {
+	if namespace == "" {
+		namespace = DefaultServiceAccountNamespace
+	}
+	cluster := Cluster{
+		Name:      name,
+		Auth:      Auth{Strategy: TokenStrategy, ServiceAccountNamespace: namespace},
+		Clientset: clientset,
+	}
+
+	address, err := routeAddress(ctx, clientset, namespace)
+	if err != nil {
+		return Cluster{}, fmt.Errorf("failed to discover kiali route, cluster=%s, err=%s", name, err)
+	}
+	if address == "" {
+		service, err := clientset.CoreV1().Services(namespace).Get(ctx, kialiName, metav1.GetOptions{})
+		if err != nil {
+			return Cluster{}, fmt.Errorf("failed to discover kiali service, cluster=%s, err=%s", name, err)
+		}
+		address = serviceAddress(service)
+	}
+	cluster.KialiAddress = address
+	return cluster, nil
+}
+
+// routeAddress OpenShift Route 로 Kiali 주소를 찾음, Route API 가 없거나 Route 가 없으면 빈 문자열
+func routeAddress(ctx context.Context, clientset kubernetes.Interface, namespace string) (string, error) {
+	body, err := clientset.Discovery().RESTClient().Get().
+		AbsPath(fmt.Sprintf(routeAPIPathFmt, namespace, kialiName)).DoRaw(ctx)
+	if errors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	var kialiRoute route
+	if err = json.Unmarshal(body, &kialiRoute); err != nil {
+		return "", err
+	}
+	scheme := "http"
+	if kialiRoute.Spec.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + kialiRoute.Spec.Host + kialiRoute.Spec.Path, nil
+}
+
+// serviceAddress Kiali Service 의 주소, LoadBalancer 주소가 있으면 외부 주소를 사용
+func serviceAddress(service *corev1.Service) string {
+	var port int32
+	for _, servicePort := range service.Spec.Ports {
+		if port == 0 || servicePort.Name == kialiPortName {
+			port = servicePort.Port
+		}
+	}
+	host := fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace)
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			host = ingress.IP
+			break
+		}
+		if ingress.Hostname != "" {
+			host = ingress.Hostname
+			break
+		}
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(int(port))) + kialiWebRoot
+}
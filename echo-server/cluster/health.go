@@ -0,0 +1,67 @@
+package cluster
+
+import (
+	"context"
+	"echo-server/logging"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// healthPath Kiali 상태 확인 API(인증 없이 호출 가능)
+const healthPath = "/api/status"
+
+// CheckHealth 모든 클러스터의 Kiali 상태 API 를 동시에 호출하여 상태를 기록
+func (r *Registry) CheckHealth(ctx context.Context, client *http.Client) {
+	var wait sync.WaitGroup
+	for _, cluster := range r.List() {
+		wait.Add(1)
+		go func(cluster Cluster) {
+			defer wait.Done()
+			status := Status{Healthy: true, CheckedAt: time.Now()}
+			if err := checkKiali(ctx, client, cluster.KialiAddress); err != nil {
+				status.Healthy = false
+				status.Error = err.Error()
+				logging.Logger.Warn().Str("cluster", cluster.Name).Err(err).Msg("kiali is unhealthy")
+			}
+			r.setStatus(cluster.Name, status)
+		}(cluster)
+	}
+	wait.Wait()
+}
+
+// StartHealthChecks 컨텍스트가 종료될 때까지 주기적으로 상태를 확인(시작 시 한 번 바로 확인)
+func (r *Registry) StartHealthChecks(ctx context.Context, interval time.Duration, client *http.Client) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			r.CheckHealth(ctx, client)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// checkKiali Kiali 상태 API 가 200 을 응답하는지 확인
+func checkKiali(ctx context.Context, client *http.Client, kialiAddress string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, kialiAddress+healthPath, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create health request, err=%s", err)
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to call kiali status, err=%s", err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected kiali status, status=%d", response.StatusCode)
+	}
+	return nil
+}
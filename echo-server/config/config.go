@@ -0,0 +1,462 @@
+// Package config echo-server 설정(설정 파일, 환경 변수, 실행 인자 순서로 덮어씀)
+package config
+
+import (
+	"echo-server/cluster"
+	"echo-server/logging"
+	"flag"
+	"fmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"os"
+	"sigs.k8s.io/yaml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config echo-server 설정 파일(YAML 또는 JSON), 기간은 "30s" 와 같은 문자열로 설정
+/*
+server:
+  address: :8443
+  tls:
+    certFile: /etc/echo-server/tls.crt
+    keyFile: /etc/echo-server/tls.key
+  shutdownTimeout: 30s
+log:
+  level: info
+  format: json
+kiali:
+  defaultCluster: east
+  clusters:
+    - name: east
+      kialiAddress: http://192.168.49.100/kiali
+  responseHeaderTimeout: 30s
+cors:
+  allowOrigins: ["https://console.example.com"]
+  allowCredentials: true
+auth:
+  jwksURL: https://console.example.com/.well-known/jwks.json
+  issuer: console
+istioWrites:
+  requireDryRun: true
+audit:
+  sink: file
+  file: /var/log/echo-server/istio-audit.jsonl
+telemetry:
+  tracing:
+    enabled: true
+    endpoint: http://otel-collector.istio-system:4318
+    sampleRatio: 0.1
+*/
+type Config struct {
+	Server ServerConfig `json:"server"`
+	Log    LogConfig    `json:"log"`
+	Kiali  KialiConfig  `json:"kiali"`
+	CORS   CORSConfig   `json:"cors"`
+	Auth   AuthConfig   `json:"auth"`
+
+	IstioWrites IstioWritesConfig `json:"istioWrites"`
+	Audit       AuditConfig       `json:"audit"`
+	Telemetry   TelemetryConfig   `json:"telemetry"`
+}
+
+// ServerConfig HTTP 서버 설정
+type ServerConfig struct {
+	Address           string          `json:"address"`
+	TLS               TLSConfig       `json:"tls"`
+	ReadHeaderTimeout metav1.Duration `json:"readHeaderTimeout"`
+	ReadTimeout       metav1.Duration `json:"readTimeout"`  // 0 이면 제한 없음
+	WriteTimeout      metav1.Duration `json:"writeTimeout"` // 0 이면 제한 없음(스트리밍 응답이 끊기지 않도록 기본값 0)
+	IdleTimeout       metav1.Duration `json:"idleTimeout"`
+	ShutdownTimeout   metav1.Duration `json:"shutdownTimeout"` // 종료 시 처리 중인 요청을 기다리는 최대 시간
+}
+
+// LogConfig 로그 설정, 레벨은 실행 중에 /admin/loglevel 로 변경 가능
+type LogConfig struct {
+	Level  string `json:"level"`  // trace, debug, info, warn, error
+	Format string `json:"format"` // json 또는 console
+}
+
+// TLSConfig 인증서와 키를 모두 설정하면 HTTPS 로 서비스
+type TLSConfig struct {
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+}
+
+// Enabled TLS 설정 여부
+func (t TLSConfig) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+// KialiConfig 프록시할 Kiali 클러스터와 업스트림 호출 설정
+// 클러스터는 설정 파일에 직접 쓰거나(clusters) 별도 클러스터 설정 파일(clustersFile)로 지정하며,
+// 둘 다 없으면 kubeconfig 의 컨텍스트에서 Kiali 를 찾는다.
+type KialiConfig struct {
+	cluster.Config
+	ClustersFile          string          `json:"clustersFile,omitempty"`
+	Namespace             string          `json:"namespace"` // kubeconfig 에서 Kiali 를 찾을 네임스페이스
+	DialTimeout           metav1.Duration `json:"dialTimeout"`
+	ResponseHeaderTimeout metav1.Duration `json:"responseHeaderTimeout"`
+	HealthCheckInterval   metav1.Duration `json:"healthCheckInterval"`
+	HealthCheckTimeout    metav1.Duration `json:"healthCheckTimeout"`
+}
+
+// CORSConfig 콘솔 웹에서 호출하기 위한 CORS 설정, AllowOrigins 가 비어 있으면 CORS 헤더를 붙이지 않음
+type CORSConfig struct {
+	AllowOrigins     []string `json:"allowOrigins,omitempty"` // "*" 이면 모든 Origin 허용
+	AllowMethods     []string `json:"allowMethods,omitempty"`
+	AllowHeaders     []string `json:"allowHeaders,omitempty"`
+	ExposeHeaders    []string `json:"exposeHeaders,omitempty"`
+	AllowCredentials bool     `json:"allowCredentials,omitempty"`
+	MaxAge           int      `json:"maxAge,omitempty"` // preflight 응답 캐시 시간(초)
+}
+
+// AuthConfig 콘솔 JWT 검증 설정, 검증 키(HMACSecret, PublicKeyFile, JWKSURL)가 하나도 없으면 인증하지 않음
+type AuthConfig struct {
+	HMACSecret    string          `json:"hmacSecret,omitempty"`    // HS256 공유 키(ECHO_SERVER_JWT_SECRET 환경 변수 권장)
+	PublicKeyFile string          `json:"publicKeyFile,omitempty"` // RS256 PEM 공개키 파일
+	JWKSURL       string          `json:"jwksURL,omitempty"`       // RS256 공개키 JWKS URL
+	Issuer        string          `json:"issuer,omitempty"`
+	Audience      string          `json:"audience,omitempty"`
+	Leeway        metav1.Duration `json:"leeway"`
+	CookieName    string          `json:"cookieName,omitempty"` // Authorization 헤더가 없을 때 토큰을 읽을 쿠키
+
+	NamespaceAuthorization NamespaceAuthorizationConfig `json:"namespaceAuthorization"`
+}
+
+// NamespaceAuthorizationConfig 인증한 사용자의 네임스페이스 접근 권한 확인(SubjectAccessReview) 설정
+// 네임스페이스에서 Resource 에 Verb 권한이 있는 사용자만 해당 네임스페이스의 Kiali 정보를 볼 수 있다.
+type NamespaceAuthorizationConfig struct {
+	Enabled  bool            `json:"enabled"`
+	Verb     string          `json:"verb"`
+	Resource string          `json:"resource"`
+	TTL      metav1.Duration `json:"ttl"` // 확인 결과 캐시 시간
+}
+
+// IstioWritesConfig Kiali 로 전달하는 Istio 설정 생성, 수정, 삭제 요청의 검증 설정
+type IstioWritesConfig struct {
+	Validate      bool            `json:"validate"`      // Istio CRD 스키마로 검증
+	RequireDryRun bool            `json:"requireDryRun"` // 같은 요청을 ?dryRun=true 로 먼저 호출해야 변경 허용
+	DryRunTTL     metav1.Duration `json:"dryRunTTL"`     // dry-run 후 변경할 수 있는 시간
+}
+
+// AuditConfig Istio 설정 변경 기록 저장소 설정, Sink 가 비어 있으면 기록하지 않음
+type AuditConfig struct {
+	Sink string         `json:"sink,omitempty"` // file 또는 sql
+	File string         `json:"file,omitempty"` // JSONL 파일 경로(sink: file)
+	SQL  AuditSQLConfig `json:"sql"`
+}
+
+// AuditSQLConfig 변경 기록을 저장할 데이터베이스 설정(sink: sql)
+type AuditSQLConfig struct {
+	Driver string `json:"driver"`
+	DSN    string `json:"dsn,omitempty"` // ECHO_SERVER_AUDIT_SQL_DSN 환경 변수 권장
+	Table  string `json:"table"`
+}
+
+// TelemetryConfig Prometheus 메트릭(/metrics)과 OpenTelemetry 트레이스 설정
+type TelemetryConfig struct {
+	Metrics MetricsConfig `json:"metrics"`
+	Tracing TracingConfig `json:"tracing"`
+}
+
+// MetricsConfig Prometheus 메트릭 설정
+type MetricsConfig struct {
+	Enabled bool `json:"enabled"` // GET /metrics 로 메트릭 제공
+}
+
+// TracingConfig OTLP/HTTP 로 span 을 내보내는 설정
+type TracingConfig struct {
+	Enabled     bool              `json:"enabled"`
+	Endpoint    string            `json:"endpoint"` // OpenTelemetry Collector OTLP/HTTP 주소
+	Headers     map[string]string `json:"headers,omitempty"`
+	ServiceName string            `json:"serviceName"`
+	SampleRatio float64           `json:"sampleRatio"` // 0~1, 부모 span 이 있으면 부모의 샘플링 여부를 따름
+	Timeout     metav1.Duration   `json:"timeout"`     // 한 번 내보낼 때의 최대 시간
+}
+
+// Enabled 검증 키 설정 여부
+func (a AuthConfig) Enabled() bool {
+	return a.HMACSecret != "" || a.PublicKeyFile != "" || a.JWKSURL != ""
+}
+
+// Default 기본 설정
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Address:           ":1323",
+			ReadHeaderTimeout: metav1.Duration{Duration: 10 * time.Second},
+			IdleTimeout:       metav1.Duration{Duration: 120 * time.Second},
+			ShutdownTimeout:   metav1.Duration{Duration: 30 * time.Second},
+		},
+		Log: LogConfig{Level: "info", Format: logging.JSONFormat},
+		Kiali: KialiConfig{
+			Namespace:             cluster.DefaultServiceAccountNamespace,
+			DialTimeout:           metav1.Duration{Duration: 5 * time.Second},
+			ResponseHeaderTimeout: metav1.Duration{Duration: 30 * time.Second},
+			HealthCheckInterval:   metav1.Duration{Duration: 30 * time.Second},
+			HealthCheckTimeout:    metav1.Duration{Duration: 5 * time.Second},
+		},
+		CORS: CORSConfig{
+			AllowMethods:  []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"},
+			AllowHeaders:  []string{"Authorization", "Content-Type", "X-Cluster", "X-Request-ID"},
+			ExposeHeaders: []string{"X-Request-ID"},
+		},
+		Auth: AuthConfig{
+			Leeway: metav1.Duration{Duration: 30 * time.Second},
+			NamespaceAuthorization: NamespaceAuthorizationConfig{
+				Enabled:  true,
+				Verb:     "list",
+				Resource: "pods",
+				TTL:      metav1.Duration{Duration: 30 * time.Second},
+			},
+		},
+		IstioWrites: IstioWritesConfig{
+			Validate:  true,
+			DryRunTTL: metav1.Duration{Duration: 10 * time.Minute},
+		},
+		Audit: AuditConfig{
+			SQL: AuditSQLConfig{Driver: "mysql", Table: "istio_config_audit"},
+		},
+		Telemetry: TelemetryConfig{
+			Metrics: MetricsConfig{Enabled: true},
+			Tracing: TracingConfig{
+				Endpoint:    "http://localhost:4318",
+				ServiceName: "echo-server",
+				SampleRatio: 1,
+				Timeout:     metav1.Duration{Duration: 10 * time.Second},
+			},
+		},
+	}
+}
+
+// setting 환경 변수와 실행 인자로 덮어쓸 수 있는 설정 항목(flag 가 비어 있으면 환경 변수로만 설정)
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	apply func(config *Config, value string) error
+}
+
+var settings = []setting{
+	{"address", "ECHO_SERVER_ADDRESS", "listen address", func(c *Config, v string) error {
+		c.Server.Address = v
+		return nil
+	}},
+	{"tls-cert-file", "ECHO_SERVER_TLS_CERT_FILE", "TLS certificate file", func(c *Config, v string) error {
+		c.Server.TLS.CertFile = v
+		return nil
+	}},
+	{"tls-key-file", "ECHO_SERVER_TLS_KEY_FILE", "TLS key file", func(c *Config, v string) error {
+		c.Server.TLS.KeyFile = v
+		return nil
+	}},
+	{"read-timeout", "ECHO_SERVER_READ_TIMEOUT", "request read timeout", durationSetter(func(c *Config) *metav1.Duration { return &c.Server.ReadTimeout })},
+	{"write-timeout", "ECHO_SERVER_WRITE_TIMEOUT", "response write timeout", durationSetter(func(c *Config) *metav1.Duration { return &c.Server.WriteTimeout })},
+	{"shutdown-timeout", "ECHO_SERVER_SHUTDOWN_TIMEOUT", "graceful shutdown timeout", durationSetter(func(c *Config) *metav1.Duration { return &c.Server.ShutdownTimeout })},
+	{"log-level", "ECHO_SERVER_LOG_LEVEL", "log level (trace, debug, info, warn, error)", func(c *Config, v string) error {
+		c.Log.Level = v
+		return nil
+	}},
+	{"log-format", "ECHO_SERVER_LOG_FORMAT", "log format (json or console)", func(c *Config, v string) error {
+		c.Log.Format = v
+		return nil
+	}},
+	{"kiali-clusters", "KIALI_CLUSTERS_CONFIG", "kiali cluster config file", func(c *Config, v string) error {
+		c.Kiali.ClustersFile = v
+		return nil
+	}},
+	{"kiali-namespace", "KIALI_NAMESPACE", "namespace to discover kiali in", func(c *Config, v string) error {
+		c.Kiali.Namespace = v
+		return nil
+	}},
+	{"kiali-response-header-timeout", "KIALI_RESPONSE_HEADER_TIMEOUT", "kiali response header timeout", durationSetter(func(c *Config) *metav1.Duration { return &c.Kiali.ResponseHeaderTimeout })},
+	{"cors-allow-origins", "ECHO_SERVER_CORS_ALLOW_ORIGINS", "comma separated CORS origins", func(c *Config, v string) error {
+		c.CORS.AllowOrigins = splitList(v)
+		return nil
+	}},
+	{"cors-allow-credentials", "ECHO_SERVER_CORS_ALLOW_CREDENTIALS", "allow CORS credentials", func(c *Config, v string) error {
+		allow, err := strconv.ParseBool(v)
+		c.CORS.AllowCredentials = allow
+		return err
+	}},
+	{"", "ECHO_SERVER_JWT_SECRET", "HS256 secret", func(c *Config, v string) error {
+		c.Auth.HMACSecret = v
+		return nil
+	}},
+	{"jwt-public-key-file", "ECHO_SERVER_JWT_PUBLIC_KEY_FILE", "RS256 PEM public key file", func(c *Config, v string) error {
+		c.Auth.PublicKeyFile = v
+		return nil
+	}},
+	{"jwks-url", "ECHO_SERVER_JWKS_URL", "RS256 JWKS URL", func(c *Config, v string) error {
+		c.Auth.JWKSURL = v
+		return nil
+	}},
+	{"istio-require-dry-run", "ECHO_SERVER_ISTIO_REQUIRE_DRY_RUN", "require dry-run before istio config writes", func(c *Config, v string) error {
+		require, err := strconv.ParseBool(v)
+		c.IstioWrites.RequireDryRun = require
+		return err
+	}},
+	{"audit-sink", "ECHO_SERVER_AUDIT_SINK", "istio config audit sink (file or sql)", func(c *Config, v string) error {
+		c.Audit.Sink = v
+		return nil
+	}},
+	{"audit-file", "ECHO_SERVER_AUDIT_FILE", "istio config audit JSONL file", func(c *Config, v string) error {
+		c.Audit.File = v
+		return nil
+	}},
+	{"tracing", "ECHO_SERVER_TRACING_ENABLED", "export opentelemetry traces", func(c *Config, v string) error {
+		enabled, err := strconv.ParseBool(v)
+		c.Telemetry.Tracing.Enabled = enabled
+		return err
+	}},
+	{"otlp-endpoint", "OTEL_EXPORTER_OTLP_ENDPOINT", "OTLP/HTTP collector endpoint", func(c *Config, v string) error {
+		c.Telemetry.Tracing.Endpoint = v
+		return nil
+	}},
+	{"", "OTEL_SERVICE_NAME", "service name of exported traces", func(c *Config, v string) error {
+		c.Telemetry.Tracing.ServiceName = v
+		return nil
+	}},
+	{"", "ECHO_SERVER_AUDIT_SQL_DSN", "istio config audit database DSN", func(c *Config, v string) error {
+		c.Audit.SQL.DSN = v
+		return nil
+	}},
+}
+
+// durationSetter 기간 설정 항목의 apply 함수를 생성
+func durationSetter(field func(c *Config) *metav1.Duration) func(c *Config, v string) error {
+	return func(c *Config, v string) error {
+		duration, err := time.ParseDuration(v)
+		field(c).Duration = duration
+		return err
+	}
+}
+
+// Load 기본 설정에 설정 파일, 환경 변수, 실행 인자 순서로 덮어쓴 설정을 반환
+// 설정 파일은 -config 인자 또는 ECHO_SERVER_CONFIG 환경 변수로 지정한다.
+/* echo-server -config /etc/echo-server/config.yaml -address :8080
+   ECHO_SERVER_ADDRESS=:8080 ECHO_SERVER_SHUTDOWN_TIMEOUT=10s echo-server
+*/
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	flags := flag.NewFlagSet("echo-server", flag.ContinueOnError)
+	path := flags.String("config", "", "config file (env ECHO_SERVER_CONFIG)")
+	values := make(map[string]*string, len(settings))
+	for _, s := range settings {
+		if s.flag != "" {
+			values[s.flag] = flags.String(s.flag, "", s.usage+" (env "+s.env+")")
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	config := Default()
+	if *path == "" {
+		*path, _ = lookupEnv("ECHO_SERVER_CONFIG")
+	}
+	if *path != "" {
+		bytes, err := os.ReadFile(*path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config, path=%s, err=%s", *path, err)
+		}
+		if err = yaml.UnmarshalStrict(bytes, config); err != nil {
+			return nil, fmt.Errorf("failed to parse config, path=%s, err=%s", *path, err)
+		}
+	}
+
+	// 실행 인자로 지정한 항목은 환경 변수보다 우선
+	set := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for _, s := range settings {
+		value, ok := lookupEnv(s.env)
+		source := "env " + s.env
+		if s.flag != "" && set[s.flag] {
+			value, ok, source = *values[s.flag], true, "flag -"+s.flag
+		}
+		if !ok || value == "" {
+			continue
+		}
+		if err := s.apply(config, value); err != nil {
+			return nil, fmt.Errorf("invalid config, source=%s, err=%s", source, err)
+		}
+	}
+	return config, config.Validate()
+}
+
+// Validate 설정을 검사
+func (c *Config) Validate() error {
+	if c.Server.Address == "" {
+		return fmt.Errorf("server address is required")
+	}
+	if c.Server.TLS.Enabled() && (c.Server.TLS.CertFile == "" || c.Server.TLS.KeyFile == "") {
+		return fmt.Errorf("both tls certFile and keyFile are required")
+	}
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		return err
+	}
+	if c.Log.Format != logging.JSONFormat && c.Log.Format != logging.ConsoleFormat {
+		return fmt.Errorf("unknown log format, format=%s", c.Log.Format)
+	}
+	durations := map[string]time.Duration{
+		"server.readHeaderTimeout":        c.Server.ReadHeaderTimeout.Duration,
+		"server.readTimeout":              c.Server.ReadTimeout.Duration,
+		"server.writeTimeout":             c.Server.WriteTimeout.Duration,
+		"server.idleTimeout":              c.Server.IdleTimeout.Duration,
+		"server.shutdownTimeout":          c.Server.ShutdownTimeout.Duration,
+		"kiali.dialTimeout":               c.Kiali.DialTimeout.Duration,
+		"kiali.responseHeaderTimeout":     c.Kiali.ResponseHeaderTimeout.Duration,
+		"kiali.healthCheckTimeout":        c.Kiali.HealthCheckTimeout.Duration,
+		"auth.leeway":                     c.Auth.Leeway.Duration,
+		"auth.namespaceAuthorization.ttl": c.Auth.NamespaceAuthorization.TTL.Duration,
+		"istioWrites.dryRunTTL":           c.IstioWrites.DryRunTTL.Duration,
+		"telemetry.tracing.timeout":       c.Telemetry.Tracing.Timeout.Duration,
+	}
+	for name, duration := range durations {
+		if duration < 0 {
+			return fmt.Errorf("negative duration, name=%s, value=%s", name, duration)
+		}
+	}
+	if c.Kiali.HealthCheckInterval.Duration <= 0 {
+		return fmt.Errorf("kiali.healthCheckInterval must be positive, value=%s", c.Kiali.HealthCheckInterval.Duration)
+	}
+	for _, origin := range c.CORS.AllowOrigins {
+		if origin == "*" && c.CORS.AllowCredentials {
+			return fmt.Errorf("cors allowOrigins \"*\" can not be used with allowCredentials")
+		}
+	}
+	if c.Auth.PublicKeyFile != "" && c.Auth.JWKSURL != "" {
+		return fmt.Errorf("auth publicKeyFile and jwksURL can not be used together")
+	}
+	if tracing := c.Telemetry.Tracing; tracing.Enabled && (tracing.Endpoint == "" || tracing.ServiceName == "") {
+		return fmt.Errorf("telemetry tracing endpoint and serviceName are required")
+	}
+	if ratio := c.Telemetry.Tracing.SampleRatio; ratio < 0 || ratio > 1 {
+		return fmt.Errorf("telemetry tracing sampleRatio must be between 0 and 1, value=%v", ratio)
+	}
+	switch c.Audit.Sink {
+	case "":
+	case "file":
+		if c.Audit.File == "" {
+			return fmt.Errorf("audit file is required for file sink")
+		}
+	case "sql":
+		if c.Audit.SQL.Driver == "" || c.Audit.SQL.DSN == "" || c.Audit.SQL.Table == "" {
+			return fmt.Errorf("audit sql driver, dsn and table are required for sql sink")
+		}
+	default:
+		return fmt.Errorf("unknown audit sink, sink=%s", c.Audit.Sink)
+	}
+	return nil
+}
+
+// splitList 쉼표로 구분된 목록을 나눔(빈 항목 제외)
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
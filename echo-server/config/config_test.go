@@ -0,0 +1,76 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func env(values map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+}
+
+func TestLoad(t *testing.T) {
+	config, err := Load(nil, env(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Server.Address != ":1323" || config.Server.ShutdownTimeout.Duration != 30*time.Second || config.Server.TLS.Enabled() {
+		t.Errorf("unexpected default config %+v", config.Server)
+	}
+
+	// 설정 파일 < 환경 변수 < 실행 인자
+	config, err = Load(
+		[]string{"-config", "testdata/config.yaml", "-shutdown-timeout", "5s"},
+		env(map[string]string{
+			"ECHO_SERVER_ADDRESS":            ":9443",
+			"ECHO_SERVER_SHUTDOWN_TIMEOUT":   "20s",
+			"ECHO_SERVER_CORS_ALLOW_ORIGINS": "https://a.example.com, https://b.example.com",
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Server.Address != ":9443" || config.Server.ShutdownTimeout.Duration != 5*time.Second {
+		t.Errorf("unexpected server config %+v", config.Server)
+	}
+	if config.Server.TLS.CertFile != "/etc/echo-server/tls.crt" || config.Server.ReadHeaderTimeout.Duration != 10*time.Second {
+		t.Errorf("unexpected server config %+v", config.Server)
+	}
+	if config.Kiali.DefaultCluster != "west" || len(config.Kiali.Clusters) != 2 || config.Kiali.ResponseHeaderTimeout.Duration != time.Minute {
+		t.Errorf("unexpected kiali config %+v", config.Kiali)
+	}
+	if len(config.CORS.AllowOrigins) != 2 || config.CORS.AllowOrigins[1] != "https://b.example.com" {
+		t.Errorf("unexpected cors config %+v", config.CORS)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+	}{
+		{"unknown flag", []string{"-unknown"}, nil},
+		{"missing file", []string{"-config", "testdata/missing.yaml"}, nil},
+		{"invalid duration", nil, map[string]string{"ECHO_SERVER_SHUTDOWN_TIMEOUT": "soon"}},
+		{"negative duration", []string{"-read-timeout", "-1s"}, nil},
+		{"tls key missing", []string{"-tls-cert-file", "tls.crt"}, nil},
+		{"wildcard origin with credentials", []string{"-cors-allow-origins", "*", "-cors-allow-credentials", "true"}, nil},
+		{"unknown log level", []string{"-log-level", "verbose"}, nil},
+		{"unknown log format", nil, map[string]string{"ECHO_SERVER_LOG_FORMAT": "xml"}},
+		{"invalid tracing flag", []string{"-tracing", "maybe"}, nil},
+		{"unknown audit sink", []string{"-audit-sink", "kafka"}, nil},
+		{"audit file missing", []string{"-audit-sink", "file"}, nil},
+		{"audit dsn missing", []string{"-audit-sink", "sql"}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Load(test.args, env(test.env)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
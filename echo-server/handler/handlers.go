@@ -1,9 +1,8 @@
 package handler
 
 import (
-	"encoding/json"
+	"github.com/kiali/kiali/models"
 	"github.com/labstack/echo/v4"
-	"log"
 	"net/http"
 )
 
@@ -12,10 +11,25 @@ func Healthz(c echo.Context) error {
 	return c.NoContent(http.StatusOK)
 }
 
-func IstioConfigDetails(c echo.Context) error {
-	var bytes = "{\"a\":1,\"b\":2,\"c\":3,\"d\":{\"e\":{\"f\":4}}}"
-	var b interface{}
-	_ = json.Unmarshal([]byte(bytes), &b)
-	log.Printf("%+v\n", b)
-	return c.JSON(http.StatusOK, b)
+// IstioConfigSummary 콘솔에서 Istio 설정 상세 화면에 사용하는 요약 정보
+type IstioConfigSummary struct {
+	Namespace  string `json:"namespace"`
+	ObjectType string `json:"objectType"`
+	Object     string `json:"object"`
+	Editable   bool   `json:"editable"`
+	Valid      *bool  `json:"valid,omitempty"`
+}
+
+// IstioConfigDetailsSummary Kiali 의 Istio 설정 상세 응답으로 콘솔 요약 정보를 생성(응답의 summary 필드로 추가)
+func IstioConfigDetailsSummary(c echo.Context, details *models.IstioConfigDetails) (interface{}, error) {
+	summary := IstioConfigSummary{
+		Namespace:  details.Namespace.Name,
+		ObjectType: details.ObjectType,
+		Object:     c.Param("object"),
+		Editable:   details.Permissions.Update,
+	}
+	if details.IstioValidation != nil {
+		summary.Valid = &details.IstioValidation.Valid
+	}
+	return summary, nil
 }
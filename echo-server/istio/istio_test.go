@@ -0,0 +1,52 @@
+package istio
+
+import (
+	"errors"
+	"testing"
+)
+
+// virtualService Kiali 위저드에서 생성하는 VirtualService(k8s-client TestCreateVirtualService 참고)
+const virtualService = `{"kind":"VirtualService","apiVersion":"networking.istio.io/v1beta1","metadata":{"namespace":"default","name":"fleetman-position-tracker","labels":{"kiali_wizard":"fault_injection"}},"spec":{"http":[{"route":[{"destination":{"host":"fleetman-position-tracker.default.svc.cluster.local","subset":"latest"},"weight":100}],"fault":{"delay":{"percentage":{"value":100},"fixedDelay":"5s"}}}],"hosts":["fleetman-position-tracker.default.svc.cluster.local"],"gateways":null}}`
+
+func TestValidate(t *testing.T) {
+	virtualServices, _ := LookupObjectType("virtualservices")
+	if err := Validate(virtualServices, "default", []byte(virtualService)); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		body      string
+	}{
+		{"invalid json", "default", `{"kind":`},
+		{"wrong kind", "default", `{"kind":"DestinationRule","apiVersion":"networking.istio.io/v1beta1","metadata":{"name":"a"},"spec":{}}`},
+		{"wrong group", "default", `{"kind":"VirtualService","apiVersion":"security.istio.io/v1beta1","metadata":{"name":"a"},"spec":{}}`},
+		{"invalid name", "default", `{"kind":"VirtualService","apiVersion":"networking.istio.io/v1beta1","metadata":{"name":"fleetma이름n"},"spec":{}}`},
+		{"other namespace", "bookinfo", virtualService},
+		{"missing spec", "default", `{"kind":"VirtualService","apiVersion":"networking.istio.io/v1beta1","metadata":{"name":"a"}}`},
+		{"unknown field", "default", `{"kind":"VirtualService","apiVersion":"networking.istio.io/v1beta1","metadata":{"name":"a"},"spec":{"hosts":["a"],"htp":[]}}`},
+		{"wrong type", "default", `{"kind":"VirtualService","apiVersion":"networking.istio.io/v1beta1","metadata":{"name":"a"},"spec":{"hosts":"a"}}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var validationError *ValidationError
+			if err := Validate(virtualServices, test.namespace, []byte(test.body)); !errors.As(err, &validationError) {
+				t.Errorf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	merged, err := MergePatch(
+		[]byte(`{"metadata":{"name":"a","labels":{"x":"1"}},"spec":{"hosts":["a"],"gateways":["g"]}}`),
+		[]byte(`{"metadata":{"labels":{"y":"2"}},"spec":{"gateways":null,"hosts":["b"]}}`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"metadata":{"labels":{"x":"1","y":"2"},"name":"a"},"spec":{"hosts":["b"]}}`; string(merged) != expected {
+		t.Errorf("expected %s, got %s", expected, merged)
+	}
+}
@@ -0,0 +1,41 @@
+package istio
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MergePatch JSON merge patch(RFC 7386)를 적용한 결과, Kiali 의 Istio 설정 수정(PATCH) 요청 본문 형식
+/* MergePatch({"spec":{"hosts":["a"],"gateways":["g"]}}, {"spec":{"gateways":null}}) => {"spec":{"hosts":["a"]}}
+ */
+func MergePatch(original []byte, patch []byte) ([]byte, error) {
+	var target, patchValue interface{}
+	if len(original) > 0 {
+		if err := json.Unmarshal(original, &target); err != nil {
+			return nil, fmt.Errorf("failed to decode original object, err=%s", err)
+		}
+	}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, fmt.Errorf("failed to decode merge patch, err=%s", err)
+	}
+	return json.Marshal(mergePatch(target, patchValue))
+}
+
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
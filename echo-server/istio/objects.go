@@ -0,0 +1,53 @@
+// Package istio Kiali API 로 변경하는 Istio 설정 객체의 종류, 스키마 검증, merge patch 처리
+package istio
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/kiali/kiali/kubernetes"
+	extensionsv1alpha1 "istio.io/api/extensions/v1alpha1"
+	networkingv1alpha3 "istio.io/api/networking/v1alpha3"
+	networkingv1beta1 "istio.io/api/networking/v1beta1"
+	securityv1beta1 "istio.io/api/security/v1beta1"
+	telemetryv1alpha1 "istio.io/api/telemetry/v1alpha1"
+)
+
+// ObjectType Kiali API 경로의 Istio 객체 종류(/api/namespaces/{namespace}/istio/{objectType})
+type ObjectType struct {
+	Name         string // Kiali 경로의 objectType(예: virtualservices)
+	Kind         string
+	APIGroup     string
+	DetailsField string // Kiali 상세 조회(models.IstioConfigDetails) 응답에서 객체가 들어 있는 필드
+	newSpec      func() proto.Message
+}
+
+// objectTypes Kiali 에서 생성, 수정, 삭제할 수 있는 Istio 객체 종류
+var objectTypes = map[string]ObjectType{}
+
+func init() {
+	for _, objectType := range []ObjectType{
+		{kubernetes.VirtualServices, kubernetes.VirtualServiceType, "networking.istio.io", "virtualService", func() proto.Message { return &networkingv1beta1.VirtualService{} }},
+		{kubernetes.DestinationRules, kubernetes.DestinationRuleType, "networking.istio.io", "destinationRule", func() proto.Message { return &networkingv1beta1.DestinationRule{} }},
+		{kubernetes.Gateways, kubernetes.GatewayType, "networking.istio.io", "gateway", func() proto.Message { return &networkingv1beta1.Gateway{} }},
+		{kubernetes.ServiceEntries, kubernetes.ServiceEntryType, "networking.istio.io", "serviceEntry", func() proto.Message { return &networkingv1beta1.ServiceEntry{} }},
+		{kubernetes.Sidecars, kubernetes.SidecarType, "networking.istio.io", "sidecar", func() proto.Message { return &networkingv1beta1.Sidecar{} }},
+		{kubernetes.WorkloadEntries, kubernetes.WorkloadEntryType, "networking.istio.io", "workloadEntry", func() proto.Message { return &networkingv1beta1.WorkloadEntry{} }},
+		{kubernetes.WorkloadGroups, kubernetes.WorkloadGroupType, "networking.istio.io", "workloadGroup", func() proto.Message { return &networkingv1beta1.WorkloadGroup{} }},
+		{kubernetes.EnvoyFilters, kubernetes.EnvoyFilterType, "networking.istio.io", "envoyFilter", func() proto.Message { return &networkingv1alpha3.EnvoyFilter{} }},
+		{kubernetes.AuthorizationPolicies, kubernetes.AuthorizationPoliciesType, "security.istio.io", "authorizationPolicy", func() proto.Message { return &securityv1beta1.AuthorizationPolicy{} }},
+		{kubernetes.PeerAuthentications, kubernetes.PeerAuthenticationsType, "security.istio.io", "peerAuthentication", func() proto.Message { return &securityv1beta1.PeerAuthentication{} }},
+		{kubernetes.RequestAuthentications, kubernetes.RequestAuthenticationsType, "security.istio.io", "requestAuthentication", func() proto.Message { return &securityv1beta1.RequestAuthentication{} }},
+		{kubernetes.Telemetries, kubernetes.TelemetryType, "telemetry.istio.io", "telemetry", func() proto.Message { return &telemetryv1alpha1.Telemetry{} }},
+		{kubernetes.WasmPlugins, kubernetes.WasmPluginType, "extensions.istio.io", "wasmPlugin", func() proto.Message { return &extensionsv1alpha1.WasmPlugin{} }},
+		// Kubernetes Gateway API 는 Istio 스키마가 없으므로 객체 형식만 검사
+		{kubernetes.K8sGateways, kubernetes.K8sActualGatewayType, "gateway.networking.k8s.io", "k8sGateway", nil},
+		{kubernetes.K8sHTTPRoutes, kubernetes.K8sActualHTTPRouteType, "gateway.networking.k8s.io", "k8sHTTPRoute", nil},
+	} {
+		objectTypes[objectType.Name] = objectType
+	}
+}
+
+// LookupObjectType Kiali 경로의 objectType 에 해당하는 객체 종류
+func LookupObjectType(name string) (ObjectType, bool) {
+	objectType, ok := objectTypes[name]
+	return objectType, ok
+}
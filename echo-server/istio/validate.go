@@ -0,0 +1,70 @@
+package istio
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/protobuf/jsonpb"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"strings"
+)
+
+// object Istio 설정 객체 중 검증에 사용하는 필드
+type object struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec json.RawMessage `json:"spec"`
+}
+
+// ValidationError 스키마 검증 실패 항목
+type ValidationError struct {
+	Errors []string `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid istio config: " + strings.Join(e.Errors, "; ")
+}
+
+// Validate Istio 설정 객체를 CRD 스키마(istio.io/api 의 proto 타입)로 검증
+// apiVersion, kind 가 objectType 과 맞는지, metadata.name 이 DNS 서브도메인 형식인지, metadata.namespace 가 경로의 네임스페이스와 같은지,
+// spec 에 스키마에 없는 필드나 타입이 맞지 않는 값이 없는지 검사한다.
+/* Validate(virtualservices, "default", {"kind":"VirtualService","apiVersion":"networking.istio.io/v1beta1","metadata":{"name":"reviews"},"spec":{"hosts":["reviews"],"htp":[]}})
+   => invalid istio config: spec: unknown field "htp" in v1beta1.VirtualService
+*/
+func Validate(objectType ObjectType, namespace string, body []byte) error {
+	var obj object
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if err := decoder.Decode(&obj); err != nil {
+		return &ValidationError{Errors: []string{"invalid json: " + err.Error()}}
+	}
+
+	var errs []string
+	if group, _, _ := strings.Cut(obj.APIVersion, "/"); group != objectType.APIGroup {
+		errs = append(errs, fmt.Sprintf("apiVersion must be in group %s, apiVersion=%s", objectType.APIGroup, obj.APIVersion))
+	}
+	if obj.Kind != objectType.Kind {
+		errs = append(errs, fmt.Sprintf("kind must be %s, kind=%s", objectType.Kind, obj.Kind))
+	}
+	for _, message := range validation.IsDNS1123Subdomain(obj.Metadata.Name) {
+		errs = append(errs, "metadata.name: "+message)
+	}
+	if obj.Metadata.Namespace != "" && obj.Metadata.Namespace != namespace {
+		errs = append(errs, fmt.Sprintf("metadata.namespace must be %s, namespace=%s", namespace, obj.Metadata.Namespace))
+	}
+	if len(obj.Spec) == 0 || string(obj.Spec) == "null" {
+		errs = append(errs, "spec is required")
+	} else if objectType.newSpec != nil {
+		unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: false}
+		if err := unmarshaler.Unmarshal(bytes.NewReader(obj.Spec), objectType.newSpec()); err != nil {
+			errs = append(errs, "spec: "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Package logging echo-server 의 구조화 로그(zerolog), 요청 ID, 접근 로그
+package logging
+
+import (
+	"context"
+	"fmt"
+	"github.com/rs/zerolog"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	JSONFormat    = "json"    // 한 줄에 JSON 하나(기본값, 로그 수집기 용)
+	ConsoleFormat = "console" // 사람이 읽기 쉬운 형식(로컬 개발 용)
+)
+
+// Logger 요청과 관계없는 로그에 사용하는 기본 로거(Configure 로 교체), 레벨은 SetLevel 의 전역 레벨로 제한
+var Logger = newLogger(os.Stderr)
+
+// Configure 로그 출력 형식과 레벨을 설정(서버 시작 시 요청을 받기 전에 호출)
+func Configure(w io.Writer, format string, level string) error {
+	switch format {
+	case "", JSONFormat:
+	case ConsoleFormat:
+		w = zerolog.ConsoleWriter{Out: w, TimeFormat: time.RFC3339}
+	default:
+		return fmt.Errorf("unknown log format, format=%s", format)
+	}
+	if err := SetLevel(level); err != nil {
+		return err
+	}
+	zerolog.TimeFieldFormat = time.RFC3339Nano
+	Logger = newLogger(w)
+	return nil
+}
+
+func newLogger(w io.Writer) zerolog.Logger {
+	return zerolog.New(w).Level(zerolog.TraceLevel).With().Timestamp().Logger()
+}
+
+// ParseLevel 로그 레벨 이름(trace, debug, info, warn, error)을 변환
+func ParseLevel(level string) (zerolog.Level, error) {
+	parsed, err := zerolog.ParseLevel(strings.ToLower(strings.TrimSpace(level)))
+	if err != nil || parsed == zerolog.NoLevel || parsed > zerolog.ErrorLevel {
+		return zerolog.NoLevel, fmt.Errorf("unknown log level, level=%s", level)
+	}
+	return parsed, nil
+}
+
+// SetLevel 모든 로거의 레벨을 변경(실행 중에 호출 가능)
+func SetLevel(level string) error {
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	zerolog.SetGlobalLevel(parsed)
+	return nil
+}
+
+// Level 현재 로그 레벨 이름
+func Level() string {
+	return zerolog.GlobalLevel().String()
+}
+
+// Ctx 요청 컨텍스트의 로거(요청 ID 포함), 요청 컨텍스트가 아니면 기본 로거
+func Ctx(ctx context.Context) *zerolog.Logger {
+	if logger := zerolog.Ctx(ctx); logger.GetLevel() != zerolog.Disabled {
+		return logger
+	}
+	return &Logger
+}
+
+// AddField 요청 로거에 필드를 추가(이후 로그와 접근 로그에 포함), 요청 컨텍스트가 아니면 무시
+func AddField(ctx context.Context, key string, value string) {
+	if logger := zerolog.Ctx(ctx); logger.GetLevel() != zerolog.Disabled {
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str(key, value)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// redacted 로그에서 비밀 값을 대신하는 문자열
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders 값을 로그에 남기지 않는 헤더(쿠키는 이름만 남김)
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "X-Auth-Token"}
+
+// sensitiveQueries 값을 로그에 남기지 않는 쿼리 파라미터
+var sensitiveQueries = []string{"token", "access_token", "id_token", "password"}
+
+// RedactHeader 인증 헤더와 쿠키 값을 가린 헤더 복사본(원본은 변경하지 않음)
+/* Authorization: Bearer eyJ...           => Authorization: [REDACTED]
+   Cookie: kiali-token=abc; lang=ko       => Cookie: kiali-token=[REDACTED]; lang=[REDACTED]
+   Set-Cookie: kiali-token=abc; Path=/    => Set-Cookie: kiali-token=[REDACTED]
+*/
+func RedactHeader(header http.Header) http.Header {
+	out := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if _, ok := out[name]; ok {
+			out[name] = []string{redacted}
+		}
+	}
+	if cookies := out.Values("Cookie"); len(cookies) > 0 {
+		var names []string
+		for _, cookie := range (&http.Request{Header: http.Header{"Cookie": cookies}}).Cookies() {
+			names = append(names, cookie.Name+"="+redacted)
+		}
+		out["Cookie"] = []string{strings.Join(names, "; ")}
+	}
+	if setCookies := out.Values("Set-Cookie"); len(setCookies) > 0 {
+		redactedCookies := make([]string, 0, len(setCookies))
+		for _, cookie := range (&http.Response{Header: http.Header{"Set-Cookie": setCookies}}).Cookies() {
+			redactedCookies = append(redactedCookies, cookie.Name+"="+redacted)
+		}
+		out["Set-Cookie"] = redactedCookies
+	}
+	return out
+}
+
+// RedactURL 토큰 쿼리 파라미터와 URL 의 사용자 정보를 가린 URL 문자열
+func RedactURL(u *url.URL) string {
+	out := *u
+	if out.User != nil {
+		out.User = url.User(redacted)
+	}
+	if out.RawQuery != "" {
+		query := out.Query()
+		changed := false
+		for _, name := range sensitiveQueries {
+			if query.Has(name) {
+				query.Set(name, redacted)
+				changed = true
+			}
+		}
+		if changed {
+			out.RawQuery = query.Encode()
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,169 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// RequestIDHeader 요청 ID 헤더, 사용자가 보낸 값이 없으면 생성하여 응답과 Kiali 요청에 붙임
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength 사용자가 보낸 요청 ID 를 그대로 사용할 최대 길이
+const maxRequestIDLength = 128
+
+// requestKey 요청 컨텍스트에 requestInfo 를 저장하는 키
+type requestKey struct{}
+
+// requestInfo 요청 ID 와 접근 로그에 남길 마지막 Kiali 호출
+type requestInfo struct {
+	id       string
+	upstream atomic.Pointer[upstreamCall]
+}
+
+// upstreamCall Kiali 호출 한 건의 결과
+type upstreamCall struct {
+	status  int
+	latency time.Duration
+	bytes   atomic.Int64
+}
+
+// quietPaths 접근 로그를 debug 레벨로 남기는 경로(probe 요청)
+var quietPaths = map[string]bool{"/livez": true, "/readyz": true}
+
+// Middleware 요청 ID 를 설정하고 요청 ID 가 포함된 로거를 요청 컨텍스트에 넣은 후, 응답이 끝나면 접근 로그를 남김
+/* {"level":"info","request_id":"4f1c...","method":"GET","uri":"/api/console/servicemesh/namespaces","status":200,
+   "bytes_in":0,"bytes_out":512,"latency":12.5,"remote_ip":"10.0.0.1","upstream_status":200,"upstream_bytes":512,
+   "upstream_latency":11.8,"time":"2023-07-01T00:00:00Z","message":"access"}
+*/
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			request := c.Request()
+			id := request.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			request.Header.Set(RequestIDHeader, id)
+			c.Response().Header().Set(RequestIDHeader, id)
+
+			info := &requestInfo{id: id}
+			logger := Logger.With().Str("request_id", id).Logger()
+			ctx := logger.WithContext(context.WithValue(request.Context(), requestKey{}, info))
+			c.SetRequest(request.WithContext(ctx))
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			response := c.Response()
+			event := logger.Info()
+			switch {
+			case response.Status >= http.StatusInternalServerError:
+				event = logger.Error()
+			case quietPaths[request.URL.Path]:
+				event = logger.Debug()
+			}
+			event = event.
+				Str("method", request.Method).
+				Str("uri", RedactURL(request.URL)).
+				Int("status", response.Status).
+				Int64("bytes_in", request.ContentLength).
+				Int64("bytes_out", response.Size).
+				Dur("latency", time.Since(start)).
+				Str("remote_ip", c.RealIP()).
+				Str("user_agent", request.UserAgent())
+			if call := info.upstream.Load(); call != nil {
+				event = event.Int("upstream_status", call.status).Int64("upstream_bytes", call.bytes.Load()).Dur("upstream_latency", call.latency)
+			}
+			event.Msg("access")
+			return nil
+		}
+	}
+}
+
+// RequestID 요청 컨텍스트의 요청 ID, 요청 컨텍스트가 아니면 빈 문자열
+func RequestID(ctx context.Context) string {
+	if info, ok := ctx.Value(requestKey{}).(*requestInfo); ok {
+		return info.id
+	}
+	return ""
+}
+
+// validRequestID 사용자가 보낸 요청 ID 를 로그와 헤더에 그대로 써도 되는지 확인
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' || r == ':') {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	bytes := make([]byte, 16)
+	_, _ = rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+// Transport Kiali 호출에 요청 ID 헤더를 붙이고 호출 결과를 로그와 접근 로그에 남기는 RoundTripper
+// 헤더는 trace 레벨에서만 남기며 인증 헤더와 쿠키 값은 가린다.
+func Transport(next http.RoundTripper) http.RoundTripper {
+	return &transport{next: next}
+}
+
+type transport struct {
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+	if id := RequestID(ctx); id != "" && request.Header.Get(RequestIDHeader) != id {
+		request = request.Clone(ctx)
+		request.Header.Set(RequestIDHeader, id)
+	}
+	logger := Ctx(ctx)
+	start := time.Now()
+	response, err := t.next.RoundTrip(request)
+	latency := time.Since(start)
+	if err != nil {
+		logger.Warn().Str("method", request.Method).Str("url", RedactURL(request.URL)).Dur("latency", latency).Err(err).Msg("kiali request failed")
+		return nil, err
+	}
+
+	event := logger.Debug().Str("method", request.Method).Str("url", RedactURL(request.URL)).Int("status", response.StatusCode).Dur("latency", latency)
+	if zerolog.GlobalLevel() <= zerolog.TraceLevel {
+		event = event.Interface("request_header", RedactHeader(request.Header)).Interface("response_header", RedactHeader(response.Header))
+	}
+	event.Msg("kiali request")
+
+	// 프로토콜 전환(101) 응답의 본문은 ReverseProxy 가 io.ReadWriteCloser 로 사용하므로 감싸지 않음
+	if info, ok := ctx.Value(requestKey{}).(*requestInfo); ok && response.StatusCode != http.StatusSwitchingProtocols {
+		call := &upstreamCall{status: response.StatusCode, latency: latency}
+		info.upstream.Store(call)
+		response.Body = &countingBody{ReadCloser: response.Body, call: call}
+	}
+	return response, nil
+}
+
+// countingBody Kiali 응답 본문 크기를 세는 ReadCloser
+type countingBody struct {
+	io.ReadCloser
+	call *upstreamCall
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.call.bytes.Add(int64(n))
+	return n, err
+}
@@ -1,17 +1,28 @@
 package main
 
 import (
-	"echo-server/manager"
-	"github.com/labstack/echo/v4"
+	"context"
+	"echo-server/config"
+	"echo-server/logging"
+	"echo-server/server"
+	"os"
 )
 
 func main() {
-	e := echo.New()
-	e.GET("/", manager.HelloWorld)
-	// Kiali API Route Root
-	kiali := e.Group("/api/console/servicemesh")
-	// 모든 요청을 하나의 Endpoint 에서 관리
-	kiali.Any("*", manager.ProxyKialiServer)
-
-	e.Logger.Fatal(e.Start(":1323"))
+	// 설정 파일(-config, ECHO_SERVER_CONFIG), 환경 변수, 실행 인자 순서로 설정을 읽음
+	cfg, err := config.Load(os.Args[1:], os.LookupEnv)
+	if err != nil {
+		logging.Logger.Fatal().Err(err).Msg("failed to load config")
+	}
+	if err = logging.Configure(os.Stderr, cfg.Log.Format, cfg.Log.Level); err != nil {
+		logging.Logger.Fatal().Err(err).Msg("failed to configure log")
+	}
+	s, err := server.New(cfg)
+	if err != nil {
+		logging.Logger.Fatal().Err(err).Msg("failed to create server")
+	}
+	// SIGINT/SIGTERM 을 받으면 처리 중인 요청을 기다린 후 종료
+	if err = s.Run(context.Background()); err != nil {
+		logging.Logger.Fatal().Err(err).Msg("server stopped with error")
+	}
 }
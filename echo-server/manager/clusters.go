@@ -0,0 +1,124 @@
+package manager
+
+import (
+	"context"
+	"echo-server/cluster"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ClusterHeader 경로에 클러스터가 없을 때 대상 클러스터를 지정하는 헤더
+const ClusterHeader = "X-Cluster"
+
+// clustersPathPrefix 경로로 대상 클러스터를 지정하는 경우의 prefix(/api/console/servicemesh/clusters/{cluster}/...)
+const clustersPathPrefix = "/clusters/"
+
+// Clusters Kiali 를 프록시할 클러스터 레지스트리(main 에서 설정 파일 또는 kubeconfig 로 등록)
+var Clusters = cluster.NewRegistry()
+
+// clusterSession 클러스터 설정별 세션 관리자(설정이 바뀌면 새로 생성)
+type clusterSession struct {
+	kialiAddress string
+	auth         cluster.Auth
+	sessions     *SessionManager
+}
+
+// clusterSessions 클러스터 이름별 clusterSession
+var clusterSessions sync.Map
+
+// resolveCluster 요청의 대상 클러스터를 경로(/clusters/{cluster}/...), ClusterHeader 헤더, 기본 클러스터 순서로 찾음
+// 경로로 지정한 경우 요청 경로에서 클러스터 세그먼트를 제거하고, 외부에 노출된 prefix 를 함께 반환한다.
+func resolveCluster(c echo.Context) (cluster.Cluster, string, error) {
+	request := c.Request()
+	name := request.Header.Get(ClusterHeader)
+	prefix := KialiApiGroupPrefix
+
+	escapedPath := request.URL.EscapedPath()
+	if rest := strings.TrimPrefix(escapedPath, KialiApiGroupPrefix+clustersPathPrefix); rest != escapedPath {
+		escapedName, remaining, _ := strings.Cut(rest, "/")
+		pathName, err := url.PathUnescape(escapedName)
+		if err != nil {
+			return cluster.Cluster{}, "", echo.NewHTTPError(http.StatusBadRequest, "invalid cluster name")
+		}
+		name = pathName
+		prefix = KialiApiGroupPrefix + clustersPathPrefix + escapedName
+		stripped := KialiApiGroupPrefix + "/" + remaining
+		request.URL.Path, _ = url.PathUnescape(stripped)
+		request.URL.RawPath = stripped
+	}
+
+	target, ok := Clusters.Get(name)
+	if !ok {
+		if name == "" {
+			return cluster.Cluster{}, "", echo.NewHTTPError(http.StatusNotFound, "no cluster is registered")
+		}
+		return cluster.Cluster{}, "", echo.NewHTTPError(http.StatusNotFound, "unknown cluster "+name)
+	}
+	if status := Clusters.Status(target.Name); !status.Healthy {
+		return cluster.Cluster{}, "", echo.NewHTTPError(http.StatusServiceUnavailable, "kiali is unhealthy, cluster="+target.Name+", err="+status.Error)
+	}
+	return target, prefix, nil
+}
+
+// sessionsFor 클러스터의 세션 관리자를 반환, 클러스터의 Kiali 주소나 인증 설정이 바뀌었으면 새로 생성
+func sessionsFor(target cluster.Cluster) *SessionManager {
+	if value, ok := clusterSessions.Load(target.Name); ok {
+		existing := value.(*clusterSession)
+		if existing.kialiAddress == target.KialiAddress && existing.auth == target.Auth {
+			return existing.sessions
+		}
+	}
+	sessions := NewSessionManager(tokenSourceFor(target))
+	sessions.Transport = kialiTransport
+	clusterSessions.Store(target.Name, &clusterSession{kialiAddress: target.KialiAddress, auth: target.Auth, sessions: sessions})
+	return sessions
+}
+
+// tokenSourceFor 클러스터의 인증 설정에 따른 TokenSource, anonymous 전략이면 nil
+func tokenSourceFor(target cluster.Cluster) TokenSource {
+	if target.Auth.Strategy != cluster.TokenStrategy {
+		return nil
+	}
+	if target.Auth.Token != "" {
+		token := target.Auth.Token
+		return TokenSourceFunc(func(ctx context.Context) (string, time.Time, error) {
+			return token, time.Time{}, nil
+		})
+	}
+	namespace, name := target.Auth.ServiceAccountNamespace, target.Auth.ServiceAccountName
+	if namespace == "" {
+		namespace = cluster.DefaultServiceAccountNamespace
+	}
+	if name == "" {
+		name = cluster.DefaultServiceAccountName
+	}
+	return ServiceAccountTokenSource{Clientset: target.Clientset, Namespace: namespace, Name: name}
+}
+
+// ClusterInfo 클러스터 목록 API 의 응답 항목
+type ClusterInfo struct {
+	Name         string         `json:"name"`
+	KialiAddress string         `json:"kialiAddress"`
+	Default      bool           `json:"default"`
+	Status       cluster.Status `json:"status"`
+}
+
+// ListClusters 등록된 클러스터와 상태를 반환
+func ListClusters(c echo.Context) error {
+	defaultCluster, _ := Clusters.Get("")
+	clusters := Clusters.List()
+	infos := make([]ClusterInfo, 0, len(clusters))
+	for _, item := range clusters {
+		infos = append(infos, ClusterInfo{
+			Name:         item.Name,
+			KialiAddress: item.KialiAddress,
+			Default:      item.Name == defaultCluster.Name,
+			Status:       Clusters.Status(item.Name),
+		})
+	}
+	return c.JSON(http.StatusOK, infos)
+}
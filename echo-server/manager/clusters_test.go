@@ -0,0 +1,98 @@
+package manager
+
+import (
+	"context"
+	"echo-server/cluster"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// namedKiali 요청 경로 앞에 클러스터 이름을 붙여 응답하는 Kiali 스텁 서버
+func namedKiali(t *testing.T, name string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/kiali/api/authenticate":
+			if name == "west" && r.FormValue("token") != "west-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		case "/kiali/api/status":
+			if name == "down" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		default:
+			_, _ = w.Write([]byte(name + " " + r.URL.EscapedPath() + " " + r.Header.Get("X-Forwarded-Prefix") + " " + r.Header.Get(ClusterHeader)))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL + "/kiali"
+}
+
+func TestProxyKialiServerClusters(t *testing.T) {
+	useCluster(t, cluster.Cluster{Name: "west", KialiAddress: namedKiali(t, "west"),
+		Auth: cluster.Auth{Strategy: cluster.TokenStrategy, Token: "west-token"}})
+	useCluster(t, cluster.Cluster{Name: "down", KialiAddress: namedKiali(t, "down")})
+	useCluster(t, cluster.Cluster{Name: "east", KialiAddress: namedKiali(t, "east")})
+	Clusters.CheckHealth(context.Background(), http.DefaultClient)
+	// 클러스터 선택만 확인하도록 응답을 변환하는 proxy 없이 실행
+	withProxies(t)
+	server := newTestServer()
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		status int
+		body   string
+	}{
+		{"default cluster", "/namespaces", "", http.StatusOK, "east /kiali/api/namespaces /api/console/servicemesh "},
+		{"header", "/namespaces", "west", http.StatusOK, "west /kiali/api/namespaces /api/console/servicemesh "},
+		{"path", "/clusters/west/namespaces/a%2Fb", "", http.StatusOK, "west /kiali/api/namespaces/a%2Fb /api/console/servicemesh/clusters/west "},
+		{"path overrides header", "/clusters/east/namespaces", "west", http.StatusOK, "east /kiali/api/namespaces /api/console/servicemesh/clusters/east "},
+		{"unknown cluster", "/clusters/north/namespaces", "", http.StatusNotFound, ""},
+		{"unhealthy cluster", "/namespaces", "down", http.StatusServiceUnavailable, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodGet, server.URL+KialiApiGroupPrefix+test.path, nil)
+			if test.header != "" {
+				request.Header.Set(ClusterHeader, test.header)
+			}
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+			body, _ := io.ReadAll(response.Body)
+			if response.StatusCode != test.status {
+				t.Fatalf("expected status %d, got %d (%s)", test.status, response.StatusCode, body)
+			}
+			if test.body != "" && string(body) != test.body {
+				t.Errorf("expected body %q, got %q", test.body, body)
+			}
+		})
+	}
+}
+
+func TestListClusters(t *testing.T) {
+	useCluster(t, cluster.Cluster{Name: "down", KialiAddress: namedKiali(t, "down")})
+	useCluster(t, cluster.Cluster{Name: "east", KialiAddress: namedKiali(t, "east")})
+	Clusters.CheckHealth(context.Background(), http.DefaultClient)
+
+	recorder := httptest.NewRecorder()
+	e := newTestEcho()
+	if err := ListClusters(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), recorder)); err != nil {
+		t.Fatal(err)
+	}
+	var infos []ClusterInfo
+	if err := json.Unmarshal(recorder.Body.Bytes(), &infos); err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 2 || infos[0].Name != "down" || infos[0].Status.Healthy || !infos[1].Default || !infos[1].Status.Healthy {
+		t.Errorf("unexpected clusters %+v", infos)
+	}
+}
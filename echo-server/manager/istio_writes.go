@@ -0,0 +1,287 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"echo-server/audit"
+	"echo-server/auth"
+	"echo-server/cluster"
+	"echo-server/istio"
+	"echo-server/logging"
+	"echo-server/util/url"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"sync"
+	"time"
+)
+
+const (
+	dryRunQuery         = "dryRun" // ?dryRun=true 이면 Kiali 로 전달하지 않고 검증 결과와 변경 내용만 응답
+	maxIstioObjectSize  = 1 << 20  // Istio 설정 변경 요청 본문 최대 크기
+	defaultDryRunTTL    = 10 * time.Minute
+	anonymousAuditActor = "anonymous"
+)
+
+// istioObjectPath Kiali 의 Istio 설정 생성(POST .../istio/{objectType}), 수정(PATCH), 삭제(DELETE .../istio/{objectType}/{object}) 경로
+var istioObjectPath = urlpath.New("/api/namespaces/:namespace/istio/:objectType/:object?")
+
+// IstioWriteGuard Istio 설정 변경 요청의 스키마 검증, dry-run 강제, 변경 기록 설정
+type IstioWriteGuard struct {
+	Validate      bool          // 생성, 수정 결과를 Istio CRD 스키마로 검증
+	RequireDryRun bool          // 같은 사용자가 같은 요청을 DryRunTTL 안에 dry-run 한 경우에만 변경 허용
+	DryRunTTL     time.Duration // 기본값 10분
+	Sink          audit.Sink    // nil 이면 기록하지 않음
+	Now           func() time.Time
+
+	mutex   sync.Mutex
+	dryRuns map[string]time.Time // dry-run 한 요청별 만료 시간
+}
+
+// IstioWrites Istio 설정 변경 요청 처리 설정(main 에서 설정 파일로 교체)
+var IstioWrites = &IstioWriteGuard{Validate: true}
+
+// IstioWriteError Istio 설정 변경 요청 거부 응답
+/* 422 {"code":"invalid_object","message":"invalid istio config","errors":["spec: unknown field \"htp\" in v1beta1.VirtualService"]}
+   428 {"code":"dry_run_required","message":"run the same request with ?dryRun=true first"}
+*/
+type IstioWriteError struct {
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// DryRunResult dry-run 요청 응답
+type DryRunResult struct {
+	DryRun    bool            `json:"dryRun"`
+	Action    audit.Action    `json:"action"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	Diff      []audit.Change  `json:"diff"`
+	ExpiresAt *time.Time      `json:"expiresAt,omitempty"` // RequireDryRun 이면 이 시간 전까지 같은 요청으로 변경할 수 있음
+}
+
+// istioWrite 처리 중인 Istio 설정 변경 요청
+type istioWrite struct {
+	record audit.Record
+}
+
+// begin Istio 설정 변경 요청이면 변경 전후 객체를 구하고 검증, dry-run 을 처리
+// 요청을 직접 응답했으면(dry-run, 거부) handled 가 true 이고, Kiali 로 전달해야 하면 기록을 마칠 때 사용할 istioWrite 를 반환한다.
+func (g *IstioWriteGuard) begin(c echo.Context, target cluster.Cluster) (write *istioWrite, handled bool, err error) {
+	request := c.Request()
+	match, ok := istioObjectPath.Match(kialiAPIPath(request.URL.EscapedPath()))
+	if !ok {
+		return nil, false, nil
+	}
+	action, ok := istioWriteAction(request.Method, match.Params["object"])
+	if !ok {
+		return nil, false, nil
+	}
+	record := audit.Record{
+		Time:       g.now(),
+		User:       auditActor(c),
+		RemoteAddr: c.RealIP(),
+		Cluster:    target.Name,
+		Namespace:  match.Params["namespace"],
+		ObjectType: match.Params["objectType"],
+		Object:     match.Params["object"],
+		Action:     action,
+		DryRun:     request.URL.Query().Get(dryRunQuery) == "true",
+	}
+
+	body, err := io.ReadAll(io.LimitReader(request.Body, maxIstioObjectSize+1))
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read request body, err=%s", err)
+	}
+	if len(body) > maxIstioObjectSize {
+		return nil, true, g.reject(c, record, http.StatusRequestEntityTooLarge, IstioWriteError{Code: "object_too_large", Message: "istio config is too large"})
+	}
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	request.ContentLength = int64(len(body))
+
+	objectType, ok := istio.LookupObjectType(record.ObjectType)
+	if !ok {
+		return nil, true, g.reject(c, record, http.StatusBadRequest, IstioWriteError{Code: "unsupported_object_type", Message: "unsupported istio object type " + record.ObjectType})
+	}
+	if action != audit.ActionCreate {
+		before, err := fetchIstioObject(request.Context(), target, objectType, record.Namespace, record.Object)
+		if err != nil {
+			return nil, true, c.JSON(http.StatusBadGateway, Error{Message: err.Error()})
+		}
+		record.Before = audit.Strip(before)
+	}
+	switch action {
+	case audit.ActionCreate:
+		record.After = audit.Strip(body)
+	case audit.ActionUpdate:
+		merged, err := istio.MergePatch(record.Before, body)
+		if err != nil {
+			return nil, true, g.reject(c, record, http.StatusBadRequest, IstioWriteError{Code: "invalid_patch", Message: err.Error()})
+		}
+		record.After = merged
+	}
+	record.Diff = audit.Diff(record.Before, record.After)
+
+	if g.Validate && action != audit.ActionDelete {
+		if err = istio.Validate(objectType, record.Namespace, record.After); err != nil {
+			validationErrors := []string{err.Error()}
+			var validationError *istio.ValidationError
+			if errors.As(err, &validationError) {
+				validationErrors = validationError.Errors
+			}
+			return nil, true, g.reject(c, record, http.StatusUnprocessableEntity, IstioWriteError{Code: "invalid_object", Message: "invalid istio config", Errors: validationErrors})
+		}
+	}
+
+	key := dryRunKey(record, request.Method, body)
+	if record.DryRun {
+		result := DryRunResult{DryRun: true, Action: action, Before: record.Before, After: record.After, Diff: record.Diff}
+		if g.RequireDryRun {
+			expiresAt := g.rememberDryRun(key)
+			result.ExpiresAt = &expiresAt
+		}
+		record.Status = http.StatusOK
+		g.write(request.Context(), record)
+		return nil, true, c.JSON(http.StatusOK, result)
+	}
+	if g.RequireDryRun && !g.consumeDryRun(key) {
+		return nil, true, g.reject(c, record, http.StatusPreconditionRequired, IstioWriteError{Code: "dry_run_required", Message: "run the same request with ?dryRun=true first"})
+	}
+	return &istioWrite{record: record}, false, nil
+}
+
+// finish Kiali 의 응답 상태로 변경 기록을 저장
+func (g *IstioWriteGuard) finish(c echo.Context, write *istioWrite) {
+	write.record.Status = c.Response().Status
+	if write.record.Status >= http.StatusBadRequest {
+		write.record.Error = http.StatusText(write.record.Status)
+	}
+	g.write(c.Request().Context(), write.record)
+}
+
+// reject 거부한 요청을 기록하고 에러를 응답
+func (g *IstioWriteGuard) reject(c echo.Context, record audit.Record, status int, response IstioWriteError) error {
+	record.Status = status
+	record.Error = response.Message
+	g.write(c.Request().Context(), record)
+	return c.JSON(status, response)
+}
+
+// write 변경 기록을 저장, 저장하지 못해도 요청 처리는 계속함
+// 클라이언트가 연결을 끊어도 기록은 남겨야 하므로 요청 컨텍스트는 로그에만 사용한다.
+func (g *IstioWriteGuard) write(ctx context.Context, record audit.Record) {
+	if g.Sink == nil {
+		return
+	}
+	if err := g.Sink.Write(context.Background(), record); err != nil {
+		logging.Ctx(ctx).Error().Str("user", record.User).Str("cluster", record.Cluster).
+			Str("object", record.Namespace+"/"+record.ObjectType+"/"+record.Object).Err(err).Msg("failed to write audit record")
+	}
+}
+
+// rememberDryRun dry-run 한 요청을 저장하고 만료 시간을 반환
+func (g *IstioWriteGuard) rememberDryRun(key string) time.Time {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	now := g.now()
+	if g.dryRuns == nil {
+		g.dryRuns = make(map[string]time.Time)
+	}
+	for cachedKey, expiresAt := range g.dryRuns {
+		if !now.Before(expiresAt) {
+			delete(g.dryRuns, cachedKey)
+		}
+	}
+	ttl := g.DryRunTTL
+	if ttl <= 0 {
+		ttl = defaultDryRunTTL
+	}
+	g.dryRuns[key] = now.Add(ttl)
+	return g.dryRuns[key]
+}
+
+// consumeDryRun 만료되지 않은 dry-run 이 있으면 삭제하고 true(한 번의 dry-run 으로 한 번만 변경)
+func (g *IstioWriteGuard) consumeDryRun(key string) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	expiresAt, ok := g.dryRuns[key]
+	delete(g.dryRuns, key)
+	return ok && g.now().Before(expiresAt)
+}
+
+func (g *IstioWriteGuard) now() time.Time {
+	if g.Now != nil {
+		return g.Now()
+	}
+	return time.Now()
+}
+
+// istioWriteAction 메소드와 객체 이름으로 변경 종류를 구함, 변경 요청이 아니면 false
+func istioWriteAction(method string, object string) (audit.Action, bool) {
+	switch {
+	case method == http.MethodPost && object == "":
+		return audit.ActionCreate, true
+	case (method == http.MethodPatch || method == http.MethodPut) && object != "":
+		return audit.ActionUpdate, true
+	case method == http.MethodDelete && object != "":
+		return audit.ActionDelete, true
+	}
+	return "", false
+}
+
+// auditActor 기록에 남길 사용자(인증하지 않으면 anonymous)
+func auditActor(c echo.Context) string {
+	if claims, ok := auth.ClaimsFrom(c); ok {
+		return claims.Username()
+	}
+	return anonymousAuditActor
+}
+
+// dryRunKey 같은 사용자가 같은 클러스터에 보낸 같은 요청을 구분하는 키
+func dryRunKey(record audit.Record, method string, body []byte) string {
+	hash := sha256.New()
+	for _, part := range []string{record.User, record.Cluster, method, record.Namespace, record.ObjectType, record.Object} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// fetchIstioObject Kiali 상세 조회 API 로 현재 객체를 조회, 객체가 없으면 nil
+/* GET {kialiAddress}/api/namespaces/default/istio/virtualservices/reviews => {"virtualService":{...},"permissions":{...},...}
+ */
+func fetchIstioObject(ctx context.Context, target cluster.Cluster, objectType istio.ObjectType, namespace string, object string) (json.RawMessage, error) {
+	address := target.KialiAddress + "/api/namespaces/" + neturl.PathEscape(namespace) + "/istio/" + objectType.Name + "/" + neturl.PathEscape(object)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create istio config request, err=%s", err)
+	}
+	client := &http.Client{Transport: &sessionTransport{sessions: sessionsFor(target), kialiServerAddress: target.KialiAddress, next: kialiTransport}}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current istio config, err=%s", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get current istio config, status=%d", response.StatusCode)
+	}
+	var details map[string]json.RawMessage
+	if err = json.NewDecoder(response.Body).Decode(&details); err != nil {
+		return nil, fmt.Errorf("failed to decode current istio config, err=%s", err)
+	}
+	if current := details[objectType.DetailsField]; len(current) > 0 && string(current) != "null" {
+		return current, nil
+	}
+	return nil, nil
+}
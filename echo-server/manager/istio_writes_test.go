@@ -0,0 +1,134 @@
+package manager
+
+import (
+	"context"
+	"echo-server/audit"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink 기록을 메모리에 보관하는 테스트 저장소
+type recordingSink struct {
+	mutex   sync.Mutex
+	records []audit.Record
+}
+
+func (s *recordingSink) Write(ctx context.Context, record audit.Record) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *recordingSink) last(t *testing.T) audit.Record {
+	t.Helper()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.records) == 0 {
+		t.Fatal("no audit record")
+	}
+	return s.records[len(s.records)-1]
+}
+
+const reviewsVirtualService = `{"kind":"VirtualService","apiVersion":"networking.istio.io/v1beta1","metadata":{"namespace":"default","name":"reviews","resourceVersion":"12"},"spec":{"hosts":["reviews"],"http":[{"route":[{"destination":{"host":"reviews","subset":"v1"}}]}]}}`
+
+func TestProxyKialiServerIstioWrites(t *testing.T) {
+	var forwarded []string
+	var mutex sync.Mutex
+	stubKiali(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			if r.URL.Path != "/kiali/api/namespaces/default/istio/virtualservices/reviews" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = io.WriteString(w, `{"namespace":{"name":"default"},"objectType":"virtualservices","virtualService":`+reviewsVirtualService+`}`)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mutex.Lock()
+		forwarded = append(forwarded, r.Method+" "+r.URL.Path+" "+string(body))
+		mutex.Unlock()
+		_, _ = io.WriteString(w, `{}`)
+	})
+	sink := &recordingSink{}
+	now := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	original := IstioWrites
+	IstioWrites = &IstioWriteGuard{Validate: true, RequireDryRun: true, Sink: sink, Now: func() time.Time { return now }}
+	defer func() { IstioWrites = original }()
+	e := newTestEcho()
+
+	send := func(method string, path string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, KialiApiGroupPrefix+path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		return recorder
+	}
+	patch := `{"spec":{"http":[{"route":[{"destination":{"host":"reviews","subset":"v2"}}]}]}}`
+	reviews := "/namespaces/default/istio/virtualservices/reviews"
+
+	// 스키마에 맞지 않는 변경은 전달하지 않음
+	recorder := send(http.MethodPatch, reviews, `{"spec":{"htp":[]}}`)
+	if recorder.Code != http.StatusUnprocessableEntity || !strings.Contains(recorder.Body.String(), "invalid_object") {
+		t.Fatalf("expected 422, got %d %s", recorder.Code, recorder.Body)
+	}
+	if record := sink.last(t); record.Status != http.StatusUnprocessableEntity || record.User != anonymousAuditActor || record.Cluster != "test" {
+		t.Errorf("unexpected audit record %+v", record)
+	}
+
+	// dry-run 없이 변경 거부
+	if recorder = send(http.MethodPatch, reviews, patch); recorder.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected 428, got %d %s", recorder.Code, recorder.Body)
+	}
+
+	// dry-run 은 변경 내용만 응답
+	recorder = send(http.MethodPatch, reviews+"?dryRun=true", patch)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d %s", recorder.Code, recorder.Body)
+	}
+	var result DryRunResult
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.DryRun || result.Action != audit.ActionUpdate || len(result.Diff) != 1 || result.Diff[0].Path != "/spec/http" {
+		t.Errorf("unexpected dry-run result %s", recorder.Body)
+	}
+	if strings.Contains(string(result.Before), "resourceVersion") {
+		t.Errorf("server managed fields not stripped %s", result.Before)
+	}
+
+	// 같은 요청은 한 번만 변경 가능
+	if recorder = send(http.MethodPatch, reviews, patch); recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d %s", recorder.Code, recorder.Body)
+	}
+	record := sink.last(t)
+	if record.DryRun || record.Action != audit.ActionUpdate || record.Status != http.StatusOK || record.Object != "reviews" || len(record.Diff) != 1 {
+		t.Errorf("unexpected audit record %+v", record)
+	}
+	if recorder = send(http.MethodPatch, reviews, patch); recorder.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected 428, got %d %s", recorder.Code, recorder.Body)
+	}
+	if len(forwarded) != 1 || forwarded[0] != "PATCH /kiali/api/namespaces/default/istio/virtualservices/reviews "+patch {
+		t.Errorf("unexpected forwarded requests %v", forwarded)
+	}
+
+	// 삭제는 검증 없이 변경 전 객체를 기록
+	IstioWrites.RequireDryRun = false
+	if recorder = send(http.MethodDelete, reviews, ""); recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d %s", recorder.Code, recorder.Body)
+	}
+	if record = sink.last(t); record.Action != audit.ActionDelete || record.Before == nil || record.After != nil {
+		t.Errorf("unexpected audit record %+v", record)
+	}
+
+	// 지원하지 않는 객체 종류
+	if recorder = send(http.MethodPost, "/namespaces/default/istio/unknowns", `{}`); recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d %s", recorder.Code, recorder.Body)
+	}
+}
@@ -1,24 +1,44 @@
 package manager
 
 import (
+	"echo-server/auth"
+	"echo-server/logging"
 	"echo-server/proxy"
-	urlpath "echo-server/util/url"
+	"echo-server/telemetry"
 	_ "encoding/json"
 	_ "errors"
-	"fmt"
-	"github.com/imroc/req/v3"
 	_ "github.com/kiali/kiali/models"
 	"github.com/labstack/echo/v4"
-	"github.com/thoas/go-funk"
-	"io"
-	"log"
 	"net/http"
-	"strings"
+	"sync/atomic"
 )
 
-var KialiServerAddress = "http://192.168.49.100/kiali"
 var KialiApiGroupPrefix = "/api/console/servicemesh"
 
+// proxyRouter proxy.Proxies 를 컴파일한 라우터(SetProxies 로 설정, 설정 전이면 첫 요청에서 생성)
+var proxyRouter atomic.Pointer[proxy.Router]
+
+// SetProxies proxy 목록으로 라우터를 생성하여 교체, 패턴이 충돌하면 에러(서버 시작 시 호출)
+func SetProxies(proxies []proxy.Proxy) error {
+	router, err := proxy.NewRouter(proxies)
+	if err != nil {
+		return err
+	}
+	proxyRouter.Store(router)
+	return nil
+}
+
+// currentRouter 현재 라우터를 반환, 아직 없으면 proxy.Proxies 로 생성
+func currentRouter() (*proxy.Router, error) {
+	if router := proxyRouter.Load(); router != nil {
+		return router, nil
+	}
+	if err := SetProxies(*proxy.Proxies); err != nil {
+		return nil, err
+	}
+	return proxyRouter.Load(), nil
+}
+
 type Error struct {
 	Message string `json:"message"`
 }
@@ -27,86 +47,72 @@ func HelloWorld(c echo.Context) error {
 	return c.String(http.StatusOK, "Hello, World!")
 }
 
-func setCookies(c echo.Context) {
-	var bodyParams []byte
-	if c.Request().Body != nil {
-		bodyParams, _ = io.ReadAll(c.Request().Body)
-	}
-	requestURL := KialiServerAddress + "/api/authenticate"
-	res, _ := req.C().R().
-		EnableDump().
-		SetContentType(c.Request().Header.Get("Content-Type")).
-		SetBody(bodyParams).
-		Post(requestURL)
-
-	for _, cookie := range res.Cookies() {
-		fmt.Println(cookie)
-		c.SetCookie(cookie)
-	}
-}
-
 // ProxyKialiServer 사용자의 요청을 Kiali 로 프록시 처리한 후 응답값을 처리하여 반환
 func ProxyKialiServer(c echo.Context) error {
-	log.Println("Start ProxyKialiServer")
-	defer func() {
-		log.Println("End ProxyKialiServer")
-	}()
-
-	// 세션 획득
-	setCookies(c)
+	// 대상 클러스터 선택(경로로 지정한 경우 요청 경로에서 클러스터 세그먼트 제거)
+	target, forwardedPrefix, err := resolveCluster(c)
+	if err != nil {
+		return err
+	}
+	ctx := c.Request().Context()
+	logging.AddField(ctx, "cluster", target.Name)
+	telemetry.SetCluster(ctx, target.Name)
+	if claims, ok := auth.ClaimsFrom(c); ok {
+		logging.AddField(ctx, "user", claims.Username())
+	}
 
 	// proxy handler 를 정의한 경우 정의한 handler 에서 사용자의 요청을 처리하여 응답함
-	if find := funk.Find(*proxy.Proxies, func(proxy proxy.Proxy) bool {
-		pattern := urlpath.New(proxy.Pattern)
-		_, patternOK := pattern.Match("/api" + strings.TrimPrefix(c.Request().URL.Path, KialiApiGroupPrefix))
-		methodOK := proxy.Method == c.Request().Method
-		return patternOK && methodOK
-	}); find != nil {
-		log.Println("Run Proxy HandlerFunc")
-		// 핸들러에 컨텍스트 위임
-		_ = find.(proxy.Proxy).HandlerFunc(c)
-	} else {
-		log.Println("정의된 proxy 패턴 없음 kiali 로 전달하여 응답값 그대로 전달 할 것")
-		requestURL := KialiServerAddress + "/api" + strings.TrimPrefix(c.Request().RequestURI, KialiApiGroupPrefix)
-		log.Println("RequestURL: ", requestURL)
-		client := req.C().R()
-		var res *req.Response
-		var err error
-
-		switch c.Request().Method {
-		case "GET":
-			res, err = req.C().R().
-				EnableDump().
-				//SetSuccessResult(&result).
-				Get(requestURL)
-		case "POST":
-			var bodyParams []byte
-			if c.Request().Body != nil {
-				bodyParams, _ = io.ReadAll(c.Request().Body)
-			}
-			res, err = client.
-				EnableDump().
-				SetContentType(c.Request().Header.Get("Content-Type")).
-				SetBody(bodyParams).
-				Post(requestURL)
+	router, err := currentRouter()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, Error{Message: err.Error()})
+	}
+	find, params, ok := router.Match(c.Request().Method, kialiAPIPath(c.Request().URL.EscapedPath()))
+	telemetry.SetRoute(ctx, telemetry.UnmatchedRoute)
+	if ok {
+		// 패턴에서 추출한 경로 파라미터를 컨텍스트에 설정(핸들러와 훅에서 c.Param 으로 사용)
+		c.SetParamNames(params.Names...)
+		c.SetParamValues(params.Values...)
+		logging.AddField(ctx, "proxy", find.Name)
+		telemetry.SetRoute(ctx, find.Name)
+		// 경로에 역할이 지정되어 있으면 사용자의 역할 확인
+		if authErr := auth.Authorize(c, find.Roles); authErr != nil {
+			return auth.WriteError(c, authErr)
 		}
+	}
+	// 사용자가 접근할 수 없는 네임스페이스 요청 거부
+	if authErr := authorizeNamespaces(c, target, ok && find.ResponseHook != nil); authErr != nil {
+		return auth.WriteError(c, authErr)
+	}
+	// Istio 설정 변경 요청은 검증, dry-run 확인 후 전달하고 결과를 기록
+	write, handled, err := IstioWrites.begin(c, target)
+	if handled {
+		return err
+	}
+	if write != nil {
+		defer IstioWrites.finish(c, write)
+	}
+	if ok && find.HandlerFunc != nil {
+		logging.Ctx(ctx).Debug().Msg("run proxy handler")
+		// 핸들러에 컨텍스트 위임
+		return find.HandlerFunc(c)
+	}
 
-		if err != nil {
-			return c.NoContent(http.StatusInternalServerError)
-		}
-		if res.IsErrorState() {
-			return c.JSON(res.StatusCode, res.Status)
-		}
-		if res.IsSuccessState() {
-			body, err := io.ReadAll(res.Body)
-			if err != nil {
-				fmt.Println(err)
-			}
-			defer func(Body io.ReadCloser) {
-				_ = Body.Close()
-			}(res.Body)
-			return c.String(res.StatusCode, string(body))
+	// 핸들러가 없으면 클러스터의 kiali 로 전달하여 응답값을 그대로 전달(세션은 클러스터별로 캐시하여 첨부)
+	reverseProxy, err := newKialiReverseProxy(target.KialiAddress, sessionsFor(target), forwardedPrefix)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, Error{Message: err.Error()})
+	}
+	if ok && find.RequestHook != nil {
+		if err = find.RequestHook(c, c.Request()); err != nil {
+			return err
 		}
 	}
+	if ok && find.ResponseHook != nil {
+		logging.Ctx(ctx).Debug().Msg("run proxy response hook")
+		// 응답 본문을 변환해야 하므로 압축되지 않은 본문을 받도록 사용자의 Accept-Encoding 은 전달하지 않음
+		c.Request().Header.Del("Accept-Encoding")
+		reverseProxy.ModifyResponse = modifyResponse(c, find.ResponseHook)
+	}
+	reverseProxy.ServeHTTP(c.Response(), c.Request())
 	return nil
 }
@@ -0,0 +1,119 @@
+package manager
+
+import (
+	"echo-server/auth"
+	"echo-server/cluster"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// namespacesPathPrefix 네임스페이스 단위 Kiali API 경로(/api/namespaces/{namespace}/...)
+const namespacesPathPrefix = "/api/namespaces/"
+
+// namespacesQuery 여러 네임스페이스를 지정하는 Kiali 쿼리 파라미터(예: /api/namespaces/graph?namespaces=bookinfo,default)
+const namespacesQuery = "namespaces"
+
+// clusterInfoPaths 네임스페이스 단위 데이터를 포함하지 않아 네임스페이스 지정 없이 전달하는 Kiali API 경로
+var clusterInfoPaths = map[string]bool{
+	"/api":           true,
+	"/api/auth/info": true,
+	"/api/config":    true,
+	"/api/status":    true,
+}
+
+// NamespaceAuthorizer 사용자의 네임스페이스 접근 권한 확인기(nil 이면 확인하지 않음, 인증을 사용할 때 main 에서 설정)
+var NamespaceAuthorizer *auth.NamespaceAuthorizer
+
+// authorizeNamespaces 요청 경로와 namespaces 쿼리의 네임스페이스에 대한 사용자의 접근 권한을 확인
+// 경로의 네임스페이스에 접근할 수 없으면 403, namespaces 쿼리는 접근할 수 있는 네임스페이스만 남기고 하나도 없으면 403 을 반환한다.
+// 네임스페이스를 지정하지 않은 요청은 응답을 필터링하는 경로(filtered)와 clusterInfoPaths 만 허용하고,
+// 그 외 경로(/api/istio/validations, /api/clusters/apps 등)는 모든 네임스페이스가 노출되므로 운영 관리자가 아니면 403 을 반환한다.
+// 응답 필터링(proxy.FilterNamespaces 등)에 사용하도록 권한 확인기를 컨텍스트에 설정한다.
+func authorizeNamespaces(c echo.Context, target cluster.Cluster, filtered bool) *auth.Error {
+	claims, ok := auth.ClaimsFrom(c)
+	if NamespaceAuthorizer == nil || !ok {
+		return nil
+	}
+	if target.Clientset == nil {
+		return &auth.Error{Status: http.StatusServiceUnavailable, Code: auth.ErrCodeAuthzUnavailable,
+			Message: "cluster has no kubernetes client to review namespace access, cluster=" + target.Name}
+	}
+	access := NamespaceAuthorizer.For(target.Clientset, target.Name, claims.Username())
+	auth.SetNamespaceAccess(c, access)
+
+	request := c.Request()
+	kialiPath := kialiAPIPath(request.URL.EscapedPath())
+	pathNamespace := requestNamespace(kialiPath)
+	query := request.URL.Query()
+	queryNamespaces := splitNamespaces(query.Get(namespacesQuery))
+	if pathNamespace == "" && len(queryNamespaces) == 0 {
+		if filtered || clusterInfoPaths[kialiPath] || claims.HasRole(auth.RoleOSManager) {
+			return nil
+		}
+		return &auth.Error{Status: http.StatusForbidden, Code: auth.ErrCodeNamespaceForbidden,
+			Message: "namespace or namespaces query is required"}
+	}
+
+	namespaces := queryNamespaces
+	if pathNamespace != "" {
+		namespaces = append(namespaces, pathNamespace)
+	}
+	allowed, err := access.Allowed(request.Context(), namespaces...)
+	if err != nil {
+		return &auth.Error{Status: http.StatusServiceUnavailable, Code: auth.ErrCodeAuthzUnavailable, Message: err.Error()}
+	}
+	if pathNamespace != "" && !allowed[pathNamespace] {
+		return namespaceForbidden(pathNamespace)
+	}
+	if len(queryNamespaces) > 0 {
+		var permitted []string
+		for _, namespace := range queryNamespaces {
+			if allowed[namespace] {
+				permitted = append(permitted, namespace)
+			}
+		}
+		if len(permitted) == 0 {
+			return namespaceForbidden(strings.Join(queryNamespaces, ","))
+		}
+		query.Set(namespacesQuery, strings.Join(permitted, ","))
+		request.URL.RawQuery = query.Encode()
+	}
+	return nil
+}
+
+func namespaceForbidden(namespace string) *auth.Error {
+	return &auth.Error{Status: http.StatusForbidden, Code: auth.ErrCodeNamespaceForbidden, Message: "namespace access denied", Namespace: namespace}
+}
+
+// requestNamespace Kiali API 경로의 네임스페이스, 네임스페이스 단위 경로가 아니면 빈 문자열
+/* /api/namespaces/bookinfo/workloads => bookinfo
+   /api/namespaces/graph              => ""
+*/
+func requestNamespace(kialiPath string) string {
+	rest := strings.TrimPrefix(kialiPath, namespacesPathPrefix)
+	if rest == kialiPath {
+		return ""
+	}
+	escaped, remaining, _ := strings.Cut(rest, "/")
+	if escaped == "graph" && remaining == "" {
+		return ""
+	}
+	namespace, err := url.PathUnescape(escaped)
+	if err != nil {
+		return escaped
+	}
+	return namespace
+}
+
+// splitNamespaces 쉼표로 구분된 네임스페이스 목록(빈 항목 제외)
+func splitNamespaces(value string) []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(value, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
@@ -0,0 +1,77 @@
+package manager
+
+import (
+	"echo-server/auth"
+	"echo-server/cluster"
+	"echo-server/proxy/proxytest"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProxyKialiServerNamespaces(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/kiali/api/namespaces", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"name":"bookinfo"},{"name":"kube-system"}]`))
+	})
+	mux.HandleFunc("/kiali/api/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.URL.RequestURI())
+	})
+	kiali := httptest.NewServer(mux)
+	defer kiali.Close()
+	stub := proxytest.NewAccessReviewServer(t, "bookinfo", "default")
+	useCluster(t, cluster.Cluster{Name: "test", KialiAddress: kiali.URL + "/kiali", Clientset: stub.Clientset})
+	useCluster(t, cluster.Cluster{Name: "static", KialiAddress: kiali.URL + "/kiali"})
+
+	NamespaceAuthorizer = &auth.NamespaceAuthorizer{}
+	defer func() { NamespaceAuthorizer = nil }()
+	e := echo.New()
+	e.Group(KialiApiGroupPrefix, auth.Middleware(&auth.Verifier{HMACKey: []byte("hello-jwt")}, "")).Any("/*", ProxyKialiServer)
+	token := signToken(t, auth.Claims{UserID: "user"})
+
+	tests := []struct {
+		name    string
+		path    string
+		cluster string
+		status  int
+		body    string
+	}{
+		{"list filtered", "/namespaces", "test", http.StatusOK, `[{"name":"bookinfo","cluster":"","labels":null,"annotations":null}]`},
+		{"allowed namespace", "/namespaces/bookinfo/workloads", "test", http.StatusOK, "/kiali/api/namespaces/bookinfo/workloads"},
+		{"forbidden namespace", "/namespaces/kube-system/workloads", "test", http.StatusForbidden, ""},
+		{"query narrowed", "/istio/validations?namespaces=bookinfo,kube-system,default&duration=60s", "test", http.StatusOK, "/kiali/api/istio/validations?duration=60s&namespaces=bookinfo%2Cdefault"},
+		{"query forbidden", "/istio/validations?namespaces=kube-system", "test", http.StatusForbidden, ""},
+		{"cross-namespace without query", "/istio/validations", "test", http.StatusForbidden, ""},
+		{"cluster-wide list", "/clusters/test/clusters/apps", "test", http.StatusForbidden, ""},
+		{"cluster info", "/config", "test", http.StatusOK, "/kiali/api/config"},
+		{"cluster without client", "/namespaces", "static", http.StatusServiceUnavailable, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, KialiApiGroupPrefix+test.path, nil)
+			request.Header.Set("Authorization", "Bearer "+token)
+			request.Header.Set(ClusterHeader, test.cluster)
+			recorder := httptest.NewRecorder()
+			e.ServeHTTP(recorder, request)
+			if recorder.Code != test.status {
+				t.Fatalf("expected %d, got %d, body=%s", test.status, recorder.Code, recorder.Body)
+			}
+			if test.body != "" && recorder.Body.String() != test.body {
+				t.Errorf("expected body %s, got %s", test.body, recorder.Body)
+			}
+		})
+	}
+
+	// 운영 관리자는 네임스페이스를 지정하지 않고 조회 가능
+	request := httptest.NewRequest(http.MethodGet, KialiApiGroupPrefix+"/istio/validations", nil)
+	request.Header.Set("Authorization", "Bearer "+signToken(t, auth.Claims{UserID: "admin", Infos: auth.Infos{IsOSManager: true}}))
+	request.Header.Set(ClusterHeader, "test")
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "/kiali/api/istio/validations" {
+		t.Errorf("expected manager request to be forwarded, got %d %s", recorder.Code, recorder.Body)
+	}
+}
@@ -0,0 +1,121 @@
+package manager
+
+import (
+	"bytes"
+	"echo-server/logging"
+	"echo-server/proxy"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// kialiTransport Kiali 로 요청을 전달할 때 사용하는 Transport(테스트에서 교체 가능)
+var kialiTransport http.RoundTripper = http.DefaultTransport
+
+// SetKialiTransport Kiali 호출에 사용할 Transport 를 교체(서버 시작 시 요청을 받기 전에 호출)
+func SetKialiTransport(transport http.RoundTripper) {
+	kialiTransport = transport
+}
+
+// authCookieName 콘솔 JWT 를 담는 쿠키 이름(Kiali 로 전달하지 않음)
+var authCookieName string
+
+// SetAuthCookieName 콘솔 JWT 쿠키 이름을 설정(서버 시작 시 요청을 받기 전에 호출)
+func SetAuthCookieName(name string) {
+	authCookieName = name
+}
+
+// newKialiReverseProxy Kiali 서버로 요청을 그대로 전달하는 스트리밍 리버스 프록시를 생성
+// 모든 메소드, 쿼리 스트링, 요청 헤더(hop-by-hop 제외)를 전달하고 X-Forwarded-* 헤더를 추가하며,
+// 업스트림의 상태 코드, 헤더, 본문은 버퍼링 없이 그대로 사용자에게 전달한다. 세션 쿠키는 sessions 에서 붙인다.
+/* GET /api/console/servicemesh/namespaces?health=true => GET {kialiServerAddress}/api/namespaces?health=true
+ */
+func newKialiReverseProxy(kialiServerAddress string, sessions *SessionManager, forwardedPrefix string) (*httputil.ReverseProxy, error) {
+	target, err := url.Parse(kialiServerAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kiali server address, err=%s", err)
+	}
+	return &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+			r.Out.URL.Path = singleJoiningSlash(target.Path, kialiAPIPath(r.In.URL.Path))
+			r.Out.URL.RawPath = ""
+			if r.In.URL.RawPath != "" {
+				r.Out.URL.RawPath = singleJoiningSlash(target.EscapedPath(), kialiAPIPath(r.In.URL.RawPath))
+			}
+			r.Out.URL.RawQuery = r.In.URL.RawQuery
+			r.SetXForwarded()
+			// 요청에 지정된 prefix 를 유지하여 Kiali 가 외부 경로를 알 수 있도록 함
+			r.Out.Header.Set("X-Forwarded-Prefix", forwardedPrefix)
+			// 클러스터 선택 헤더와 콘솔 JWT 는 Kiali 로 전달하지 않음(Kiali 는 세션 쿠키로 인증)
+			r.Out.Header.Del(ClusterHeader)
+			r.Out.Header.Del("Authorization")
+		},
+		Transport: &sessionTransport{sessions: sessions, kialiServerAddress: kialiServerAddress, next: kialiTransport},
+		// 음수이면 응답을 받는 즉시 flush 하여 대용량/스트리밍 응답을 메모리에 쌓지 않음
+		FlushInterval: -1,
+		ErrorHandler:  proxyErrorHandler,
+	}, nil
+}
+
+// kialiAPIPath 사용자 요청 경로의 API 그룹 prefix 를 Kiali API 경로(/api)로 변환
+func kialiAPIPath(requestPath string) string {
+	return "/api" + strings.TrimPrefix(requestPath, KialiApiGroupPrefix)
+}
+
+// singleJoiningSlash 두 경로 사이에 슬래시가 하나만 오도록 연결
+func singleJoiningSlash(a, b string) string {
+	aSlash := strings.HasSuffix(a, "/")
+	bSlash := strings.HasPrefix(b, "/")
+	switch {
+	case aSlash && bSlash:
+		return a + b[1:]
+	case !aSlash && !bSlash:
+		return a + "/" + b
+	}
+	return a + b
+}
+
+// modifyResponse 성공(2xx) 응답 본문을 ResponseHook 으로 변환하는 ReverseProxy.ModifyResponse 함수를 생성
+func modifyResponse(c echo.Context, hook proxy.ResponseHook) func(*http.Response) error {
+	return func(response *http.Response) error {
+		if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+			return nil
+		}
+		body, err := io.ReadAll(response.Body)
+		_ = response.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read kiali response, err=%s", err)
+		}
+		transformed, err := hook(c, body)
+		if err != nil {
+			return err
+		}
+		response.Body = io.NopCloser(bytes.NewReader(transformed))
+		response.ContentLength = int64(len(transformed))
+		response.Header.Set("Content-Length", strconv.Itoa(len(transformed)))
+		response.Header.Del("Content-Encoding")
+		return nil
+	}
+}
+
+// proxyErrorHandler 프록시 실패 시 에러 메시지를 JSON 으로 응답
+// 훅에서 반환한 echo.HTTPError 는 해당 상태 코드로, 그 외(Kiali 연결 실패 등)는 502 로 응답한다.
+func proxyErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	logging.Ctx(r.Context()).Warn().Str("method", r.Method).Str("path", r.URL.Path).Err(err).Msg("failed to proxy kiali request")
+	status, message := http.StatusBadGateway, err.Error()
+	var httpError *echo.HTTPError
+	if errors.As(err, &httpError) {
+		status, message = httpError.Code, fmt.Sprint(httpError.Message)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(Error{Message: message})
+}
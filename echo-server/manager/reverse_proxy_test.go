@@ -0,0 +1,307 @@
+package manager
+
+import (
+	"bufio"
+	"echo-server/auth"
+	"echo-server/cluster"
+	"echo-server/proxy"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubKiali 요청 내용을 JSON 으로 되돌려주는 Kiali 스텁 서버
+func stubKiali(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/kiali/api/authenticate", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "kiali-token", Value: "session"})
+	})
+	mux.HandleFunc("/kiali/api/", handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	useCluster(t, cluster.Cluster{Name: "test", KialiAddress: server.URL + "/kiali"})
+}
+
+// useCluster 테스트 동안 클러스터를 등록하여 기본 클러스터로 사용
+func useCluster(t *testing.T, target cluster.Cluster) {
+	t.Helper()
+	if err := Clusters.Add(target); err != nil {
+		t.Fatal(err)
+	}
+	if err := Clusters.SetDefault(target.Name); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Clusters.Remove(target.Name) })
+}
+
+// echoRequest 스텁이 받은 요청
+type echoRequest struct {
+	Method string      `json:"method"`
+	URI    string      `json:"uri"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+func echoHandler(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Kiali", "stub")
+	_ = json.NewEncoder(w).Encode(echoRequest{r.Method, r.RequestURI, r.Header, string(body)})
+}
+
+// signToken 테스트 키(hello-jwt)로 한 시간 뒤 만료되는 HS256 콘솔 토큰을 서명
+func signToken(t *testing.T, claims auth.Claims) string {
+	t.Helper()
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Hour))
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("hello-jwt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func newTestEcho() *echo.Echo {
+	e := echo.New()
+	e.Group(KialiApiGroupPrefix).Any("*", ProxyKialiServer)
+	return e
+}
+
+func newTestServer() *httptest.Server {
+	return httptest.NewServer(newTestEcho())
+}
+
+func TestProxyKialiServerMethods(t *testing.T) {
+	stubKiali(t, echoHandler)
+	server := newTestServer()
+	defer server.Close()
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		t.Run(method, func(t *testing.T) {
+			request, _ := http.NewRequest(method, server.URL+KialiApiGroupPrefix+"/namespaces/a%2Fb/istio?validate=true&help=true", strings.NewReader(`{"a":1}`))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("X-Custom", "value")
+			request.Header.Set("Connection", "X-Hop")
+			request.Header.Set("X-Hop", "dropped")
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+			if response.StatusCode != http.StatusOK || response.Header.Get("X-Kiali") != "stub" {
+				t.Fatalf("unexpected response %d %v", response.StatusCode, response.Header)
+			}
+
+			var received echoRequest
+			if err = json.NewDecoder(response.Body).Decode(&received); err != nil {
+				t.Fatal(err)
+			}
+			if received.Method != method {
+				t.Errorf("expected method %s, got %s", method, received.Method)
+			}
+			if received.URI != "/kiali/api/namespaces/a%2Fb/istio?validate=true&help=true" {
+				t.Errorf("unexpected uri %s", received.URI)
+			}
+			if received.Body != `{"a":1}` {
+				t.Errorf("unexpected body %q", received.Body)
+			}
+			if received.Header.Get("X-Custom") != "value" || received.Header.Get("X-Hop") != "" {
+				t.Errorf("unexpected headers %v", received.Header)
+			}
+			if received.Header.Get("X-Forwarded-For") == "" || received.Header.Get("X-Forwarded-Host") == "" ||
+				received.Header.Get("X-Forwarded-Proto") != "http" || received.Header.Get("X-Forwarded-Prefix") != KialiApiGroupPrefix {
+				t.Errorf("missing forwarded headers %v", received.Header)
+			}
+			if !strings.Contains(received.Header.Get("Cookie"), "kiali-token=session") {
+				t.Errorf("session cookie not forwarded %v", received.Header)
+			}
+		})
+	}
+}
+
+func TestProxyKialiServerCredentials(t *testing.T) {
+	stubKiali(t, echoHandler)
+	SetAuthCookieName("console-token")
+	defer SetAuthCookieName("")
+	server := newTestServer()
+	defer server.Close()
+
+	// 콘솔 JWT 는 헤더와 쿠키 모두 Kiali 로 전달하지 않음
+	request, _ := http.NewRequest(http.MethodGet, server.URL+KialiApiGroupPrefix+"/namespaces/default/health", nil)
+	request.Header.Set("Authorization", "Bearer console-jwt")
+	request.AddCookie(&http.Cookie{Name: "console-token", Value: "console-jwt"})
+	request.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	var received echoRequest
+	if err = json.NewDecoder(response.Body).Decode(&received); err != nil {
+		t.Fatal(err)
+	}
+	if received.Header.Get("Authorization") != "" {
+		t.Errorf("authorization header forwarded %v", received.Header)
+	}
+	cookie := received.Header.Get("Cookie")
+	if strings.Contains(cookie, "console-jwt") || !strings.Contains(cookie, "theme=dark") || !strings.Contains(cookie, "kiali-token=session") {
+		t.Errorf("unexpected cookies %q", cookie)
+	}
+}
+
+func TestProxyKialiServerErrorPassthrough(t *testing.T) {
+	stubKiali(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"namespace not found"}`))
+	})
+	server := newTestServer()
+	defer server.Close()
+
+	response, err := http.Get(server.URL + KialiApiGroupPrefix + "/namespaces/unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+	if response.StatusCode != http.StatusNotFound || string(body) != `{"error":"namespace not found"}` {
+		t.Errorf("unexpected response %d %s", response.StatusCode, body)
+	}
+}
+
+func TestProxyKialiServerUnavailable(t *testing.T) {
+	useCluster(t, cluster.Cluster{Name: "unavailable", KialiAddress: "http://127.0.0.1:1/kiali"})
+	server := newTestServer()
+	defer server.Close()
+
+	response, err := http.Get(server.URL + KialiApiGroupPrefix + "/namespaces")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected status %d, got %d", http.StatusBadGateway, response.StatusCode)
+	}
+}
+
+func TestProxyKialiServerStreaming(t *testing.T) {
+	release := make(chan struct{})
+	stubKiali(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("first\n"))
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = w.Write([]byte("second\n"))
+	})
+	server := newTestServer()
+	defer server.Close()
+	defer close(release)
+
+	response, err := http.Get(server.URL + KialiApiGroupPrefix + "/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	// 업스트림 응답이 끝나기 전에 첫 번째 청크를 받을 수 있어야 함
+	line, err := bufio.NewReader(response.Body).ReadString('\n')
+	if err != nil || line != "first\n" {
+		t.Errorf("expected first chunk before upstream completes, got %q (%v)", line, err)
+	}
+}
+
+// withProxies 테스트 동안 proxy 목록을 교체
+func withProxies(t *testing.T, proxies ...proxy.Proxy) {
+	t.Helper()
+	if err := SetProxies(proxies); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = SetProxies(*proxy.Proxies) })
+}
+
+func TestProxyKialiServerResponseHook(t *testing.T) {
+	stubKiali(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"name":"bookinfo"},{"name":"kube-system"}]`))
+	})
+	type namespace struct {
+		Name string `json:"name"`
+	}
+	withProxies(t, proxy.Proxy{
+		Name:    "Namespaces",
+		Method:  http.MethodGet,
+		Pattern: "/api/namespaces/:cluster",
+		RequestHook: func(c echo.Context, request *http.Request) error {
+			if c.Param("cluster") == "forbidden" {
+				return echo.NewHTTPError(http.StatusForbidden, "forbidden cluster")
+			}
+			return nil
+		},
+		ResponseHook: proxy.Transform(proxy.FilterSlice(func(list *[]namespace) *[]namespace { return list },
+			func(c echo.Context, item namespace) bool { return item.Name != "kube-system" })),
+	})
+	server := newTestServer()
+	defer server.Close()
+
+	request, _ := http.NewRequest(http.MethodGet, server.URL+KialiApiGroupPrefix+"/namespaces/east", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+	if response.StatusCode != http.StatusOK || string(body) != `[{"name":"bookinfo"}]` {
+		t.Errorf("unexpected response %d %s", response.StatusCode, body)
+	}
+
+	response, err = http.Get(server.URL + KialiApiGroupPrefix + "/namespaces/forbidden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusForbidden {
+		t.Errorf("expected status %d, got %d", http.StatusForbidden, response.StatusCode)
+	}
+}
+
+func TestProxyKialiServerRoles(t *testing.T) {
+	stubKiali(t, echoHandler)
+	withProxies(t, proxy.Proxy{
+		Name:    "IstioConfigs",
+		Method:  http.MethodGet,
+		Pattern: "/api/istio/config",
+		Roles:   []auth.Role{auth.RoleOSManager},
+	})
+	verifier := &auth.Verifier{HMACKey: []byte("hello-jwt")}
+	e := echo.New()
+	e.Group(KialiApiGroupPrefix, auth.Middleware(verifier, "")).Any("/*", ProxyKialiServer)
+
+	tests := []struct {
+		name   string
+		path   string
+		claims auth.Claims
+		status int
+	}{
+		{"route without roles", "/api/console/servicemesh/namespaces", auth.Claims{UserID: "user"}, http.StatusOK},
+		{"insufficient role", "/api/console/servicemesh/istio/config", auth.Claims{UserID: "user"}, http.StatusForbidden},
+		{"os manager", "/api/console/servicemesh/istio/config", auth.Claims{UserID: "admin", Infos: auth.Infos{IsOSManager: true}}, http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, test.path, nil)
+			request.Header.Set("Authorization", "Bearer "+signToken(t, test.claims))
+			recorder := httptest.NewRecorder()
+			e.ServeHTTP(recorder, request)
+			if recorder.Code != test.status {
+				t.Errorf("expected %d, got %d, body=%s", test.status, recorder.Code, recorder.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"echo-server/telemetry"
+	"fmt"
+	"io"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTokenExpirationSeconds = 3600             // ServiceAccount 토큰 유효 기간 기본값
+	sessionExpirySkew             = 30 * time.Second // 만료 직전 세션을 사용하지 않도록 당겨서 만료 처리하는 시간
+	maxReplayBodySize             = 1 << 20          // 401 재인증 후 재요청을 위해 메모리에 보관하는 요청 본문 최대 크기
+)
+
+// TokenSource Kiali token 전략 인증에 사용할 토큰과 만료 시간을 발급하는 인터페이스
+type TokenSource interface {
+	Token(ctx context.Context) (string, time.Time, error)
+}
+
+// TokenSourceFunc 함수를 TokenSource 로 사용하기 위한 타입
+type TokenSourceFunc func(ctx context.Context) (string, time.Time, error)
+
+// Token 함수를 호출하여 토큰을 발급
+func (f TokenSourceFunc) Token(ctx context.Context) (string, time.Time, error) {
+	return f(ctx)
+}
+
+// ServiceAccountTokenSource Kubernetes ServiceAccount TokenRequest 로 토큰을 발급하는 TokenSource
+type ServiceAccountTokenSource struct {
+	Clientset         kubernetes.Interface
+	Namespace         string // ServiceAccount 네임스페이스(예: istio-system)
+	Name              string // ServiceAccount 이름(예: kiali-service-account)
+	ExpirationSeconds int64  // 토큰 유효 기간(0 이면 1시간)
+}
+
+// Token ServiceAccount 의 토큰을 생성
+func (s ServiceAccountTokenSource) Token(ctx context.Context) (string, time.Time, error) {
+	expirationSeconds := s.ExpirationSeconds
+	if expirationSeconds <= 0 {
+		expirationSeconds = defaultTokenExpirationSeconds
+	}
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expirationSeconds},
+	}
+	token, err := s.Clientset.CoreV1().ServiceAccounts(s.Namespace).CreateToken(ctx, s.Name, tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to create service account token, namespace=%s, name=%s, err=%s", s.Namespace, s.Name, err)
+	}
+	return token.Status.Token, token.Status.ExpirationTimestamp.Time, nil
+}
+
+// session Kiali 인증으로 받은 세션 쿠키와 만료 시간
+type session struct {
+	cookies   []*http.Cookie
+	expiresAt time.Time
+}
+
+// SessionManager Kiali 서버별 세션을 한 번만 인증하여 만료 전까지 캐시하는 세션 관리자(클러스터마다 하나, sessionsFor 참고)
+type SessionManager struct {
+	TokenSource TokenSource       // nil 이면 토큰 없이 인증(anonymous 전략)
+	Transport   http.RoundTripper // Kiali 호출에 사용하는 Transport(nil 이면 http.DefaultTransport)
+	Now         func() time.Time  // 현재 시각(테스트에서 교체 가능)
+
+	mutex    sync.Mutex
+	sessions map[string]*session
+	locks    map[string]*sync.Mutex
+}
+
+// NewSessionManager 토큰 발급 방식을 지정하여 세션 관리자를 생성
+func NewSessionManager(tokenSource TokenSource) *SessionManager {
+	return &SessionManager{
+		TokenSource: tokenSource,
+		sessions:    make(map[string]*session),
+		locks:       make(map[string]*sync.Mutex),
+	}
+}
+
+// Cookies Kiali 서버의 세션 쿠키를 반환, 캐시된 세션이 없거나 만료되었으면 인증하여 새 세션을 만든다
+func (m *SessionManager) Cookies(ctx context.Context, kialiServerAddress string) ([]*http.Cookie, error) {
+	lock := m.lock(kialiServerAddress)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if cached := m.cached(kialiServerAddress); cached != nil {
+		return cached.cookies, nil
+	}
+	ctx, end := telemetry.AuthenticateSpan(ctx, kialiServerAddress)
+	authenticated, err := m.authenticate(ctx, kialiServerAddress)
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+	m.mutex.Lock()
+	m.sessions[kialiServerAddress] = authenticated
+	m.mutex.Unlock()
+	return authenticated.cookies, nil
+}
+
+// Invalidate Kiali 서버의 캐시된 세션을 삭제(다음 요청에서 재인증)
+func (m *SessionManager) Invalidate(kialiServerAddress string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.sessions, kialiServerAddress)
+}
+
+// lock Kiali 서버별 인증 잠금을 반환(같은 서버에 대한 동시 인증 방지)
+func (m *SessionManager) lock(kialiServerAddress string) *sync.Mutex {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.locks == nil {
+		m.locks = make(map[string]*sync.Mutex)
+		m.sessions = make(map[string]*session)
+	}
+	lock, ok := m.locks[kialiServerAddress]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.locks[kialiServerAddress] = lock
+	}
+	return lock
+}
+
+// cached 만료되지 않은 캐시 세션을 반환
+func (m *SessionManager) cached(kialiServerAddress string) *session {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	cached, ok := m.sessions[kialiServerAddress]
+	if !ok || !m.now().Before(cached.expiresAt) {
+		return nil
+	}
+	return cached
+}
+
+// authenticate Kiali 인증 API(/api/authenticate)를 호출하여 세션을 생성
+/* POST {kialiServerAddress}/api/authenticate, Content-Type: application/x-www-form-urlencoded, token=<ServiceAccount token>
+ */
+func (m *SessionManager) authenticate(ctx context.Context, kialiServerAddress string) (*session, error) {
+	form := url.Values{}
+	var tokenExpiresAt time.Time
+	if m.TokenSource != nil {
+		token, expiresAt, err := m.TokenSource.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		form.Set("token", token)
+		tokenExpiresAt = expiresAt
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, kialiServerAddress+"/api/authenticate", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authenticate request, err=%s", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	response, err := m.transport().RoundTrip(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate kiali, err=%s", err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to authenticate kiali, status=%d", response.StatusCode)
+	}
+
+	// 세션 만료 시간은 쿠키 만료 시간과 토큰 만료 시간 중 빠른 시간
+	now := m.now()
+	expiresAt := now.Add(defaultTokenExpirationSeconds * time.Second)
+	if !tokenExpiresAt.IsZero() {
+		expiresAt = tokenExpiresAt
+	}
+	cookies := response.Cookies()
+	for _, cookie := range cookies {
+		cookieExpiresAt := cookie.Expires
+		if cookie.MaxAge > 0 {
+			cookieExpiresAt = now.Add(time.Duration(cookie.MaxAge) * time.Second)
+		}
+		if !cookieExpiresAt.IsZero() && cookieExpiresAt.Before(expiresAt) {
+			expiresAt = cookieExpiresAt
+		}
+	}
+	return &session{cookies: cookies, expiresAt: expiresAt.Add(-sessionExpirySkew)}, nil
+}
+
+func (m *SessionManager) transport() http.RoundTripper {
+	if m.Transport != nil {
+		return m.Transport
+	}
+	return http.DefaultTransport
+}
+
+func (m *SessionManager) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now()
+}
+
+// sessionTransport 업스트림 요청에 Kiali 세션 쿠키를 붙이고 401 응답이면 재인증 후 한 번 재요청하는 RoundTripper
+type sessionTransport struct {
+	sessions           *SessionManager
+	kialiServerAddress string
+	next               http.RoundTripper
+}
+
+// RoundTrip 세션 쿠키를 붙여 요청을 전달
+// 요청 본문은 그대로 전달하며, 재요청이 가능하도록 maxReplayBodySize 이하인 본문만 메모리에 보관한다.
+func (t *sessionTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	getBody, err := replayableBody(request)
+	if err != nil {
+		return nil, err
+	}
+	cookies, err := t.sessions.Cookies(request.Context(), t.kialiServerAddress)
+	if err != nil {
+		return nil, err
+	}
+	response, err := t.next.RoundTrip(withSessionCookies(request, cookies))
+	if err != nil || response.StatusCode != http.StatusUnauthorized || getBody == nil {
+		return response, err
+	}
+
+	// 세션이 Kiali 에서 만료된 경우 재인증 후 재요청
+	t.sessions.Invalidate(t.kialiServerAddress)
+	if cookies, err = t.sessions.Cookies(request.Context(), t.kialiServerAddress); err != nil {
+		return response, nil
+	}
+	body, err := getBody()
+	if err != nil {
+		return response, nil
+	}
+	_, _ = io.Copy(io.Discard, response.Body)
+	_ = response.Body.Close()
+	retry := request.Clone(request.Context())
+	retry.Body = body
+	return t.next.RoundTrip(withSessionCookies(retry, cookies))
+}
+
+// replayableBody 요청 본문을 다시 읽을 수 있는 함수를 반환(본문이 너무 크면 nil 을 반환하고 스트리밍으로 전달)
+func replayableBody(request *http.Request) (func() (io.ReadCloser, error), error) {
+	if request.Body == nil || request.Body == http.NoBody {
+		return func() (io.ReadCloser, error) { return http.NoBody, nil }, nil
+	}
+	if request.GetBody != nil {
+		return request.GetBody, nil
+	}
+	buffered, err := io.ReadAll(io.LimitReader(request.Body, maxReplayBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body, err=%s", err)
+	}
+	if len(buffered) > maxReplayBodySize {
+		request.Body = readCloser{io.MultiReader(bytes.NewReader(buffered), request.Body), request.Body}
+		return nil, nil
+	}
+	_ = request.Body.Close()
+	request.Body = io.NopCloser(bytes.NewReader(buffered))
+	return func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(buffered)), nil }, nil
+}
+
+// readCloser 앞부분을 읽은 본문을 원래 본문의 Close 와 함께 사용하기 위한 타입
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// withSessionCookies 사용자 쿠키 중 세션 쿠키와 이름이 같은 쿠키를 세션 쿠키로 교체한 요청을 반환
+// 콘솔 JWT 쿠키(authCookieName)는 Kiali 로 전달하지 않는다.
+func withSessionCookies(request *http.Request, cookies []*http.Cookie) *http.Request {
+	names := make(map[string]bool, len(cookies)+1)
+	for _, cookie := range cookies {
+		names[cookie.Name] = true
+	}
+	if authCookieName != "" {
+		names[authCookieName] = true
+	}
+	out := request.Clone(request.Context())
+	out.Body = request.Body
+	out.Header.Del("Cookie")
+	for _, cookie := range request.Cookies() {
+		if !names[cookie.Name] {
+			out.AddCookie(cookie)
+		}
+	}
+	for _, cookie := range cookies {
+		out.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	return out
+}
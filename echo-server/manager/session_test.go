@@ -0,0 +1,136 @@
+package manager
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// sessionKiali 토큰 인증으로 발급한 세션 쿠키가 있어야 응답하는 Kiali 스텁 서버
+type sessionKiali struct {
+	authentications int32
+	valid           atomic.Value // 현재 유효한 세션 값
+}
+
+func (k *sessionKiali) start(t *testing.T) string {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/kiali/api/authenticate", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("token") != "sa-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		n := atomic.AddInt32(&k.authentications, 1)
+		value := "session-" + string(rune('0'+n))
+		k.valid.Store(value)
+		http.SetCookie(w, &http.Cookie{Name: "kiali-token", Value: value, Expires: time.Now().Add(time.Hour)})
+	})
+	mux.HandleFunc("/kiali/api/", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("kiali-token")
+		if err != nil || cookie.Value != k.valid.Load() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server.URL + "/kiali"
+}
+
+func tokenSource() TokenSource {
+	return TokenSourceFunc(func(ctx context.Context) (string, time.Time, error) {
+		return "sa-token", time.Now().Add(time.Hour), nil
+	})
+}
+
+func TestSessionManagerCachesSession(t *testing.T) {
+	kiali := &sessionKiali{}
+	address := kiali.start(t)
+	sessions := NewSessionManager(tokenSource())
+	client := &http.Client{Transport: &sessionTransport{sessions: sessions, kialiServerAddress: address, next: http.DefaultTransport}}
+
+	for i := 0; i < 3; i++ {
+		response, err := client.Post(address+"/api/namespaces/default/istio/virtualservices", "application/json", strings.NewReader(`{"kind":"VirtualService"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(response.Body)
+		_ = response.Body.Close()
+		if response.StatusCode != http.StatusOK || string(body) != `{"kind":"VirtualService"}` {
+			t.Fatalf("unexpected response %d %q", response.StatusCode, body)
+		}
+	}
+	if atomic.LoadInt32(&kiali.authentications) != 1 {
+		t.Errorf("expected 1 authentication, got %d", atomic.LoadInt32(&kiali.authentications))
+	}
+}
+
+func TestSessionManagerReauthenticatesOnUnauthorized(t *testing.T) {
+	kiali := &sessionKiali{}
+	address := kiali.start(t)
+	sessions := NewSessionManager(tokenSource())
+	client := &http.Client{Transport: &sessionTransport{sessions: sessions, kialiServerAddress: address, next: http.DefaultTransport}}
+
+	if _, err := sessions.Cookies(context.Background(), address); err != nil {
+		t.Fatal(err)
+	}
+	// Kiali 에서 세션이 만료된 상황
+	kiali.valid.Store("expired")
+
+	response, err := client.Post(address+"/api/namespaces", "application/json", strings.NewReader(`{"a":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+	if response.StatusCode != http.StatusOK || string(body) != `{"a":1}` {
+		t.Errorf("unexpected response %d %q", response.StatusCode, body)
+	}
+	if atomic.LoadInt32(&kiali.authentications) != 2 {
+		t.Errorf("expected 2 authentications, got %d", atomic.LoadInt32(&kiali.authentications))
+	}
+}
+
+func TestSessionManagerExpiry(t *testing.T) {
+	kiali := &sessionKiali{}
+	address := kiali.start(t)
+	now := time.Now()
+	sessions := NewSessionManager(tokenSource())
+	sessions.Now = func() time.Time { return now }
+
+	if _, err := sessions.Cookies(context.Background(), address); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(30 * time.Minute)
+	if _, err := sessions.Cookies(context.Background(), address); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&kiali.authentications) != 1 {
+		t.Fatalf("expected cached session, got %d authentications", atomic.LoadInt32(&kiali.authentications))
+	}
+	now = now.Add(time.Hour)
+	if _, err := sessions.Cookies(context.Background(), address); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&kiali.authentications) != 2 {
+		t.Errorf("expected re-authentication after expiry, got %d", atomic.LoadInt32(&kiali.authentications))
+	}
+}
+
+func TestSessionManagerAuthenticationFailure(t *testing.T) {
+	kiali := &sessionKiali{}
+	address := kiali.start(t)
+	sessions := NewSessionManager(TokenSourceFunc(func(ctx context.Context) (string, time.Time, error) {
+		return "wrong-token", time.Time{}, nil
+	}))
+	if _, err := sessions.Cookies(context.Background(), address); err == nil {
+		t.Error("expected authentication error")
+	}
+}
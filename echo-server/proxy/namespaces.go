@@ -0,0 +1,121 @@
+package proxy
+
+import (
+	"echo-server/auth"
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// FilterNamespaces 응답값의 슬라이스에서 사용자가 접근할 수 있는 네임스페이스의 원소만 남기는 트랜스포머
+// 네임스페이스가 비어 있는 원소(클러스터 범위)는 남기며, 네임스페이스 권한을 확인하지 않는 요청이면 그대로 둔다.
+/* FilterNamespaces(func(list *[]models.Namespace) *[]models.Namespace { return list }, func(ns models.Namespace) string { return ns.Name })
+ */
+func FilterNamespaces[T any, E any](items func(value *T) *[]E, namespace func(item E) string) Transformer[T] {
+	return func(c echo.Context, value *T) error {
+		access, ok := auth.NamespaceAccessFrom(c)
+		if !ok {
+			return nil
+		}
+		namespaces := make([]string, 0, len(*items(value)))
+		for _, item := range *items(value) {
+			if name := namespace(item); name != "" {
+				namespaces = append(namespaces, name)
+			}
+		}
+		allowed, err := allowedNamespaces(c, access, namespaces)
+		if err != nil {
+			return err
+		}
+		return FilterSlice(items, func(c echo.Context, item E) bool {
+			name := namespace(item)
+			return name == "" || allowed[name]
+		})(c, value)
+	}
+}
+
+// FilterNamespaceMap 네임스페이스를 키로 하는 응답값(예: models.IstioConfigMap)에서 사용자가 접근할 수 없는 네임스페이스를 삭제하는 트랜스포머
+func FilterNamespaceMap[M ~map[string]V, V any]() Transformer[M] {
+	return func(c echo.Context, value *M) error {
+		access, ok := auth.NamespaceAccessFrom(c)
+		if !ok {
+			return nil
+		}
+		namespaces := make([]string, 0, len(*value))
+		for namespace := range *value {
+			namespaces = append(namespaces, namespace)
+		}
+		allowed, err := allowedNamespaces(c, access, namespaces)
+		if err != nil {
+			return err
+		}
+		for namespace := range *value {
+			if !allowed[namespace] {
+				delete(*value, namespace)
+			}
+		}
+		return nil
+	}
+}
+
+// Graph Kiali 그래프 API(/api/namespaces/graph) 응답, 노드와 엣지의 data 는 필요한 필드만 읽고 그대로 전달
+type Graph struct {
+	Timestamp int64         `json:"timestamp"`
+	Duration  int64         `json:"duration"`
+	GraphType string        `json:"graphType"`
+	Elements  GraphElements `json:"elements"`
+}
+
+// GraphElements 그래프의 노드와 엣지
+type GraphElements struct {
+	Nodes []GraphElement `json:"nodes"`
+	Edges []GraphElement `json:"edges"`
+}
+
+// GraphElement 그래프 노드 또는 엣지
+type GraphElement struct {
+	Data json.RawMessage `json:"data"`
+}
+
+// graphData 필터링에 사용하는 노드, 엣지 data 필드
+type graphData struct {
+	ID        string `json:"id"`
+	Namespace string `json:"namespace"`
+	Source    string `json:"source"`
+	Target    string `json:"target"`
+}
+
+func (e GraphElement) data() graphData {
+	var data graphData
+	_ = json.Unmarshal(e.Data, &data)
+	return data
+}
+
+// FilterGraph 그래프에서 사용자가 접근할 수 없는 네임스페이스의 노드와 그 노드에 연결된 엣지를 삭제하는 트랜스포머
+// 네임스페이스가 없는 노드(unknown 등)는 남긴다.
+func FilterGraph() Transformer[Graph] {
+	return func(c echo.Context, graph *Graph) error {
+		if err := FilterNamespaces(func(graph *Graph) *[]GraphElement { return &graph.Elements.Nodes },
+			func(node GraphElement) string { return node.data().Namespace })(c, graph); err != nil {
+			return err
+		}
+		nodes := make(map[string]bool, len(graph.Elements.Nodes))
+		for _, node := range graph.Elements.Nodes {
+			nodes[node.data().ID] = true
+		}
+		return FilterSlice(func(graph *Graph) *[]GraphElement { return &graph.Elements.Edges },
+			func(c echo.Context, edge GraphElement) bool {
+				data := edge.data()
+				return nodes[data.Source] && nodes[data.Target]
+			})(c, graph)
+	}
+}
+
+// allowedNamespaces 네임스페이스별 접근 가능 여부, 권한 확인에 실패하면 503 에러
+func allowedNamespaces(c echo.Context, access *auth.NamespaceAccess, namespaces []string) (map[string]bool, error) {
+	allowed, err := access.Allowed(c.Request().Context(), namespaces...)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	}
+	return allowed, nil
+}
@@ -0,0 +1,55 @@
+package proxy_test
+
+import (
+	"echo-server/proxy"
+	"echo-server/proxy/proxytest"
+	"github.com/kiali/kiali/models"
+	"net/http"
+	"testing"
+)
+
+func TestFilterNamespaces(t *testing.T) {
+	filter := proxy.FilterNamespaces(
+		func(list *[]models.Namespace) *[]models.Namespace { return list },
+		func(namespace models.Namespace) string { return namespace.Name },
+	)
+
+	// 네임스페이스 권한을 확인하지 않는 요청이면 그대로 전달
+	namespaces := proxytest.Run(t, proxytest.NewContext(http.MethodGet, "/api/namespaces", nil), "testdata/namespaces.json", filter)
+	if len(*namespaces) != 3 {
+		t.Errorf("unexpected namespaces %v", *namespaces)
+	}
+
+	c := proxytest.WithNamespaceAccess(t, proxytest.NewContext(http.MethodGet, "/api/namespaces", nil), "bookinfo", "istio-system")
+	namespaces = proxytest.Run(t, c, "testdata/namespaces.json", filter)
+	if len(*namespaces) != 2 || (*namespaces)[0].Name != "bookinfo" || (*namespaces)[1].Name != "istio-system" {
+		t.Errorf("unexpected namespaces %v", *namespaces)
+	}
+}
+
+func TestFilterNamespaceMap(t *testing.T) {
+	c := proxytest.WithNamespaceAccess(t, proxytest.NewContext(http.MethodGet, "/api/istio/config", nil), "bookinfo")
+	configs := proxytest.Run(t, c, "testdata/istio_configs.json", proxy.FilterNamespaceMap[models.IstioConfigMap]())
+	if _, ok := (*configs)["payments"]; ok || len(*configs) != 1 {
+		t.Errorf("unexpected istio configs %v", *configs)
+	}
+}
+
+func TestFilterGraph(t *testing.T) {
+	c := proxytest.WithNamespaceAccess(t, proxytest.NewContext(http.MethodGet, "/api/namespaces/graph?namespaces=bookinfo,payments", nil), "bookinfo")
+	got := proxytest.Apply(t, c, proxy.Transform[proxy.Graph](proxy.FilterGraph()), "testdata/graph.json")
+	proxytest.AssertJSONEqual(t, `{
+		"timestamp": 1663752857,
+		"duration": 600,
+		"graphType": "versionedApp",
+		"elements": {
+			"nodes": [
+				{"data": {"id": "n1", "nodeType": "app", "namespace": "bookinfo", "app": "productpage", "traffic": [{"protocol": "http", "rates": {"httpIn": "1.00"}}]}},
+				{"data": {"id": "n3", "nodeType": "unknown", "isInaccessible": true}}
+			],
+			"edges": [
+				{"data": {"id": "e1", "source": "n3", "target": "n1", "traffic": {"protocol": "http", "rates": {"http": "1.00"}}}}
+			]
+		}
+	}`, got)
+}
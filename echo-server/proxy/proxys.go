@@ -1,6 +1,7 @@
 package proxy
 
 import (
+	"echo-server/auth"
 	"echo-server/handler"
 	"github.com/kiali/kiali/models"
 	"github.com/labstack/echo/v4"
@@ -8,29 +9,57 @@ import (
 
 type HandlerFunc func(c echo.Context) error
 
+// Proxy Kiali API 경로 패턴과 메소드별 처리 방식
+// HandlerFunc 가 있으면 Kiali 를 호출하지 않고 핸들러에서 응답하며, 없으면 Kiali 로 프록시하면서
+// RequestHook 으로 요청을, ResponseHook 으로 성공 응답 본문을 변환한다.
+// Roles 가 있으면 해당 역할 중 하나를 가진 사용자만 호출할 수 있다.
 type Proxy struct {
-	Name        string
-	Method      string
-	Pattern     string
-	HandlerFunc HandlerFunc
-	Type        interface{}
+	Name         string
+	Method       string
+	Pattern      string
+	HandlerFunc  HandlerFunc
+	Type         interface{} // Kiali 응답 모델 타입
+	RequestHook  RequestHook
+	ResponseHook ResponseHook
+	Roles        []auth.Role
 }
 
 type proxies []Proxy
 
 var Proxies = &proxies{
 	{
-		"IstioConfigDetails",
-		"GET",
-		"/api/namespaces/:namespace/istio/:objectType/:object",
-		handler.IstioConfigDetails,
-		models.IstioConfigDetails{},
+		Name:    "IstioConfigDetails",
+		Method:  "GET",
+		Pattern: "/api/namespaces/:namespace/istio/:objectType/:object",
+		Type:    models.IstioConfigDetails{},
+		ResponseHook: Transform[models.IstioConfigDetails](
+			Redact[models.IstioConfigDetails]("help"),
+			Enrich("summary", handler.IstioConfigDetailsSummary),
+		),
 	},
 	{
-		"allIstioConfigs",
-		"GET",
-		"/api/istio/config",
-		handler.Healthz,
-		"faf",
+		Name:         "allIstioConfigs",
+		Method:       "GET",
+		Pattern:      "/api/istio/config",
+		Type:         models.IstioConfigMap{},
+		ResponseHook: Transform[models.IstioConfigMap](FilterNamespaceMap[models.IstioConfigMap]()),
+		Roles:        []auth.Role{auth.RoleOSManager},
+	},
+	{
+		Name:    "namespaces",
+		Method:  "GET",
+		Pattern: "/api/namespaces",
+		Type:    []models.Namespace{},
+		ResponseHook: Transform[[]models.Namespace](FilterNamespaces(
+			func(list *[]models.Namespace) *[]models.Namespace { return list },
+			func(namespace models.Namespace) string { return namespace.Name },
+		)),
+	},
+	{
+		Name:         "namespacesGraph",
+		Method:       "GET",
+		Pattern:      "/api/namespaces/graph",
+		Type:         Graph{},
+		ResponseHook: Transform[Graph](FilterGraph()),
 	},
 }
@@ -0,0 +1,133 @@
+// Package proxytest Kiali 응답 픽스처로 proxy 트랜스포머와 ResponseHook 을 테스트하기 위한 헬퍼
+package proxytest
+
+import (
+	"echo-server/auth"
+	"echo-server/proxy"
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"testing"
+)
+
+// NewContext 메소드, 요청 URL, 경로 파라미터를 지정하여 트랜스포머에 전달할 echo 컨텍스트를 생성
+func NewContext(method, target string, params map[string]string) echo.Context {
+	c := echo.New().NewContext(httptest.NewRequest(method, target, nil), httptest.NewRecorder())
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = params[name]
+	}
+	c.SetParamNames(names...)
+	c.SetParamValues(values...)
+	return c
+}
+
+// LoadFixture 픽스처 파일(예: testdata/istio_config_details.json)을 읽음
+func LoadFixture(t testing.TB, path string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read fixture, path=%s, err=%s", path, err)
+	}
+	return body
+}
+
+// Run 픽스처를 T 로 디코딩하여 트랜스포머를 순서대로 실행한 결과를 반환
+func Run[T any](t testing.TB, c echo.Context, fixturePath string, transformers ...proxy.Transformer[T]) *T {
+	t.Helper()
+	value, err := proxy.RunTransformers(c, LoadFixture(t, fixturePath), transformers...)
+	if err != nil {
+		t.Fatalf("failed to run transformers, err=%s", err)
+	}
+	return value
+}
+
+// Apply 픽스처를 ResponseHook 으로 변환한 본문을 JSON 으로 디코딩하여 반환
+func Apply(t testing.TB, c echo.Context, hook proxy.ResponseHook, fixturePath string) interface{} {
+	t.Helper()
+	body, err := hook(c, LoadFixture(t, fixturePath))
+	if err != nil {
+		t.Fatalf("failed to apply response hook, err=%s", err)
+	}
+	var decoded interface{}
+	if err = json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("invalid response hook body %q, err=%s", body, err)
+	}
+	return decoded
+}
+
+// AssertJSONEqual 값을 JSON 으로 인코딩했을 때 기대하는 JSON 과 같은지 비교(필드 순서와 공백은 무시)
+func AssertJSONEqual(t testing.TB, expected string, actual interface{}) {
+	t.Helper()
+	encoded, err := json.Marshal(actual)
+	if err != nil {
+		t.Fatalf("failed to encode actual value, err=%s", err)
+	}
+	var expectedValue, actualValue interface{}
+	if err = json.Unmarshal([]byte(expected), &expectedValue); err != nil {
+		t.Fatalf("invalid expected json %q, err=%s", expected, err)
+	}
+	_ = json.Unmarshal(encoded, &actualValue)
+	if !reflect.DeepEqual(expectedValue, actualValue) {
+		t.Errorf("expected %s, got %s", expected, encoded)
+	}
+}
+
+// AccessReviewServer 허용한 네임스페이스만 allowed 로 응답하는 SubjectAccessReview API 서버 스텁
+type AccessReviewServer struct {
+	Clientset kubernetes.Interface
+	Requests  atomic.Int32 // 받은 SubjectAccessReview 요청 수
+}
+
+// NewAccessReviewServer allowed 네임스페이스만 허용하는 API 서버 스텁과 clientset 을 생성
+func NewAccessReviewServer(t testing.TB, allowed ...string) *AccessReviewServer {
+	t.Helper()
+	permitted := make(map[string]bool, len(allowed))
+	for _, namespace := range allowed {
+		permitted[namespace] = true
+	}
+	stub := &AccessReviewServer{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/apis/authorization.k8s.io/v1/subjectaccessreviews" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		stub.Requests.Add(1)
+		review := authorizationv1.SubjectAccessReview{}
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Spec.ResourceAttributes == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		review.Status.Allowed = permitted[review.Spec.ResourceAttributes.Namespace]
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(review)
+	}))
+	t.Cleanup(server.Close)
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stub.Clientset = clientset
+	return stub
+}
+
+// WithNamespaceAccess allowed 네임스페이스만 접근할 수 있는 사용자의 권한 확인기를 컨텍스트에 설정
+func WithNamespaceAccess(t testing.TB, c echo.Context, allowed ...string) echo.Context {
+	t.Helper()
+	stub := NewAccessReviewServer(t, allowed...)
+	auth.SetNamespaceAccess(c, (&auth.NamespaceAuthorizer{}).For(stub.Clientset, "test", "user"))
+	return c
+}
@@ -0,0 +1,185 @@
+package proxy
+
+import (
+	urlpath "echo-server/util/url"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Router proxy 패턴을 미리 컴파일한 경로 세그먼트 단위의 radix 트리
+// 패턴 문법과 매칭 규칙은 urlpath 와 같고(:param, :param<int>, :param?, 마지막 세그먼트의 *), 같은 위치에서는 고정 세그먼트, 파라미터, * 순서로 우선한다.
+// 경로 중간의 와일드카드(*, **)는 지원하지 않는다.
+type Router struct {
+	root *routeNode
+}
+
+// routeNode 경로 세그먼트 하나에 해당하는 트리 노드
+type routeNode struct {
+	static    map[string]*routeNode // 고정 세그먼트 자식
+	param     *routeNode            // 파라미터 세그먼트 자식
+	paramName string                // param 자식의 파라미터 이름
+	paramRule urlpath.Segment       // param 자식의 제약 조건
+	trailing  map[string]*route     // 이 노드 이후 임의의 세그먼트를 허용하는(*) 메소드별 라우트
+	routes    map[string]*route     // 이 노드에서 끝나는 메소드별 라우트
+}
+
+// route 등록된 proxy 와 파라미터 이름 목록(세그먼트 순서)
+type route struct {
+	proxy      *Proxy
+	paramNames []string
+}
+
+// Params 매칭된 경로 파라미터(이름과 값의 순서가 같음)
+type Params struct {
+	Names    []string
+	Values   []string
+	Trailing string // * 패턴에 매칭된 나머지 경로(앞의 슬래시 제외)
+}
+
+// Get 파라미터 이름에 해당하는 값을 반환
+func (p Params) Get(name string) string {
+	for i, paramName := range p.Names {
+		if paramName == name {
+			return p.Values[i]
+		}
+	}
+	return ""
+}
+
+// NewRouter proxy 목록으로 라우터를 생성, 같은 메소드에 같은 형태의 패턴이 있거나 같은 위치의 파라미터 이름이 다르면 에러
+/* /api/namespaces/:namespace/istio 와 /api/namespaces/:ns/istio/:objectType => conflicting param name
+   /api/workloads/:id<int> 와 /api/workloads/:id/logs => conflicting param constraint
+   GET /api/istio/config 와 GET /api/istio/:objectType?/config => conflicting route
+*/
+func NewRouter(proxies []Proxy) (*Router, error) {
+	router := &Router{root: newRouteNode()}
+	for i := range proxies {
+		if err := router.add(&proxies[i]); err != nil {
+			return nil, err
+		}
+	}
+	return router, nil
+}
+
+func newRouteNode() *routeNode {
+	return &routeNode{static: make(map[string]*routeNode)}
+}
+
+// add proxy 패턴을 트리에 등록, 선택 세그먼트가 있으면 세그먼트가 있는 경우와 없는 경우를 모두 등록
+func (r *Router) add(p *Proxy) error {
+	path, err := urlpath.Parse(p.Pattern)
+	if err != nil {
+		return fmt.Errorf("failed to add route, name=%s, err=%s", p.Name, err)
+	}
+	for _, segments := range expandOptional(path.Segments) {
+		if err = r.addSegments(p, segments, path.Trailing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandOptional 선택 세그먼트의 포함 여부에 따른 모든 세그먼트 조합을 반환
+func expandOptional(segments []urlpath.Segment) [][]urlpath.Segment {
+	expanded := [][]urlpath.Segment{nil}
+	for _, segment := range segments {
+		next := make([][]urlpath.Segment, 0, len(expanded)*2)
+		for _, prefix := range expanded {
+			next = append(next, append(prefix[:len(prefix):len(prefix)], segment))
+			if segment.Optional {
+				next = append(next, prefix)
+			}
+		}
+		expanded = next
+	}
+	return expanded
+}
+
+// addSegments 세그먼트 조합 하나를 트리에 등록
+func (r *Router) addSegments(p *Proxy, segments []urlpath.Segment, trailing bool) error {
+	node := r.root
+	var paramNames []string
+	for _, segment := range segments {
+		if segment.Wildcard {
+			return fmt.Errorf("failed to add route, name=%s, pattern=%s, err=wildcard segments are not supported", p.Name, p.Pattern)
+		}
+		if !segment.IsParam {
+			child, ok := node.static[segment.Const]
+			if !ok {
+				child = newRouteNode()
+				node.static[segment.Const] = child
+			}
+			node = child
+			continue
+		}
+		if node.param == nil {
+			node.param = newRouteNode()
+			node.paramName = segment.Param
+			node.paramRule = segment
+		} else if node.paramName != segment.Param {
+			return fmt.Errorf("failed to add route, name=%s, pattern=%s, err=conflicting param name :%s with :%s", p.Name, p.Pattern, segment.Param, node.paramName)
+		} else if node.paramRule.Constraint != segment.Constraint {
+			return fmt.Errorf("failed to add route, name=%s, pattern=%s, err=conflicting param constraint <%s> with <%s>", p.Name, p.Pattern, segment.Constraint, node.paramRule.Constraint)
+		}
+		paramNames = append(paramNames, segment.Param)
+		node = node.param
+	}
+
+	routes := &node.routes
+	if trailing {
+		routes = &node.trailing
+	}
+	if *routes == nil {
+		*routes = make(map[string]*route)
+	}
+	if existing, ok := (*routes)[p.Method]; ok {
+		return fmt.Errorf("failed to add route, name=%s, pattern=%s, err=conflicting route %s %s", p.Name, p.Pattern, existing.proxy.Name, existing.proxy.Pattern)
+	}
+	(*routes)[p.Method] = &route{proxy: p, paramNames: paramNames}
+	return nil
+}
+
+// Match 메소드와 인코딩된 경로(url.URL.EscapedPath)에 해당하는 proxy 와 디코딩된 경로 파라미터를 반환
+// 인코딩된 슬래시(%2F)는 세그먼트를 나누지 않으며, * 에 매칭된 나머지 경로는 인코딩된 채로 반환한다.
+func (r *Router) Match(method, path string) (*Proxy, Params, bool) {
+	rawSegments := strings.Split(path, "/")
+	segments := make([]string, len(rawSegments))
+	for i, rawSegment := range rawSegments {
+		segment, err := url.PathUnescape(rawSegment)
+		if err != nil {
+			return nil, Params{}, false
+		}
+		segments[i] = segment
+	}
+	values := make([]string, 0, 4)
+	matched, values, trailing := r.root.match(method, segments, rawSegments, values)
+	if matched == nil {
+		return nil, Params{}, false
+	}
+	return matched.proxy, Params{Names: matched.paramNames, Values: values, Trailing: trailing}, true
+}
+
+// match 남은 세그먼트로 노드를 탐색, 고정 세그먼트에서 실패하면 파라미터, * 순서로 되돌아가 탐색
+func (n *routeNode) match(method string, segments, rawSegments []string, values []string) (*route, []string, string) {
+	if len(segments) == 0 {
+		if found, ok := n.routes[method]; ok {
+			return found, values, ""
+		}
+		return nil, values, ""
+	}
+	if child, ok := n.static[segments[0]]; ok {
+		if found, matchedValues, trailing := child.match(method, segments[1:], rawSegments[1:], values); found != nil {
+			return found, matchedValues, trailing
+		}
+	}
+	if n.param != nil && n.paramRule.Allows(segments[0]) {
+		if found, matchedValues, trailing := n.param.match(method, segments[1:], rawSegments[1:], append(values, segments[0])); found != nil {
+			return found, matchedValues, trailing
+		}
+	}
+	if found, ok := n.trailing[method]; ok {
+		return found, values, strings.Join(rawSegments, "/")
+	}
+	return nil, values, ""
+}
@@ -0,0 +1,194 @@
+package proxy_test
+
+import (
+	"echo-server/proxy"
+	"echo-server/util/collection"
+	urlpath "echo-server/util/url"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var testProxies = []proxy.Proxy{
+	{Name: "namespaces", Method: http.MethodGet, Pattern: "/api/namespaces"},
+	{Name: "istioConfig", Method: http.MethodGet, Pattern: "/api/namespaces/:namespace/istio"},
+	{Name: "istioConfigDetails", Method: http.MethodGet, Pattern: "/api/namespaces/:namespace/istio/:objectType/:object"},
+	{Name: "istioConfigUpdate", Method: http.MethodPatch, Pattern: "/api/namespaces/:namespace/istio/:objectType/:object"},
+	{Name: "graph", Method: http.MethodGet, Pattern: "/api/namespaces/graph"},
+	{Name: "files", Method: http.MethodGet, Pattern: "/api/namespaces/:namespace/files/*"},
+	{Name: "workload", Method: http.MethodGet, Pattern: "/api/workloads/:id<int>/:tab?"},
+}
+
+func TestRouterMatch(t *testing.T) {
+	router, err := proxy.NewRouter(testProxies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		method   string
+		path     string
+		name     string
+		params   map[string]string
+		trailing string
+	}{
+		{http.MethodGet, "/api/namespaces", "namespaces", map[string]string{}, ""},
+		{http.MethodGet, "/api/namespaces/graph", "graph", map[string]string{}, ""},
+		{http.MethodGet, "/api/namespaces/bookinfo/istio", "istioConfig", map[string]string{"namespace": "bookinfo"}, ""},
+		// graph 고정 세그먼트 이후 매칭에 실패하면 파라미터로 되돌아가 매칭
+		{http.MethodGet, "/api/namespaces/graph/istio", "istioConfig", map[string]string{"namespace": "graph"}, ""},
+		{http.MethodGet, "/api/namespaces/bookinfo/istio/virtualservices/reviews", "istioConfigDetails",
+			map[string]string{"namespace": "bookinfo", "objectType": "virtualservices", "object": "reviews"}, ""},
+		{http.MethodPatch, "/api/namespaces/bookinfo/istio/virtualservices/reviews", "istioConfigUpdate",
+			map[string]string{"namespace": "bookinfo", "objectType": "virtualservices", "object": "reviews"}, ""},
+		{http.MethodGet, "/api/namespaces/bookinfo/files/a/b.txt", "files", map[string]string{"namespace": "bookinfo"}, "a/b.txt"},
+		{http.MethodGet, "/api/namespaces/bookinfo/files/", "files", map[string]string{"namespace": "bookinfo"}, ""},
+		// 인코딩된 슬래시는 파라미터 값의 일부
+		{http.MethodGet, "/api/namespaces/bookinfo/istio/virtualservices/a%2Fb", "istioConfigDetails",
+			map[string]string{"namespace": "bookinfo", "objectType": "virtualservices", "object": "a/b"}, ""},
+		{http.MethodGet, "/api/namespaces/bookinfo/files/a%2Fb/c", "files", map[string]string{"namespace": "bookinfo"}, "a%2Fb/c"},
+		// 제약 조건과 선택 세그먼트
+		{http.MethodGet, "/api/workloads/42", "workload", map[string]string{"id": "42"}, ""},
+		{http.MethodGet, "/api/workloads/42/logs", "workload", map[string]string{"id": "42", "tab": "logs"}, ""},
+		{http.MethodGet, "/api/workloads/abc", "", nil, ""},
+		{http.MethodGet, "/api/namespaces/%zz/istio", "", nil, ""},
+		{http.MethodDelete, "/api/namespaces/bookinfo/istio/virtualservices/reviews", "", nil, ""},
+		{http.MethodGet, "/api/namespaces/bookinfo/files", "", nil, ""},
+		{http.MethodGet, "/api/namespaces/bookinfo/istio/virtualservices", "", nil, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.method+" "+test.path, func(t *testing.T) {
+			found, params, ok := router.Match(test.method, test.path)
+			if test.name == "" {
+				if ok {
+					t.Fatalf("expected no match, got %s", found.Name)
+				}
+				return
+			}
+			if !ok || found.Name != test.name {
+				t.Fatalf("expected %s, got %v", test.name, found)
+			}
+			values := make(map[string]string)
+			for _, name := range params.Names {
+				values[name] = params.Get(name)
+			}
+			if !reflect.DeepEqual(values, test.params) || params.Trailing != test.trailing {
+				t.Errorf("unexpected params %+v", params)
+			}
+
+			// 기존 urlpath 매칭과 같은 결과인지 확인
+			pattern := urlpath.New(found.Pattern)
+			match, matchOK := pattern.Match(test.path)
+			if !matchOK || !reflect.DeepEqual(match.Params, test.params) || match.Trailing != test.trailing {
+				t.Errorf("urlpath mismatch %+v", match)
+			}
+		})
+	}
+}
+
+func TestRouterConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies []proxy.Proxy
+	}{
+		{"duplicate route", []proxy.Proxy{
+			{Name: "a", Method: http.MethodGet, Pattern: "/api/istio/config"},
+			{Name: "b", Method: http.MethodGet, Pattern: "/api/istio/config"},
+		}},
+		{"same shape with different param", []proxy.Proxy{
+			{Name: "a", Method: http.MethodGet, Pattern: "/api/namespaces/:namespace"},
+			{Name: "b", Method: http.MethodGet, Pattern: "/api/namespaces/:ns"},
+		}},
+		{"different param name at same position", []proxy.Proxy{
+			{Name: "a", Method: http.MethodGet, Pattern: "/api/namespaces/:namespace/istio"},
+			{Name: "b", Method: http.MethodPost, Pattern: "/api/namespaces/:ns/apps"},
+		}},
+		{"different constraint at same position", []proxy.Proxy{
+			{Name: "a", Method: http.MethodGet, Pattern: "/api/workloads/:id<int>"},
+			{Name: "b", Method: http.MethodGet, Pattern: "/api/workloads/:id/logs"},
+		}},
+		{"optional segment overlaps route", []proxy.Proxy{
+			{Name: "a", Method: http.MethodGet, Pattern: "/api/istio/config"},
+			{Name: "b", Method: http.MethodGet, Pattern: "/api/istio/config/:objectType?"},
+		}},
+		{"wildcard segment", []proxy.Proxy{
+			{Name: "a", Method: http.MethodGet, Pattern: "/api/*/config"},
+		}},
+		{"invalid constraint", []proxy.Proxy{
+			{Name: "a", Method: http.MethodGet, Pattern: "/api/workloads/:id<[0-9>"},
+		}},
+		{"duplicate trailing route", []proxy.Proxy{
+			{Name: "a", Method: http.MethodGet, Pattern: "/api/files/*"},
+			{Name: "b", Method: http.MethodGet, Pattern: "/api/files/*"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := proxy.NewRouter(test.proxies); err == nil {
+				t.Error("expected conflict error")
+			}
+		})
+	}
+
+	if _, err := proxy.NewRouter([]proxy.Proxy{
+		{Name: "a", Method: http.MethodGet, Pattern: "/api/istio/config"},
+		{Name: "b", Method: http.MethodPost, Pattern: "/api/istio/config"},
+		{Name: "c", Method: http.MethodGet, Pattern: "/api/istio/config/*"},
+	}); err != nil {
+		t.Errorf("unexpected conflict %s", err)
+	}
+}
+
+func TestProxiesRouter(t *testing.T) {
+	if _, err := proxy.NewRouter(*proxy.Proxies); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// benchmarkProxies 경로 패턴 수가 n 개인 proxy 목록
+func benchmarkProxies(n int) []proxy.Proxy {
+	proxies := make([]proxy.Proxy, 0, n)
+	for i := 0; len(proxies) < n; i++ {
+		proxies = append(proxies,
+			proxy.Proxy{Name: fmt.Sprint("list", i), Method: http.MethodGet, Pattern: fmt.Sprintf("/api/resource%d/:namespace", i)},
+			proxy.Proxy{Name: fmt.Sprint("detail", i), Method: http.MethodGet, Pattern: fmt.Sprintf("/api/resource%d/:namespace/istio/:objectType/:object", i)},
+		)
+	}
+	return proxies
+}
+
+const benchmarkPath = "/api/resource24/bookinfo/istio/virtualservices/reviews"
+
+// BenchmarkLinearScan 요청마다 모든 패턴을 파싱하여 순서대로 비교하는 기존 방식
+func BenchmarkLinearScan(b *testing.B) {
+	proxies := benchmarkProxies(50)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var match urlpath.Match
+		_, ok := collection.Find(proxies, func(p proxy.Proxy) bool {
+			pattern := urlpath.New(p.Pattern)
+			var patternOK bool
+			match, patternOK = pattern.Match(benchmarkPath)
+			return patternOK && p.Method == http.MethodGet
+		})
+		if !ok || match.Params["object"] != "reviews" {
+			b.Fatal("no match")
+		}
+	}
+}
+
+// BenchmarkRouter 미리 컴파일한 라우터로 매칭
+func BenchmarkRouter(b *testing.B) {
+	router, err := proxy.NewRouter(benchmarkProxies(50))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, params, ok := router.Match(http.MethodGet, benchmarkPath)
+		if !ok || params.Get("object") != "reviews" {
+			b.Fatal("no match")
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// metadataContextKey Enrich 트랜스포머가 응답에 추가할 필드를 보관하는 컨텍스트 키
+const metadataContextKey = "proxy.metadata"
+
+// RequestHook Kiali 로 전달하기 전에 사용자의 요청을 검사하거나 수정하는 함수
+type RequestHook func(c echo.Context, request *http.Request) error
+
+// ResponseHook Kiali 의 성공 응답 본문을 받아 사용자에게 전달할 본문을 반환하는 함수
+type ResponseHook func(c echo.Context, body []byte) ([]byte, error)
+
+// Transformer Kiali 모델 타입으로 디코딩된 응답값을 변환하는 함수
+type Transformer[T any] func(c echo.Context, value *T) error
+
+// Transform 응답 본문을 Kiali 모델 타입(T)으로 디코딩하여 트랜스포머를 순서대로 실행한 후 다시 인코딩하는 ResponseHook 을 생성
+// Enrich 로 추가한 필드는 인코딩한 JSON 객체의 최상위 필드로 병합한다.
+/* Transform[models.IstioConfigDetails](Redact[models.IstioConfigDetails]("help"), Enrich(...))
+ */
+func Transform[T any](transformers ...Transformer[T]) ResponseHook {
+	return func(c echo.Context, body []byte) ([]byte, error) {
+		value, err := RunTransformers(c, body, transformers...)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode response, err=%s", err)
+		}
+		return mergeMetadata(c, encoded)
+	}
+}
+
+// RunTransformers 응답 본문을 T 로 디코딩하여 트랜스포머를 순서대로 실행한 결과를 반환
+func RunTransformers[T any](c echo.Context, body []byte, transformers ...Transformer[T]) (*T, error) {
+	value := new(T)
+	if err := json.Unmarshal(body, value); err != nil {
+		return nil, fmt.Errorf("failed to decode response, type=%T, err=%s", *value, err)
+	}
+	for _, transformer := range transformers {
+		if err := transformer(c, value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// FilterSlice 응답값의 슬라이스(items 가 반환)에서 조건을 만족하는 원소만 남기는 트랜스포머
+/* FilterSlice(func(list *[]models.Namespace) *[]models.Namespace { return list }, func(c echo.Context, ns models.Namespace) bool { return ns.Name != "kube-system" })
+ */
+func FilterSlice[T any, E any](items func(value *T) *[]E, keep func(c echo.Context, item E) bool) Transformer[T] {
+	return func(c echo.Context, value *T) error {
+		slice := items(value)
+		filtered := (*slice)[:0]
+		for _, item := range *slice {
+			if keep(c, item) {
+				filtered = append(filtered, item)
+			}
+		}
+		*slice = filtered
+		return nil
+	}
+}
+
+// Redact JSON 필드 경로(점으로 구분)에 해당하는 값을 제로값으로 바꾸는 트랜스포머, 경로 중간의 슬라이스는 모든 원소에 적용
+/* Redact[models.IstioConfigDetails]("help", "validation.checks")
+ */
+func Redact[T any](paths ...string) Transformer[T] {
+	return func(c echo.Context, value *T) error {
+		for _, path := range paths {
+			if !redact(reflect.ValueOf(value).Elem(), strings.Split(path, ".")) {
+				return fmt.Errorf("failed to redact field, type=%T, path=%s", *value, path)
+			}
+		}
+		return nil
+	}
+}
+
+// redact 값에서 JSON 필드 경로를 따라가 마지막 필드를 제로값으로 설정, 경로가 타입에 없으면 false
+func redact(value reflect.Value, path []string) bool {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return true
+		}
+		return redact(value.Elem(), path)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if !redact(value.Index(i), path) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return false
+		}
+		key := reflect.ValueOf(path[0]).Convert(value.Type().Key())
+		if len(path) == 1 {
+			value.SetMapIndex(key, reflect.Value{})
+			return true
+		}
+		item := value.MapIndex(key)
+		if !item.IsValid() {
+			return true
+		}
+		// 맵의 값은 주소를 얻을 수 없으므로 복사본을 수정하여 다시 설정
+		copied := reflect.New(item.Type()).Elem()
+		copied.Set(item)
+		if !redact(copied, path[1:]) {
+			return false
+		}
+		value.SetMapIndex(key, copied)
+		return true
+	case reflect.Struct:
+		field, ok := fieldByJSONName(value, path[0])
+		if !ok {
+			return false
+		}
+		if len(path) == 1 {
+			field.Set(reflect.Zero(field.Type()))
+			return true
+		}
+		return redact(field, path[1:])
+	}
+	return false
+}
+
+// fieldByJSONName 구조체에서 JSON 이름(태그가 없으면 필드 이름)이 같은 필드를 찾음, 임베디드 구조체도 탐색
+func fieldByJSONName(value reflect.Value, name string) (reflect.Value, bool) {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		structField := valueType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		tagName, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if tagName == "-" {
+			continue
+		}
+		if structField.Anonymous && tagName == "" {
+			embedded := value.Field(i)
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if field, ok := fieldByJSONName(embedded, name); ok {
+					return field, true
+				}
+			}
+			continue
+		}
+		if tagName == name || (tagName == "" && structField.Name == name) {
+			return value.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// Enrich 응답값으로 계산한 값을 응답 JSON 객체의 최상위 필드(key)로 추가하는 트랜스포머
+/* Enrich("cluster", func(c echo.Context, value *models.IstioConfigDetails) (interface{}, error) { return "east", nil })
+ */
+func Enrich[T any](key string, enrich func(c echo.Context, value *T) (interface{}, error)) Transformer[T] {
+	return func(c echo.Context, value *T) error {
+		enriched, err := enrich(c, value)
+		if err != nil {
+			return err
+		}
+		metadata, _ := c.Get(metadataContextKey).(map[string]interface{})
+		if metadata == nil {
+			metadata = make(map[string]interface{})
+			c.Set(metadataContextKey, metadata)
+		}
+		metadata[key] = enriched
+		return nil
+	}
+}
+
+// mergeMetadata Enrich 로 추가한 필드를 인코딩한 JSON 객체에 병합
+func mergeMetadata(c echo.Context, encoded []byte) ([]byte, error) {
+	metadata, _ := c.Get(metadataContextKey).(map[string]interface{})
+	if len(metadata) == 0 {
+		return encoded, nil
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(encoded), []byte("{")) {
+		return nil, fmt.Errorf("failed to enrich response, err=response is not a json object")
+	}
+	object := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(encoded, &object); err != nil {
+		return nil, fmt.Errorf("failed to enrich response, err=%s", err)
+	}
+	for key, value := range metadata {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to enrich response, key=%s, err=%s", key, err)
+		}
+		object[key] = raw
+	}
+	return json.Marshal(object)
+}
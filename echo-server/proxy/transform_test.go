@@ -0,0 +1,86 @@
+package proxy_test
+
+import (
+	"echo-server/proxy"
+	"echo-server/proxy/proxytest"
+	"errors"
+	"github.com/kiali/kiali/models"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFilterSlice(t *testing.T) {
+	c := proxytest.NewContext(http.MethodGet, "/api/namespaces", nil)
+	namespaces := proxytest.Run(t, c, "testdata/namespaces.json",
+		proxy.FilterSlice(func(list *[]models.Namespace) *[]models.Namespace { return list },
+			func(c echo.Context, namespace models.Namespace) bool {
+				return !strings.HasSuffix(namespace.Name, "-system")
+			}),
+	)
+	if len(*namespaces) != 1 || (*namespaces)[0].Name != "bookinfo" {
+		t.Errorf("unexpected namespaces %v", *namespaces)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	c := proxytest.NewContext(http.MethodGet, "/api/namespaces/bookinfo/istio/virtualservices/reviews", nil)
+	details := proxytest.Run(t, c, "testdata/istio_config_details.json",
+		proxy.Redact[models.IstioConfigDetails]("help", "validation.checks", "permissions.delete", "virtualService.metadata.namespace"),
+	)
+	if details.IstioConfigHelpFields != nil || details.IstioValidation.Checks != nil {
+		t.Errorf("expected redacted fields, got %+v", details)
+	}
+	if details.VirtualService.Namespace != "" || details.VirtualService.Name != "reviews" {
+		t.Errorf("unexpected virtual service metadata %+v", details.VirtualService.ObjectMeta)
+	}
+	if !details.IstioValidation.Valid || !details.Permissions.Update {
+		t.Errorf("unexpected redaction of other fields %+v", details)
+	}
+
+	if _, err := proxy.RunTransformers(c, proxytest.LoadFixture(t, "testdata/istio_config_details.json"),
+		proxy.Redact[models.IstioConfigDetails]("unknown")); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestTransform(t *testing.T) {
+	c := proxytest.NewContext(http.MethodGet, "/api/namespaces/bookinfo/istio/virtualservices/reviews", map[string]string{"object": "reviews"})
+	hook := proxy.Transform[models.IstioConfigDetails](
+		proxy.Redact[models.IstioConfigDetails]("help", "validation", "virtualService"),
+		proxy.Enrich("console", func(c echo.Context, details *models.IstioConfigDetails) (interface{}, error) {
+			return map[string]interface{}{"object": c.Param("object"), "editable": details.Permissions.Update}, nil
+		}),
+	)
+	proxytest.AssertJSONEqual(t, `{
+		"namespace": {"name": "bookinfo", "cluster": "east", "labels": null, "annotations": null},
+		"objectType": "virtualservices",
+		"authorizationPolicy": null, "destinationRule": null, "envoyFilter": null, "gateway": null,
+		"peerAuthentication": null, "requestAuthentication": null, "serviceEntry": null, "sidecar": null,
+		"virtualService": null, "workloadEntry": null, "workloadGroup": null, "wasmPlugin": null, "telemetry": null,
+		"k8sGateway": null, "k8sHTTPRoute": null,
+		"permissions": {"create": true, "update": true, "delete": false},
+		"validation": null, "references": null, "help": null,
+		"console": {"object": "reviews", "editable": true}
+	}`, proxytest.Apply(t, c, hook, "testdata/istio_config_details.json"))
+}
+
+func TestTransformError(t *testing.T) {
+	c := proxytest.NewContext(http.MethodGet, "/api/namespaces", nil)
+	forbidden := echo.NewHTTPError(http.StatusForbidden, "forbidden namespace")
+	hook := proxy.Transform[[]models.Namespace](func(c echo.Context, namespaces *[]models.Namespace) error {
+		return forbidden
+	})
+	if _, err := hook(c, proxytest.LoadFixture(t, "testdata/namespaces.json")); !errors.Is(err, forbidden) {
+		t.Errorf("expected transformer error, got %v", err)
+	}
+
+	// 배열 응답에는 필드를 추가할 수 없음
+	enrich := proxy.Transform[[]models.Namespace](proxy.Enrich("count", func(c echo.Context, namespaces *[]models.Namespace) (interface{}, error) {
+		return len(*namespaces), nil
+	}))
+	if _, err := enrich(proxytest.NewContext(http.MethodGet, "/api/namespaces", nil), proxytest.LoadFixture(t, "testdata/namespaces.json")); err == nil {
+		t.Error("expected enrich error for array response")
+	}
+}
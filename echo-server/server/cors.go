@@ -0,0 +1,68 @@
+package server
+
+import (
+	"echo-server/config"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// corsMiddleware 허용한 Origin 의 요청에 CORS 헤더를 붙이고 preflight 요청에 응답하는 미들웨어
+/* OPTIONS /api/console/servicemesh/namespaces, Origin: https://console.example.com, Access-Control-Request-Method: GET
+   => 204, Access-Control-Allow-Origin: https://console.example.com, Access-Control-Allow-Methods: GET,HEAD,POST,...
+*/
+func corsMiddleware(cfg config.CORSConfig) echo.MiddlewareFunc {
+	origins := make(map[string]bool, len(cfg.AllowOrigins))
+	for _, origin := range cfg.AllowOrigins {
+		origins[origin] = true
+	}
+	allowMethods := strings.Join(cfg.AllowMethods, ",")
+	allowHeaders := strings.Join(cfg.AllowHeaders, ",")
+	exposeHeaders := strings.Join(cfg.ExposeHeaders, ",")
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			request := c.Request()
+			header := c.Response().Header()
+			origin := request.Header.Get(echo.HeaderOrigin)
+			preflight := request.Method == http.MethodOptions && request.Header.Get(echo.HeaderAccessControlRequestMethod) != ""
+
+			header.Add(echo.HeaderVary, echo.HeaderOrigin)
+			if origin == "" || !(origins["*"] || origins[origin]) {
+				if preflight {
+					return c.NoContent(http.StatusNoContent)
+				}
+				return next(c)
+			}
+
+			if origins["*"] {
+				header.Set(echo.HeaderAccessControlAllowOrigin, "*")
+			} else {
+				header.Set(echo.HeaderAccessControlAllowOrigin, origin)
+			}
+			if cfg.AllowCredentials {
+				header.Set(echo.HeaderAccessControlAllowCredentials, "true")
+			}
+			if !preflight {
+				if exposeHeaders != "" {
+					header.Set(echo.HeaderAccessControlExposeHeaders, exposeHeaders)
+				}
+				return next(c)
+			}
+
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestMethod)
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestHeaders)
+			header.Set(echo.HeaderAccessControlAllowMethods, allowMethods)
+			if allowHeaders != "" {
+				header.Set(echo.HeaderAccessControlAllowHeaders, allowHeaders)
+			} else if requested := request.Header.Get(echo.HeaderAccessControlRequestHeaders); requested != "" {
+				header.Set(echo.HeaderAccessControlAllowHeaders, requested)
+			}
+			if cfg.MaxAge > 0 {
+				header.Set(echo.HeaderAccessControlMaxAge, strconv.Itoa(cfg.MaxAge))
+			}
+			return c.NoContent(http.StatusNoContent)
+		}
+	}
+}
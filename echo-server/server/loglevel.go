@@ -0,0 +1,43 @@
+package server
+
+import (
+	"echo-server/auth"
+	"echo-server/logging"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// LogLevel 로그 레벨 조회, 변경 API 의 요청과 응답
+/* PUT /admin/loglevel {"level":"debug"} => {"level":"debug"}
+ */
+type LogLevel struct {
+	Level string `json:"level"`
+}
+
+// logLevelRoles 로그 레벨을 조회, 변경할 수 있는 역할
+var logLevelRoles = []auth.Role{auth.RoleOSManager}
+
+// GetLogLevel 현재 로그 레벨을 반환
+func GetLogLevel(c echo.Context) error {
+	if authErr := auth.Authorize(c, logLevelRoles); authErr != nil {
+		return auth.WriteError(c, authErr)
+	}
+	return c.JSON(http.StatusOK, LogLevel{Level: logging.Level()})
+}
+
+// SetLogLevel 실행 중인 서버의 로그 레벨을 변경(재시작하면 설정의 레벨로 돌아감)
+func SetLogLevel(c echo.Context) error {
+	if authErr := auth.Authorize(c, logLevelRoles); authErr != nil {
+		return auth.WriteError(c, authErr)
+	}
+	var request LogLevel
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	previous := logging.Level()
+	if err := logging.SetLevel(request.Level); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	logging.Ctx(c.Request().Context()).Warn().Str("from", previous).Str("to", logging.Level()).Msg("log level changed")
+	return c.JSON(http.StatusOK, LogLevel{Level: logging.Level()})
+}
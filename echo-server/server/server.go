@@ -0,0 +1,315 @@
+// Package server echo-server 의 라우트 구성과 시작, 종료(graceful shutdown) 처리
+package server
+
+import (
+	"context"
+	"database/sql"
+	"echo-server/audit"
+	"echo-server/auth"
+	"echo-server/cluster"
+	"echo-server/config"
+	"echo-server/logging"
+	"echo-server/manager"
+	"echo-server/proxy"
+	"echo-server/telemetry"
+	"errors"
+	"fmt"
+	// audit sql 저장소에서 사용하는 드라이버
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Server 설정으로 구성한 echo 서버
+type Server struct {
+	Echo *echo.Echo
+
+	config       *config.Config
+	healthClient *http.Client
+	auditCloser  io.Closer                // 종료 시 닫을 변경 기록 저장소(파일, 데이터베이스 연결)
+	tracer       *sdktrace.TracerProvider // 종료 시 남은 span 을 내보낼 TracerProvider(트레이스를 설정하지 않으면 nil)
+	draining     atomic.Bool              // 종료 중이면 readiness 가 실패하여 새 요청이 들어오지 않도록 함
+}
+
+// New 설정으로 Kiali 클러스터와 업스트림 Transport 를 설정하고 라우트를 등록한 서버를 생성
+func New(cfg *config.Config) (*Server, error) {
+	if err := registerClusters(cfg.Kiali); err != nil {
+		return nil, err
+	}
+	manager.SetKialiTransport(newKialiTransport(cfg.Kiali))
+	// proxy 패턴을 라우터로 컴파일(패턴이 충돌하면 시작하지 않음)
+	if err := manager.SetProxies(*proxy.Proxies); err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		config:       cfg,
+		healthClient: &http.Client{Timeout: cfg.Kiali.HealthCheckTimeout.Duration},
+	}
+	if tracing := cfg.Telemetry.Tracing; tracing.Enabled {
+		exporter, err := telemetry.NewOTLPExporter(context.Background(), tracing.Endpoint, tracing.Headers, tracing.Timeout.Duration)
+		if err != nil {
+			return nil, err
+		}
+		s.tracer = telemetry.NewTracerProvider(sdktrace.NewBatchSpanProcessor(exporter), tracing.ServiceName, tracing.SampleRatio)
+		otel.SetTracerProvider(s.tracer)
+	}
+	sink, closer, err := newAuditSink(cfg.Audit)
+	if err != nil {
+		return nil, err
+	}
+	s.auditCloser = closer
+	manager.IstioWrites = &manager.IstioWriteGuard{
+		Validate:      cfg.IstioWrites.Validate,
+		RequireDryRun: cfg.IstioWrites.RequireDryRun,
+		DryRunTTL:     cfg.IstioWrites.DryRunTTL.Duration,
+		Sink:          sink,
+	}
+	e := echo.New()
+	// 시작 배너 대신 구조화 로그로 시작을 기록
+	e.HideBanner = true
+	e.HidePort = true
+	for _, server := range []*http.Server{e.Server, e.TLSServer} {
+		server.ReadHeaderTimeout = cfg.Server.ReadHeaderTimeout.Duration
+		server.ReadTimeout = cfg.Server.ReadTimeout.Duration
+		server.WriteTimeout = cfg.Server.WriteTimeout.Duration
+		server.IdleTimeout = cfg.Server.IdleTimeout.Duration
+	}
+	// 요청 ID 와 접근 로그(CORS preflight 를 포함한 모든 요청)
+	e.Use(logging.Middleware())
+	// 요청 span 과 RED 메트릭(요청 수, 에러, 처리 시간)
+	e.Use(telemetry.Middleware())
+	if len(cfg.CORS.AllowOrigins) > 0 {
+		e.Use(corsMiddleware(cfg.CORS))
+	}
+
+	// Kiali API 는 콘솔 JWT 로 인증(검증 키가 설정되지 않으면 인증하지 않음)
+	var kialiMiddlewares []echo.MiddlewareFunc
+	if cfg.Auth.Enabled() {
+		verifier, err := newVerifier(cfg.Auth)
+		if err != nil {
+			return nil, err
+		}
+		kialiMiddlewares = append(kialiMiddlewares, auth.Middleware(verifier, cfg.Auth.CookieName))
+		manager.SetAuthCookieName(cfg.Auth.CookieName)
+		if namespaceAuthorization := cfg.Auth.NamespaceAuthorization; namespaceAuthorization.Enabled {
+			manager.NamespaceAuthorizer = &auth.NamespaceAuthorizer{
+				Verb:     namespaceAuthorization.Verb,
+				Resource: namespaceAuthorization.Resource,
+				TTL:      namespaceAuthorization.TTL.Duration,
+			}
+		}
+	} else {
+		logging.Logger.Warn().Msg("authentication is disabled, routes with roles will respond 401")
+	}
+
+	e.GET("/", manager.HelloWorld)
+	e.GET("/livez", s.Livez)
+	e.GET("/readyz", s.Readyz)
+	if cfg.Telemetry.Metrics.Enabled {
+		e.GET("/metrics", telemetry.Metrics)
+	}
+	// 실행 중 로그 레벨 조회, 변경(os-manager 역할 필요)
+	admin := e.Group("/admin", kialiMiddlewares...)
+	admin.GET("/loglevel", GetLogLevel)
+	admin.PUT("/loglevel", SetLogLevel)
+	// Kiali API Route Root
+	kiali := e.Group(manager.KialiApiGroupPrefix, kialiMiddlewares...)
+	// 등록된 클러스터 목록과 상태
+	kiali.GET("/clusters", manager.ListClusters)
+	// 모든 요청을 하나의 Endpoint 에서 관리(그룹 미들웨어가 등록하는 "/*" 라우트를 교체하도록 "/*" 로 등록)
+	kiali.Any("/*", manager.ProxyKialiServer)
+
+	s.Echo = e
+	return s, nil
+}
+
+// registerClusters 설정 파일의 클러스터와 클러스터 설정 파일을 등록, 둘 다 없으면 kubeconfig 컨텍스트에서 Kiali 를 찾음
+func registerClusters(cfg config.KialiConfig) error {
+	if err := cfg.Config.Apply(manager.Clusters); err != nil {
+		return err
+	}
+	if cfg.ClustersFile != "" {
+		clusters, err := cluster.LoadConfig(cfg.ClustersFile)
+		if err != nil {
+			return err
+		}
+		if err = clusters.Apply(manager.Clusters); err != nil {
+			return err
+		}
+	}
+	if len(cfg.Clusters) == 0 && cfg.ClustersFile == "" {
+		for _, err := range cluster.DiscoverKubeconfig(context.Background(), manager.Clusters, cfg.Namespace) {
+			logging.Logger.Warn().Err(err).Msg("failed to discover kiali")
+		}
+	}
+	return nil
+}
+
+// newKialiTransport 연결과 응답 헤더 대기 시간을 제한하고 호출을 로그로 남기는 Kiali 호출 Transport
+// 응답 본문은 스트리밍으로 전달하므로 전체 응답 시간은 제한하지 않는다.
+func newKialiTransport(cfg config.KialiConfig) http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: cfg.DialTimeout.Duration, KeepAlive: 30 * time.Second}).DialContext
+	transport.ResponseHeaderTimeout = cfg.ResponseHeaderTimeout.Duration
+	// 요청 ID 와 traceparent 를 Kiali 로 전달하고 Kiali 호출을 접근 로그, 메트릭, span 으로 남김
+	return logging.Transport(telemetry.Transport(transport))
+}
+
+// newVerifier 설정의 검증 키로 JWT Verifier 를 생성
+func newVerifier(cfg config.AuthConfig) (*auth.Verifier, error) {
+	verifier := &auth.Verifier{Issuer: cfg.Issuer, Audience: cfg.Audience, Leeway: cfg.Leeway.Duration}
+	if cfg.HMACSecret != "" {
+		verifier.HMACKey = []byte(cfg.HMACSecret)
+	}
+	switch {
+	case cfg.JWKSURL != "":
+		verifier.Keys = &auth.JWKS{URL: cfg.JWKSURL, Client: &http.Client{Timeout: 10 * time.Second}}
+	case cfg.PublicKeyFile != "":
+		bytes, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwt public key, path=%s, err=%s", cfg.PublicKeyFile, err)
+		}
+		key, err := auth.ParseRSAPublicKey(bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jwt public key, path=%s, err=%s", cfg.PublicKeyFile, err)
+		}
+		verifier.Keys = auth.StaticKeys{"": key}
+	}
+	return verifier, nil
+}
+
+// newAuditSink 설정의 Istio 설정 변경 기록 저장소와 종료 시 닫을 자원을 생성, 설정하지 않으면 nil
+func newAuditSink(cfg config.AuditConfig) (audit.Sink, io.Closer, error) {
+	switch cfg.Sink {
+	case "file":
+		sink, err := audit.NewFileSink(cfg.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sink, sink, nil
+	case "sql":
+		db, err := sql.Open(cfg.SQL.Driver, cfg.SQL.DSN)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open audit database, driver=%s, err=%s", cfg.SQL.Driver, err)
+		}
+		sink, err := audit.NewSQLSink(db, cfg.SQL.Table)
+		if err != nil {
+			_ = db.Close()
+			return nil, nil, err
+		}
+		return sink, db, nil
+	}
+	logging.Logger.Info().Msg("istio config audit is disabled")
+	return nil, nil, nil
+}
+
+// Run 서버를 시작하고 ctx 가 종료되거나 SIGINT/SIGTERM 을 받으면 처리 중인 요청을 기다린 후 종료
+// ShutdownTimeout 안에 끝나지 않은 요청(스트리밍 응답 등)은 연결을 끊는다.
+func (s *Server) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	manager.Clusters.StartHealthChecks(ctx, s.config.Kiali.HealthCheckInterval.Duration, s.healthClient)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- s.start()
+	}()
+	select {
+	case err := <-errs:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+	return s.shutdown()
+}
+
+func (s *Server) start() error {
+	logging.Logger.Info().Str("address", s.config.Server.Address).Bool("tls", s.config.Server.TLS.Enabled()).Msg("server started")
+	if tls := s.config.Server.TLS; tls.Enabled() {
+		return s.Echo.StartTLS(s.config.Server.Address, tls.CertFile, tls.KeyFile)
+	}
+	return s.Echo.Start(s.config.Server.Address)
+}
+
+// shutdown 새 연결을 받지 않고 처리 중인 요청이 끝날 때까지 ShutdownTimeout 동안 기다림
+func (s *Server) shutdown() error {
+	timeout := s.config.Server.ShutdownTimeout.Duration
+	logging.Logger.Info().Dur("timeout", timeout).Msg("shutting down server")
+	s.draining.Store(true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := s.Echo.Shutdown(ctx); err != nil {
+		_ = s.Echo.Close()
+		s.closeAudit()
+		s.flushTraces()
+		return fmt.Errorf("failed to shutdown server gracefully, err=%s", err)
+	}
+	s.closeAudit()
+	s.flushTraces()
+	logging.Logger.Info().Msg("server stopped")
+	return nil
+}
+
+// closeAudit 처리 중인 요청이 모두 끝난 후 변경 기록 저장소를 닫음
+func (s *Server) closeAudit() {
+	if s.auditCloser == nil {
+		return
+	}
+	if err := s.auditCloser.Close(); err != nil {
+		logging.Logger.Error().Err(err).Msg("failed to close audit sink")
+	}
+}
+
+// flushTraces 남은 span 을 내보내고 TracerProvider 를 종료
+func (s *Server) flushTraces() {
+	if s.tracer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Telemetry.Tracing.Timeout.Duration)
+	defer cancel()
+	if err := s.tracer.Shutdown(ctx); err != nil {
+		logging.Logger.Error().Err(err).Msg("failed to flush traces")
+	}
+}
+
+// Livez 프로세스가 요청을 처리할 수 있는지 확인(liveness probe)
+func (s *Server) Livez(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Readiness readiness probe 응답
+type Readiness struct {
+	Ready    bool                      `json:"ready"`
+	Draining bool                      `json:"draining,omitempty"`
+	Clusters map[string]cluster.Status `json:"clusters"`
+}
+
+// Readyz 등록된 클러스터의 Kiali 상태를 확인하여 하나 이상 응답하면 200, 종료 중이거나 모두 응답하지 않으면 503(readiness probe)
+func (s *Server) Readyz(c echo.Context) error {
+	readiness := Readiness{Draining: s.draining.Load(), Clusters: map[string]cluster.Status{}}
+	if !readiness.Draining {
+		manager.Clusters.CheckHealth(c.Request().Context(), s.healthClient)
+		for _, item := range manager.Clusters.List() {
+			status := manager.Clusters.Status(item.Name)
+			readiness.Clusters[item.Name] = status
+			readiness.Ready = readiness.Ready || status.Healthy
+		}
+	}
+	if !readiness.Ready {
+		return c.JSON(http.StatusServiceUnavailable, readiness)
+	}
+	return c.JSON(http.StatusOK, readiness)
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"context"
+	"echo-server/cluster"
+	"echo-server/config"
+	"echo-server/telemetry/telemetrytest"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestServer Kiali 스텁 하나를 기본 클러스터로 등록한 서버
+func newTestServer(t *testing.T, kiali http.HandlerFunc) *Server {
+	t.Helper()
+	upstream := httptest.NewServer(kiali)
+	t.Cleanup(upstream.Close)
+
+	cfg := config.Default()
+	cfg.Server.Address = "127.0.0.1:0"
+	cfg.Kiali.DefaultCluster = "test"
+	cfg.Kiali.Clusters = []cluster.Cluster{{Name: "test", KialiAddress: upstream.URL}}
+	cfg.CORS.AllowOrigins = []string{"https://console.example.com"}
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func serve(s *Server, request *http.Request) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	s.Echo.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestReadyz(t *testing.T) {
+	var unhealthy atomic.Bool
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if unhealthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+
+	if recorder := serve(s, httptest.NewRequest(http.MethodGet, "/livez", nil)); recorder.Code != http.StatusOK {
+		t.Errorf("unexpected livez status %d", recorder.Code)
+	}
+	if recorder := serve(s, httptest.NewRequest(http.MethodGet, "/readyz", nil)); recorder.Code != http.StatusOK {
+		t.Errorf("unexpected readyz status %d, body=%s", recorder.Code, recorder.Body)
+	}
+
+	unhealthy.Store(true)
+	if recorder := serve(s, httptest.NewRequest(http.MethodGet, "/readyz", nil)); recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected unready when kiali is unhealthy, status=%d", recorder.Code)
+	}
+
+	unhealthy.Store(false)
+	s.draining.Store(true)
+	if recorder := serve(s, httptest.NewRequest(http.MethodGet, "/readyz", nil)); recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected unready while draining, status=%d", recorder.Code)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/api/console/servicemesh/namespaces", nil)
+	preflight.Header.Set("Origin", "https://console.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	recorder := serve(s, preflight)
+	if recorder.Code != http.StatusNoContent || recorder.Header().Get("Access-Control-Allow-Origin") != "https://console.example.com" {
+		t.Errorf("unexpected preflight response %d %v", recorder.Code, recorder.Header())
+	}
+	if recorder.Header().Get("Access-Control-Allow-Headers") == "" {
+		t.Error("expected allowed headers")
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Origin", "https://evil.example.com")
+	recorder = serve(s, request)
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("unexpected response for disallowed origin %d %v", recorder.Code, recorder.Header())
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upstream.Close()
+	cfg := config.Default()
+	cfg.Kiali.DefaultCluster = "test"
+	cfg.Kiali.Clusters = []cluster.Cluster{{Name: "test", KialiAddress: upstream.URL}}
+	cfg.Auth.HMACSecret = "hello-jwt"
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if recorder := serve(s, httptest.NewRequest(http.MethodGet, "/api/console/servicemesh/namespaces", nil)); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without token, got %d", recorder.Code)
+	}
+	if recorder := serve(s, httptest.NewRequest(http.MethodGet, "/livez", nil)); recorder.Code != http.StatusOK {
+		t.Errorf("expected probes without token, got %d", recorder.Code)
+	}
+}
+
+func TestRunDrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/namespaces" {
+			close(started)
+			time.Sleep(200 * time.Millisecond)
+			_, _ = w.Write([]byte(`[]`))
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Run(ctx)
+	}()
+	var address string
+	for i := 0; i < 100 && address == ""; i++ {
+		if addr := s.Echo.ListenerAddr(); addr != nil {
+			address = addr.String()
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if address == "" {
+		t.Fatal("server did not start")
+	}
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	results := make(chan result, 1)
+	go func() {
+		response, err := http.Get("http://" + address + "/api/console/servicemesh/namespaces")
+		if err != nil {
+			results <- result{err: err}
+			return
+		}
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		results <- result{status: response.StatusCode, body: string(body), err: err}
+	}()
+
+	// 업스트림 응답 전에 종료를 시작해도 처리 중인 요청은 끝까지 응답
+	<-started
+	cancel()
+	r := <-results
+	if r.err != nil || r.status != http.StatusOK || r.body != `[]` {
+		t.Errorf("in-flight request was not drained, status=%d, body=%s, err=%v", r.status, r.body, r.err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("unexpected shutdown error %s", err)
+	}
+	if _, err := http.Get("http://" + address + "/livez"); err == nil {
+		t.Error("expected server to stop accepting connections")
+	}
+}
+
+func TestRequestIDPropagatedToKiali(t *testing.T) {
+	received := make(chan string, 2)
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/namespaces" {
+			received <- r.Header.Get("X-Request-ID")
+		}
+	})
+
+	recorder := serve(s, httptest.NewRequest(http.MethodGet, "/api/console/servicemesh/namespaces", nil))
+	if id := <-received; id == "" || recorder.Header().Get("X-Request-ID") != id {
+		t.Errorf("expected generated request id %q in response, got %v", id, recorder.Header())
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/api/console/servicemesh/namespaces", nil)
+	request.Header.Set("X-Request-ID", "console-1234")
+	recorder = serve(s, request)
+	if id := <-received; id != "console-1234" || recorder.Header().Get("X-Request-ID") != id {
+		t.Errorf("expected request id console-1234, got %q", id)
+	}
+}
+
+func TestTelemetry(t *testing.T) {
+	exporter := telemetrytest.Install(t)
+	traceparents := make(chan string, 1)
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/namespaces" {
+			traceparents <- r.Header.Get("traceparent")
+			_, _ = io.WriteString(w, "[]")
+		}
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/api/console/servicemesh/namespaces", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if recorder := serve(s, request); recorder.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d, body=%s", recorder.Code, recorder.Body)
+	}
+
+	// 사용자 요청 span 아래에 Kiali 인증과 Kiali 호출 span
+	inbound, authenticate, upstream := exporter.Span("GET namespaces"), exporter.Span("kiali authenticate"), exporter.Span("kiali GET")
+	if inbound == nil || authenticate == nil || upstream == nil {
+		t.Fatalf("missing spans %v", exporter.Spans())
+	}
+	if inbound.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || inbound.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("inbound span does not continue the incoming trace %v", inbound.Parent())
+	}
+	if authenticate.Parent().SpanID() != inbound.SpanContext().SpanID() || upstream.Parent().SpanID() != inbound.SpanContext().SpanID() {
+		t.Error("kiali spans are not children of the inbound span")
+	}
+	if traceparent := <-traceparents; !strings.Contains(traceparent, upstream.SpanContext().SpanID().String()) {
+		t.Errorf("expected traceparent of the upstream span, got %q", traceparent)
+	}
+
+	recorder := serve(s, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, metric := range []string{
+		`echo_server_requests_total{code="200",method="GET",route="namespaces"}`,
+		`echo_server_request_duration_seconds_count{method="GET",route="namespaces"}`,
+		`echo_server_upstream_requests_total{cluster="test",code="200",method="GET"}`,
+		`echo_server_kiali_auth_refreshes_total{cluster="test",result="success"}`,
+	} {
+		if !strings.Contains(recorder.Body.String(), metric) {
+			t.Errorf("missing metric %s", metric)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package telemetry
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"net/http"
+)
+
+const namespace = "echo_server"
+
+// Registry echo-server 메트릭 레지스트리(Go 런타임, 프로세스 메트릭 포함)
+var Registry = prometheus.NewRegistry()
+
+var (
+	// requests 사용자 요청 수(route 는 일치한 proxy.Proxy 이름, 없으면 kiali 또는 echo 라우트 경로)
+	requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "requests_total",
+		Help:      "Number of requests by route, method and status code.",
+	}, []string{"route", "method", "code"})
+	// requestDuration 사용자 요청 처리 시간(응답 본문 전송 포함)
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "request_duration_seconds",
+		Help:      "Request latency by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+	// upstreamRequests Kiali 호출 수(cluster 는 클러스터 이름, 요청 밖의 호출이면 Kiali 호스트)
+	upstreamRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_requests_total",
+		Help:      "Number of Kiali requests by cluster, method and status code.",
+	}, []string{"cluster", "method", "code"})
+	// upstreamDuration Kiali 응답 헤더를 받을 때까지의 시간
+	upstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Kiali latency until response headers by cluster.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"cluster"})
+	// upstreamErrors Kiali 연결 실패(응답을 받지 못한 호출) 수
+	upstreamErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_errors_total",
+		Help:      "Number of Kiali requests that failed without a response.",
+	}, []string{"cluster"})
+	// authRefreshes Kiali 세션 인증(세션 만료, 401 재인증 포함) 수
+	authRefreshes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kiali_auth_refreshes_total",
+		Help:      "Number of Kiali session authentications by cluster and result.",
+	}, []string{"cluster", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		requests, requestDuration, upstreamRequests, upstreamDuration, upstreamErrors, authRefreshes,
+	)
+}
+
+// Metrics Registry 의 메트릭을 Prometheus 형식으로 응답(GET /metrics)
+func Metrics(c echo.Context) error {
+	families, err := Registry.Gather()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to gather metrics, err="+err.Error())
+	}
+	format := expfmt.Negotiate(c.Request().Header)
+	c.Response().Header().Set(echo.HeaderContentType, string(format))
+	c.Response().WriteHeader(http.StatusOK)
+	encoder := expfmt.NewEncoder(c.Response(), format)
+	for _, family := range families {
+		if err = encoder.Encode(family); err != nil {
+			return err
+		}
+	}
+	return nil
+}
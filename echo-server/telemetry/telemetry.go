@@ -0,0 +1,130 @@
+// Package telemetry echo-server 의 Prometheus 메트릭과 OpenTelemetry 트레이스
+// 사용자 요청, Kiali 인증, Kiali 호출마다 span 을 만들고 같은 구간의 메트릭을 기록한다.
+package telemetry
+
+import (
+	"context"
+	"echo-server/logging"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// instrumentationName span 을 만드는 tracer 이름
+const instrumentationName = "echo-server"
+
+const (
+	UnmatchedRoute = "kiali"    // proxy.Proxy 와 일치하지 않고 Kiali 로 그대로 전달한 요청의 route 레이블
+	notFoundRoute  = "notfound" // echo 라우트가 없는 요청의 route 레이블
+)
+
+// untracedPaths span 을 만들지 않는 경로(probe, 메트릭 수집 요청)
+var untracedPaths = map[string]bool{"/livez": true, "/readyz": true, "/metrics": true}
+
+// ClusterAttribute 요청 대상 클러스터 span 속성
+var ClusterAttribute = attribute.Key("kiali.cluster")
+
+// propagator 요청 헤더의 traceparent 를 읽고 Kiali 요청에 전달(TracerProvider 를 설정하지 않아도 전달)
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// tracer 현재 전역 TracerProvider 의 tracer(설정하지 않으면 span 을 기록하지 않음)
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// requestKey 요청 컨텍스트에 requestInfo 를 저장하는 키
+type requestKey struct{}
+
+// requestInfo 요청 처리 중에 정해지는 메트릭 레이블(proxy 이름, 클러스터)
+type requestInfo struct {
+	mutex   sync.Mutex
+	route   string
+	cluster string
+}
+
+// SetRoute 요청과 일치한 proxy.Proxy 이름을 요청 메트릭과 span 이름에 사용
+func SetRoute(ctx context.Context, route string) {
+	if info, ok := ctx.Value(requestKey{}).(*requestInfo); ok {
+		info.mutex.Lock()
+		info.route = route
+		info.mutex.Unlock()
+	}
+	trace.SpanFromContext(ctx).SetAttributes(semconv.HTTPRouteKey.String(route))
+}
+
+// SetCluster 요청 대상 클러스터를 Kiali 호출 메트릭과 span 에 사용
+func SetCluster(ctx context.Context, cluster string) {
+	if info, ok := ctx.Value(requestKey{}).(*requestInfo); ok {
+		info.mutex.Lock()
+		info.cluster = cluster
+		info.mutex.Unlock()
+	}
+	trace.SpanFromContext(ctx).SetAttributes(ClusterAttribute.String(cluster))
+}
+
+// labels 요청의 route, cluster 레이블
+func labels(ctx context.Context) (route string, cluster string) {
+	if info, ok := ctx.Value(requestKey{}).(*requestInfo); ok {
+		info.mutex.Lock()
+		defer info.mutex.Unlock()
+		return info.route, info.cluster
+	}
+	return "", ""
+}
+
+// Middleware 사용자 요청의 span(요청 헤더의 traceparent 를 부모로 사용)을 만들고 요청 수와 처리 시간을 기록
+// route 레이블은 SetRoute 로 지정한 proxy.Proxy 이름, 없으면 echo 라우트 경로(/readyz 등)를 사용한다.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			request := c.Request()
+			info := &requestInfo{}
+			ctx := context.WithValue(request.Context(), requestKey{}, info)
+
+			var span trace.Span
+			if !untracedPaths[request.URL.Path] {
+				ctx = propagator.Extract(ctx, propagation.HeaderCarrier(request.Header))
+				ctx, span = tracer().Start(ctx, "HTTP "+request.Method,
+					trace.WithSpanKind(trace.SpanKindServer),
+					trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(instrumentationName, "", request)...))
+				if spanContext := span.SpanContext(); spanContext.IsValid() {
+					logging.AddField(ctx, "trace_id", spanContext.TraceID().String())
+				}
+			}
+			c.SetRequest(request.WithContext(ctx))
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			route, _ := labels(ctx)
+			if route == "" {
+				route = c.Path()
+			}
+			if route == "" {
+				route = notFoundRoute
+			}
+			requests.WithLabelValues(route, request.Method, strconv.Itoa(status)).Inc()
+			requestDuration.WithLabelValues(route, request.Method).Observe(time.Since(start).Seconds())
+			if span != nil {
+				span.SetName(request.Method + " " + route)
+				span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+				if status >= http.StatusInternalServerError {
+					span.SetStatus(codes.Error, http.StatusText(status))
+				}
+				span.End()
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOTLPExporter(t *testing.T) {
+	received := make(chan *collectortrace.ExportTraceServiceRequest, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/x-protobuf" || r.Header.Get("Authorization") != "Bearer collector" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		traces := &collectortrace.ExportTraceServiceRequest{}
+		body, _ := io.ReadAll(r.Body)
+		if err := proto.Unmarshal(body, traces); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- traces
+	}))
+	defer collector.Close()
+
+	exporter, err := NewOTLPExporter(context.Background(), collector.URL+"/", map[string]string{"Authorization": "Bearer collector"}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := NewTracerProvider(sdktrace.NewSimpleSpanProcessor(exporter), "echo-server", 1)
+	defer provider.Shutdown(context.Background())
+	ctx, parent := provider.Tracer(instrumentationName).Start(context.Background(), "GET namespaces", trace.WithSpanKind(trace.SpanKindServer))
+	_, child := provider.Tracer(instrumentationName).Start(ctx, "kiali authenticate", trace.WithAttributes(ClusterAttribute.String("east")))
+	child.RecordError(errors.New("connection refused"))
+	child.SetStatus(codes.Error, "connection refused")
+	child.End()
+
+	traces := <-received
+	if len(traces.ResourceSpans) != 1 || len(traces.ResourceSpans[0].InstrumentationLibrarySpans) != 1 {
+		t.Fatalf("unexpected traces %v", traces)
+	}
+	resource := traces.ResourceSpans[0].Resource.Attributes
+	if len(resource) != 1 || resource[0].Key != "service.name" || resource[0].Value.GetStringValue() != "echo-server" {
+		t.Errorf("unexpected resource %v", resource)
+	}
+	span := traces.ResourceSpans[0].InstrumentationLibrarySpans[0].Spans[0]
+	parentContext := parent.SpanContext()
+	if span.Name != "kiali authenticate" || hex.EncodeToString(span.TraceId) != parentContext.TraceID().String() || hex.EncodeToString(span.ParentSpanId) != parentContext.SpanID().String() {
+		t.Errorf("unexpected span %v", span)
+	}
+	if span.Status.Code != tracepb.Status_STATUS_CODE_ERROR || span.Status.Message != "connection refused" || len(span.Events) != 1 || span.Events[0].Name != "exception" {
+		t.Errorf("unexpected span status %v", span)
+	}
+	if len(span.Attributes) != 1 || span.Attributes[0].Key != "kiali.cluster" || span.Attributes[0].Value.GetStringValue() != "east" {
+		t.Errorf("unexpected span attributes %v", span.Attributes)
+	}
+}
+
+func TestNewOTLPExporterInvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"otel-collector:4318", "ftp://otel-collector:4318", "http://"} {
+		if _, err := NewOTLPExporter(context.Background(), endpoint, nil, time.Second); err == nil {
+			t.Errorf("expected error for endpoint %q", endpoint)
+		}
+	}
+}
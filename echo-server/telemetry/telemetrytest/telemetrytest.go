@@ -0,0 +1,60 @@
+// Package telemetrytest 테스트에서 span 을 메모리에 기록하는 도구
+package telemetrytest
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"sync"
+	"testing"
+)
+
+// Exporter 내보낸 span 을 메모리에 보관하는 SpanExporter
+type Exporter struct {
+	mutex sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+// ExportSpans span 을 보관
+func (e *Exporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+// Shutdown 아무 것도 하지 않음
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Spans 끝난 span 목록(끝난 순서)
+func (e *Exporter) Spans() []sdktrace.ReadOnlySpan {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return append([]sdktrace.ReadOnlySpan(nil), e.spans...)
+}
+
+// Span 이름이 같은 첫 span, 없으면 nil
+func (e *Exporter) Span(name string) sdktrace.ReadOnlySpan {
+	for _, span := range e.Spans() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	return nil
+}
+
+// Install 테스트 동안 모든 span 을 바로 Exporter 에 기록하는 전역 TracerProvider 를 설정
+func Install(t *testing.T) *Exporter {
+	t.Helper()
+	exporter := &Exporter{}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	original := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+		otel.SetTracerProvider(original)
+	})
+	return exporter
+}
@@ -0,0 +1,73 @@
+package telemetry
+
+import (
+	"context"
+	"echo-server/logging"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// otlpTracesPath OTLP/HTTP 트레이스 수신 경로
+const otlpTracesPath = "/v1/traces"
+
+// NewOTLPExporter span 을 OTLP/HTTP(protobuf 인코딩)로 OpenTelemetry Collector 에 보내는 SpanExporter 생성
+// endpoint 는 Collector 주소(예: http://otel-collector:4318)이며 경로가 /v1/traces 로 끝나지 않으면 붙인다.
+func NewOTLPExporter(ctx context.Context, endpoint string, headers map[string]string, timeout time.Duration) (*otlptrace.Exporter, error) {
+	target, err := url.Parse(endpoint)
+	if err != nil || target.Host == "" {
+		return nil, fmt.Errorf("failed to parse otlp endpoint, endpoint=%s", endpoint)
+	}
+	path := strings.TrimSuffix(target.Path, "/")
+	if !strings.HasSuffix(path, otlpTracesPath) {
+		path += otlpTracesPath
+	}
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(target.Host), otlptracehttp.WithURLPath(path)}
+	switch target.Scheme {
+	case "http":
+		options = append(options, otlptracehttp.WithInsecure())
+	case "https":
+	default:
+		return nil, fmt.Errorf("failed to parse otlp endpoint, scheme=%s", target.Scheme)
+	}
+	if len(headers) != 0 {
+		options = append(options, otlptracehttp.WithHeaders(headers))
+	}
+	if timeout > 0 {
+		options = append(options, otlptracehttp.WithTimeout(timeout))
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp exporter, err=%s", err)
+	}
+	return exporter, nil
+}
+
+// NewTracerProvider span 을 processor 로 내보내는 TracerProvider 생성
+// sampleRatio 비율의 트레이스만 기록하며, 요청 헤더에 부모 span 이 있으면 부모의 샘플링 여부를 따른다.
+/* exporter, err := telemetry.NewOTLPExporter(ctx, "http://otel-collector:4318", nil, 10*time.Second)
+   provider := telemetry.NewTracerProvider(sdktrace.NewBatchSpanProcessor(exporter), "echo-server", 0.1)
+   otel.SetTracerProvider(provider)
+   defer provider.Shutdown(ctx)
+*/
+func NewTracerProvider(processor sdktrace.SpanProcessor, serviceName string, sampleRatio float64) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(sdkresource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+}
+
+func init() {
+	// 내보내기 실패 등 OpenTelemetry 내부 에러를 구조화 로그로 기록
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logging.Logger.Warn().Err(err).Msg("opentelemetry error")
+	}))
+}
@@ -0,0 +1,92 @@
+package telemetry
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Transport Kiali 호출마다 client span 을 만들어 traceparent 헤더로 전달하고 호출 수, 지연 시간, 실패를 기록하는 RoundTripper
+// span 은 응답 본문을 닫을 때 끝나므로 스트리밍 응답 전송 시간까지 포함한다.
+func Transport(next http.RoundTripper) http.RoundTripper {
+	return &transport{next: next}
+}
+
+type transport struct {
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	_, cluster := labels(request.Context())
+	if cluster == "" {
+		cluster = request.URL.Host
+	}
+	ctx, span := tracer().Start(request.Context(), "kiali "+request.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPClientAttributesFromHTTPRequest(request)...),
+		trace.WithAttributes(ClusterAttribute.String(cluster)))
+	request = request.Clone(ctx)
+	propagator.Inject(ctx, propagation.HeaderCarrier(request.Header))
+
+	start := time.Now()
+	response, err := t.next.RoundTrip(request)
+	upstreamDuration.WithLabelValues(cluster).Observe(time.Since(start).Seconds())
+	if err != nil {
+		upstreamErrors.WithLabelValues(cluster).Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+		return nil, err
+	}
+	upstreamRequests.WithLabelValues(cluster, request.Method, strconv.Itoa(response.StatusCode)).Inc()
+	span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(response.StatusCode)...)
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(response.StatusCode))
+	// 프로토콜 전환(101) 응답의 본문은 ReverseProxy 가 io.ReadWriteCloser 로 사용하므로 감싸지 않음
+	if response.StatusCode == http.StatusSwitchingProtocols || response.Body == nil {
+		span.End()
+		return response, nil
+	}
+	response.Body = &spanBody{ReadCloser: response.Body, span: span}
+	return response, nil
+}
+
+// spanBody 응답 본문을 닫을 때 span 을 끝내는 ReadCloser
+type spanBody struct {
+	io.ReadCloser
+	span trace.Span
+}
+
+func (b *spanBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.span.End()
+	return err
+}
+
+// AuthenticateSpan Kiali 인증 span 을 시작하고, 끝날 때 인증 결과를 기록하는 함수를 반환
+/* ctx, end := telemetry.AuthenticateSpan(ctx, kialiServerAddress)
+   session, err := authenticate(ctx)
+   end(err)
+*/
+func AuthenticateSpan(ctx context.Context, kialiServerAddress string) (context.Context, func(err error)) {
+	_, cluster := labels(ctx)
+	if cluster == "" {
+		cluster = kialiServerAddress
+	}
+	ctx, span := tracer().Start(ctx, "kiali authenticate", trace.WithAttributes(ClusterAttribute.String(cluster)))
+	return ctx, func(err error) {
+		result := "success"
+		if err != nil {
+			result = "failure"
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		authRefreshes.WithLabelValues(cluster, result).Inc()
+		span.End()
+	}
+}
@@ -0,0 +1,44 @@
+// Package collection 제네릭 기반의 슬라이스 헬퍼(go-practice/common/collection 과 같은 시그니처)
+// echo-server 는 별도 모듈이므로 필요한 함수만 둔다.
+package collection
+
+// Map 슬라이스의 각 원소를 변환한 새 슬라이스를 반환
+func Map[T any, R any](items []T, mapper func(T) R) []R {
+	result := make([]R, len(items))
+	for i, item := range items {
+		result[i] = mapper(item)
+	}
+	return result
+}
+
+// Filter 조건을 만족하는 원소만 담은 새 슬라이스를 반환
+func Filter[T any](items []T, predicate func(T) bool) []T {
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		if predicate(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Find 조건을 만족하는 첫 번째 원소와 존재 여부를 반환
+func Find[T any](items []T, predicate func(T) bool) (T, bool) {
+	for _, item := range items {
+		if predicate(item) {
+			return item, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Contains 값과 같은 원소가 있는지 확인
+func Contains[T comparable](items []T, value T) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,11 @@
 package urlpath
 
-import "strings"
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
 
 // Path is a representation of a sequence of segments.
 //
@@ -11,6 +16,9 @@ type Path struct {
 
 	// Whether additional, trailing segments after Segments are acceptable.
 	Trailing bool
+
+	// Whether constant segments are compared case-insensitively.
+	CaseInsensitive bool
 }
 
 // Segment is a constraint on a single segment in a path.
@@ -23,6 +31,23 @@ type Segment struct {
 
 	// The constant value the segment is expected to take.
 	Const string
+
+	// Whether this parameter segment may be absent from the input.
+	Optional bool
+
+	// The constraint on the parameter value, either a named type (see
+	// constraintTypes) or a regular expression. Empty if unconstrained.
+	Constraint string
+
+	// Whether this segment is a wildcard. A single asterisk matches exactly one
+	// segment of any value, and a double asterisk matches zero or more segments.
+	Wildcard bool
+
+	// Whether this wildcard segment matches zero or more segments ("**").
+	MultiWildcard bool
+
+	// The compiled form of Constraint.
+	pattern *regexp.Regexp
 }
 
 // Match represents the data extracted by matching an input against a Path.
@@ -30,10 +55,18 @@ type Segment struct {
 // To construct instances of Match, see the Match method on Path.
 type Match struct {
 	// The segments in the input corresponding to parameterized segments in Path.
+	// Values are percent-decoded; an encoded slash ("%2F") stays part of the
+	// value instead of separating segments.
 	Params map[string]string
 
+	// The segments in the input corresponding to wildcard segments in Path, in
+	// order. Each "*" contributes one decoded segment, and each "**" contributes
+	// the matched segments joined by slashes, left encoded.
+	Wildcards []string
+
 	// The trailing segments from the input. Note that the leading slash from the
-	// trailing segments is not included, since it's implied.
+	// trailing segments is not included, since it's implied. Trailing segments
+	// are left encoded.
 	//
 	// An exception to this leading slash rule is made if the Path was constructed
 	// as New("*"), in which case Trailing will be identical to the inputted
@@ -41,6 +74,27 @@ type Match struct {
 	Trailing string
 }
 
+// Option configures a Path constructed by New or Parse.
+type Option func(*Path)
+
+// CaseInsensitive makes constant segments match regardless of case.
+func CaseInsensitive() Option {
+	return func(p *Path) {
+		p.CaseInsensitive = true
+	}
+}
+
+// constraintTypes are the named constraints usable as ":param<name>". Any
+// other constraint is treated as a regular expression that must match the
+// whole (decoded) segment.
+var constraintTypes = map[string]string{
+	"int":   `-?[0-9]+`,
+	"uint":  `[0-9]+`,
+	"alpha": `[A-Za-z]+`,
+	"alnum": `[A-Za-z0-9]+`,
+	"uuid":  `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+}
+
 // New constructs a new Path from its human-readable string representation.
 //
 // The syntax for paths looks something like the following:
@@ -80,8 +134,21 @@ type Match struct {
 //	/users/foo/files
 //
 // The asterisk syntax for trailing segments only takes effect on the last
-// segment. If an asterisk appears in any other segment, it carries no special
-// meaning.
+// segment. Elsewhere, "*" is a wildcard matching exactly one segment of any
+// value, and "**" is a wildcard matching zero or more segments:
+//
+//	/namespaces/*/istio/:objectType      matches /namespaces/foo/istio/gateways
+//	/namespaces/**/details               matches /namespaces/details and /namespaces/a/b/details
+//
+// A parameter may be constrained by a named type (int, uint, alpha, alnum,
+// uuid) or a regular expression between angle brackets, and may be marked
+// optional with a trailing question mark:
+//
+//	/workloads/:id<int>
+//	/istio/:objectType<gateways|virtualservices>/:object?
+//
+// An optional segment may be absent from the input, in which case its
+// parameter is not present in the Params of the match.
 //
 // In more formal terms, the string representation of a path is a sequence of
 // segments separated by slashes. Segments starting with colon (":") are treated
@@ -91,7 +158,20 @@ type Match struct {
 // an indication that the path accepts trailing segments, and not included in
 // the Segments of the return value. Instead, Trailing in the return value is
 // marked as true.
-func New(path string) Path {
+//
+// New panics if a constraint is not a valid regular expression; use Parse to
+// handle that case as an error.
+func New(path string, options ...Option) Path {
+	p, err := Parse(path, options...)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Parse is like New, but returns an error instead of panicking when a
+// constraint is not a valid regular expression.
+func Parse(path string, options ...Option) (Path, error) {
 	inSegments := strings.Split(path, "/")
 	trailing := inSegments[len(inSegments)-1] == "*"
 
@@ -103,14 +183,59 @@ func New(path string) Path {
 	}
 
 	for i := 0; i < len(outSegments); i++ {
-		if strings.HasPrefix(inSegments[i], ":") {
-			outSegments[i] = Segment{IsParam: true, Param: inSegments[i][1:]}
-		} else {
-			outSegments[i] = Segment{IsParam: false, Const: inSegments[i]}
+		segment, err := parseSegment(inSegments[i])
+		if err != nil {
+			return Path{}, fmt.Errorf("failed to parse path, path=%s, err=%s", path, err)
+		}
+		outSegments[i] = segment
+	}
+
+	p := Path{Segments: outSegments, Trailing: trailing}
+	for _, option := range options {
+		option(&p)
+	}
+	return p, nil
+}
+
+// parseSegment parses a single segment of a path's string representation.
+func parseSegment(s string) (Segment, error) {
+	switch {
+	case s == "*":
+		return Segment{Wildcard: true}, nil
+	case s == "**":
+		return Segment{Wildcard: true, MultiWildcard: true}, nil
+	case !strings.HasPrefix(s, ":"):
+		return Segment{Const: s}, nil
+	}
+
+	segment := Segment{IsParam: true}
+	name := s[1:]
+	if strings.HasSuffix(name, "?") {
+		segment.Optional = true
+		name = name[:len(name)-1]
+	}
+	if open := strings.IndexByte(name, '<'); open != -1 && strings.HasSuffix(name, ">") {
+		segment.Constraint = name[open+1 : len(name)-1]
+		name = name[:open]
+
+		expression, ok := constraintTypes[segment.Constraint]
+		if !ok {
+			expression = segment.Constraint
 		}
+		pattern, err := regexp.Compile("^(?:" + expression + ")$")
+		if err != nil {
+			return Segment{}, fmt.Errorf("invalid constraint %q for parameter %q: %s", segment.Constraint, name, err)
+		}
+		segment.pattern = pattern
 	}
+	segment.Param = name
+	return segment, nil
+}
 
-	return Path{Segments: outSegments, Trailing: trailing}
+// Allows reports whether a decoded value satisfies the segment's constraint.
+// Segments without a constraint allow any value.
+func (s Segment) Allows(value string) bool {
+	return s.pattern == nil || s.pattern.MatchString(value)
 }
 
 // Match checks if the input string satisfies a Path's constraints, and returns
@@ -120,82 +245,179 @@ func New(path string) Path {
 // path. The first return value is meaningful only if the match was successful.
 //
 // If the match was a success, all parameterized segments in Path have a
-// corresponding entry in the Params of Match. If the path allows for trailing
-// segments in the input, these will be in Trailing.
+// corresponding entry in the Params of Match, except for optional segments
+// absent from the input. If the path allows for trailing segments in the
+// input, these will be in Trailing.
+//
+// The input is expected in its escaped form (such as url.URL.EscapedPath), so
+// that an encoded slash inside a segment is not taken as a separator. Each
+// segment is percent-decoded before it is compared or captured, and an input
+// with an invalid escape does not match.
 func (p *Path) Match(s string) (Match, bool) {
-	params := map[string]string{}
-
-	for segmentIndex, segment := range p.Segments {
-		// s[:i] is the prefix of s which contains the segment that must match
-		// against the path. s[j:] is the suffix of s which the next iteration of
-		// the loop will operate on.
-		//
-		// In "ordinary" circumstances, s[:i] is everything up to the first slash,
-		// and s[j:] is everything after it. But when there are no remaining slashes
-		// in the input, s[:i] is the entire string, and s[j:] is the empty string.
-		i := strings.IndexByte(s, '/')
-		j := i + 1
-		if i == -1 {
-			i = len(s)
-			j = len(s)
-
-			// If we have run out of slashes before the last element of the segments,
-			// then the input does not match the path.
-			//
-			// Implicitly, allowing for trailing input effectively adds an additional
-			// required slash to the input that's not captured by p.Segments. If
-			// trailing input is allowed, it's never ok for an input to have fewer
-			// slashes than the path has segments (an equal number is ok, and
-			// corresponds to a trailing part with no slashes in it).
-			if segmentIndex != len(p.Segments)-1 || p.Trailing {
-				return Match{}, false
+	m := Match{Params: map[string]string{}}
+	if !p.match(p.Segments, strings.Split(s, "/"), &m) {
+		return Match{}, false
+	}
+	return m, true
+}
+
+// MatchURL is like Match, but takes the path of a URL, preferring its RawPath
+// when it is a valid encoding of Path.
+func (p *Path) MatchURL(u *url.URL) (Match, bool) {
+	return p.Match(u.EscapedPath())
+}
+
+// match matches the remaining segments against the remaining input parts,
+// backtracking over optional segments and multi-segment wildcards.
+func (p *Path) match(segments []Segment, parts []string, m *Match) bool {
+	if len(segments) == 0 {
+		if p.Trailing {
+			// Trailing input requires at least one more slash in the input than
+			// the segments account for, except when there are no segments at all.
+			if len(parts) == 0 {
+				return false
 			}
-		} else {
-			// If we have slashes left over and we are not ok with trailing input,
-			// then the input does not match the path.
-			if segmentIndex == len(p.Segments)-1 && !p.Trailing {
-				return Match{}, false
+			m.Trailing = strings.Join(parts, "/")
+			return true
+		}
+		return len(parts) == 0
+	}
+
+	segment := segments[0]
+	if segment.MultiWildcard {
+		// Prefer the shortest run of segments for the wildcard.
+		for n := 0; n <= len(parts); n++ {
+			m.Wildcards = append(m.Wildcards, strings.Join(parts[:n], "/"))
+			if p.match(segments[1:], parts[n:], m) {
+				return true
 			}
+			m.Wildcards = m.Wildcards[:len(m.Wildcards)-1]
 		}
+		return false
+	}
 
-		if segment.IsParam {
-			params[segment.Param] = s[:i]
-		} else {
-			if s[:i] != segment.Const {
-				return Match{}, false
+	if len(parts) > 0 {
+		if value, ok := p.matchSegment(segment, parts[0]); ok {
+			if segment.IsParam {
+				m.Params[segment.Param] = value
+			} else if segment.Wildcard {
+				m.Wildcards = append(m.Wildcards, value)
+			}
+			if p.match(segments[1:], parts[1:], m) {
+				return true
+			}
+			if segment.IsParam {
+				delete(m.Params, segment.Param)
+			} else if segment.Wildcard {
+				m.Wildcards = m.Wildcards[:len(m.Wildcards)-1]
 			}
 		}
+	}
+
+	if segment.Optional {
+		return p.match(segments[1:], parts, m)
+	}
+	return false
+}
 
-		s = s[j:]
+// matchSegment decodes a single input part and checks it against a segment,
+// returning the decoded value.
+func (p *Path) matchSegment(segment Segment, part string) (string, bool) {
+	value, err := url.PathUnescape(part)
+	if err != nil {
+		return "", false
 	}
+	switch {
+	case segment.Wildcard:
+		return value, true
+	case segment.IsParam:
+		return value, segment.Allows(value)
+	case p.CaseInsensitive:
+		return value, strings.EqualFold(value, segment.Const)
+	}
+	return value, value == segment.Const
+}
 
-	return Match{Params: params, Trailing: s}, true
+// Specificity returns a score for ordering paths that may match the same
+// input; a higher score is more specific. Constant segments outweigh
+// constrained parameters, which outweigh plain parameters, which outweigh
+// optional parameters and single-segment wildcards. Multi-segment wildcards
+// and trailing segments add nothing.
+//
+// For example, /namespaces/graph/istio scores higher than
+// /namespaces/:id<int>/istio, which scores higher than
+// /namespaces/:namespace/istio, /namespaces/*/istio and /namespaces/**/istio
+// in that order.
+func (p *Path) Specificity() int {
+	score := 0
+	for _, segment := range p.Segments {
+		switch {
+		case segment.MultiWildcard:
+		case segment.Wildcard, segment.Optional:
+			score += 1
+		case segment.IsParam && segment.Constraint != "":
+			score += 10000
+		case segment.IsParam:
+			score += 100
+		default:
+			score += 1000000
+		}
+	}
+	return score
 }
 
 // Build is the inverse of Match. Given parameter and trailing segment
 // information, Build returns a string which satifies this information.
 //
 // The second parameter indicates whether the inputted match has the parameters
-// the path specifies. If any of the parameters in the path are not found in the
-// provided Match's Params, then false is returned.
+// the path specifies. If any of the required parameters in the path are not
+// found in the provided Match's Params, if a parameter does not satisfy its
+// constraint, or if the Match's Wildcards do not cover the path's wildcard
+// segments, then false is returned. Optional parameters absent from Params
+// are omitted along with their slash.
+//
+// Parameter and single-segment wildcard values are percent-encoded, so that a
+// value containing a slash round-trips through Match. Multi-segment wildcard
+// values and Trailing are written as they are.
 func (p *Path) Build(m Match) (string, bool) {
-	var s strings.Builder
-	for i, segment := range p.Segments {
-		if segment.IsParam {
-			if param, ok := m.Params[segment.Param]; ok {
-				s.WriteString(param)
-			} else {
+	parts := make([]string, 0, len(p.Segments))
+	wildcards := m.Wildcards
+	for _, segment := range p.Segments {
+		switch {
+		case segment.Wildcard:
+			if len(wildcards) == 0 {
 				return "", false
 			}
-		} else {
-			s.WriteString(segment.Const)
-		}
-
-		if i != len(p.Segments)-1 {
-			s.WriteRune('/')
+			value := wildcards[0]
+			wildcards = wildcards[1:]
+			if segment.MultiWildcard {
+				// An empty multi-segment wildcard contributes no segment at all.
+				if value != "" {
+					parts = append(parts, value)
+				}
+				continue
+			}
+			parts = append(parts, url.PathEscape(value))
+		case segment.IsParam:
+			param, ok := m.Params[segment.Param]
+			if !ok {
+				if segment.Optional {
+					continue
+				}
+				return "", false
+			}
+			if !segment.Allows(param) {
+				return "", false
+			}
+			parts = append(parts, url.PathEscape(param))
+		default:
+			parts = append(parts, segment.Const)
 		}
 	}
 
+	var s strings.Builder
+	s.WriteString(strings.Join(parts, "/"))
+
 	// The trailing segment of a match does not include a leading slash. We
 	// therefore need to add it here.
 	//
@@ -0,0 +1,127 @@
+package urlpath
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		input   string
+		ok      bool
+		params  map[string]string
+		wild    []string
+		trail   string
+	}{
+		// 기존 동작
+		{"/shelves/:shelf/books/:book", "/shelves/foo/books/bar", true, map[string]string{"shelf": "foo", "book": "bar"}, nil, ""},
+		{"/shelves/:shelf/books/:book", "/shelves//books/", true, map[string]string{"shelf": "", "book": ""}, nil, ""},
+		{"/shelves/:shelf/books/:book", "/shelves/foo/books", false, nil, nil, ""},
+		{"/shelves/:shelf/books/:book", "/shelves/foo/books/bar/", false, nil, nil, ""},
+		{"/shelves/:shelf/books/:book", "/SHELVES/foo/books/bar", false, nil, nil, ""},
+		{"/users/:user/files/*", "/users/foo/files/foo/bar/baz.txt", true, map[string]string{"user": "foo"}, nil, "foo/bar/baz.txt"},
+		{"/users/:user/files/*", "/users/foo/files", false, nil, nil, ""},
+		{"*", "/a/b", true, map[string]string{}, nil, "/a/b"},
+		// 퍼센트 디코딩(인코딩된 슬래시는 세그먼트를 나누지 않음)
+		{"/namespaces/:namespace/istio/:objectType/:object", "/namespaces/hello-namespace/istio/asfda%2Faf/dakfjkaf", true,
+			map[string]string{"namespace": "hello-namespace", "objectType": "asfda/af", "object": "dakfjkaf"}, nil, ""},
+		{"/namespaces/:namespace", "/namespaces/%zz", false, nil, nil, ""},
+		{"/files/*", "/files/a%2Fb/c", true, map[string]string{}, nil, "a%2Fb/c"},
+		// 선택 세그먼트
+		{"/istio/:objectType/:object?", "/istio/gateways", true, map[string]string{"objectType": "gateways"}, nil, ""},
+		{"/istio/:objectType/:object?", "/istio/gateways/main", true, map[string]string{"objectType": "gateways", "object": "main"}, nil, ""},
+		{"/istio/:objectType?/details", "/istio/details", true, map[string]string{}, nil, ""},
+		{"/istio/:objectType/:object?", "/istio/gateways/main/extra", false, nil, nil, ""},
+		// 와일드카드
+		{"/namespaces/*/istio", "/namespaces/foo/istio", true, map[string]string{}, []string{"foo"}, ""},
+		{"/namespaces/*/istio", "/namespaces/istio", false, nil, nil, ""},
+		{"/namespaces/**/details", "/namespaces/details", true, map[string]string{}, []string{""}, ""},
+		{"/namespaces/**/details/:name", "/namespaces/a/b/details/x", true, map[string]string{"name": "x"}, []string{"a/b"}, ""},
+		// 제약 조건
+		{"/workloads/:id<int>", "/workloads/42", true, map[string]string{"id": "42"}, nil, ""},
+		{"/workloads/:id<int>", "/workloads/abc", false, nil, nil, ""},
+		{"/istio/:objectType<gateways|virtualservices>/:object?", "/istio/virtualservices", true, map[string]string{"objectType": "virtualservices"}, nil, ""},
+		{"/istio/:objectType<gateways|virtualservices>", "/istio/sidecars", false, nil, nil, ""},
+		{"/apps/:uid<uuid>", "/apps/123e4567-e89b-12d3-a456-426614174000", true, map[string]string{"uid": "123e4567-e89b-12d3-a456-426614174000"}, nil, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.input, func(t *testing.T) {
+			path := New(test.pattern)
+			match, ok := path.Match(test.input)
+			if ok != test.ok {
+				t.Fatalf("expected match %v, got %v", test.ok, ok)
+			}
+			if !ok {
+				return
+			}
+			if !reflect.DeepEqual(match.Params, test.params) || !reflect.DeepEqual(match.Wildcards, test.wild) || match.Trailing != test.trail {
+				t.Errorf("unexpected match %+v", match)
+			}
+
+			// Build 는 Match 의 역함수
+			built, ok := path.Build(match)
+			if !ok {
+				t.Fatalf("failed to build %+v", match)
+			}
+			rematch, ok := path.Match(built)
+			if !ok || !reflect.DeepEqual(rematch, match) {
+				t.Errorf("built %q does not round-trip, got %+v", built, rematch)
+			}
+		})
+	}
+}
+
+func TestMatchCaseInsensitive(t *testing.T) {
+	path := New("/api/Namespaces/:namespace", CaseInsensitive())
+	match, ok := path.Match("/API/namespaces/Bookinfo")
+	if !ok || match.Params["namespace"] != "Bookinfo" {
+		t.Errorf("unexpected match %+v %v", match, ok)
+	}
+}
+
+func TestMatchURL(t *testing.T) {
+	u, _ := url.Parse("http://localhost/namespaces/a%2Fb/istio")
+	path := New("/namespaces/:namespace/istio")
+	match, ok := path.MatchURL(u)
+	if !ok || match.Params["namespace"] != "a/b" {
+		t.Errorf("unexpected match %+v %v", match, ok)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	path := New("/istio/:objectType<gateways|virtualservices>/:object?")
+	if built, ok := path.Build(Match{Params: map[string]string{"objectType": "gateways", "object": "a/b"}}); !ok || built != "/istio/gateways/a%2Fb" {
+		t.Errorf("unexpected build %q %v", built, ok)
+	}
+	if built, ok := path.Build(Match{Params: map[string]string{"objectType": "gateways"}}); !ok || built != "/istio/gateways" {
+		t.Errorf("unexpected build %q %v", built, ok)
+	}
+	if _, ok := path.Build(Match{Params: map[string]string{"objectType": "sidecars"}}); ok {
+		t.Error("expected constraint violation")
+	}
+	if _, ok := path.Build(Match{Params: map[string]string{}}); ok {
+		t.Error("expected missing parameter")
+	}
+}
+
+func TestParse(t *testing.T) {
+	if _, err := Parse("/workloads/:id<[0-9>"); err == nil {
+		t.Error("expected invalid constraint error")
+	}
+	path, err := Parse("/a/:b<int>?")
+	if err != nil || !path.Segments[2].Optional || path.Segments[2].Param != "b" || path.Segments[2].Constraint != "int" {
+		t.Errorf("unexpected segment %+v %v", path.Segments, err)
+	}
+}
+
+func TestSpecificity(t *testing.T) {
+	ordered := []string{"/namespaces/graph/istio", "/namespaces/:id<int>/istio", "/namespaces/:namespace/istio", "/namespaces/*/istio", "/namespaces/**/istio"}
+	for i := 1; i < len(ordered); i++ {
+		more, less := New(ordered[i-1]), New(ordered[i])
+		if more.Specificity() <= less.Specificity() {
+			t.Errorf("expected %s (%d) more specific than %s (%d)", ordered[i-1], more.Specificity(), ordered[i], less.Specificity())
+		}
+	}
+}
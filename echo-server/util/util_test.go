@@ -2,13 +2,12 @@ package util
 
 import (
 	"echo-server/proxy"
+	"echo-server/util/collection"
 	urlpath "echo-server/util/url"
 	"fmt"
 	"github.com/imroc/req/v3"
 	"github.com/kiali/kiali/models"
-	"github.com/thoas/go-funk"
 	"log"
-	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -46,9 +45,8 @@ func TestUrlPathMatch(t *testing.T) {
 		t.Error("[ERROR] url path dose not match")
 	}
 
-	namespace, _ := url.QueryUnescape(match.Params["namespace"])
-	objectType, _ := url.QueryUnescape(match.Params["objectType"])
-	t.Log(namespace, objectType)
+	// 파라미터는 디코딩된 값(asfda/af)으로 매칭됨
+	t.Log(match.Params["namespace"], match.Params["objectType"])
 }
 
 func TestTrimStringPrefix(t *testing.T) {
@@ -73,7 +71,7 @@ func TestFindProxy(t *testing.T) {
 	t.Log("[REQUEST_URL]", requestURL)
 
 	// 정의된 프록시 목록에서 url 패턴과 메소드가 같은 것 찾기
-	find := funk.Find(*proxy.Proxies, func(proxy proxy.Proxy) bool {
+	find, ok := collection.Find(*proxy.Proxies, func(proxy proxy.Proxy) bool {
 		pattern := urlpath.New(proxy.Pattern)
 		_, patternOK := pattern.Match(requestURL)
 		t.Log("패턴 일치 여부 확인: ", patternOK)
@@ -93,8 +91,8 @@ func TestFindProxy(t *testing.T) {
 	var clientResponse *req.Response
 	clientRequest := client.R()
 
-	if find != nil {
-		switch find.(proxy.Proxy).Type.(type) {
+	if ok {
+		switch find.Type.(type) {
 		case models.IstioConfigDetails:
 			var istioConfigDetails models.IstioConfigDetails
 			clientRequest.SetSuccessResult(&istioConfigDetails)
@@ -32,4 +32,8 @@ func main() {
 		fmt.Println(common.Get(ele, "metric.namespace"), common.Get(ele, "value"))
 	}
 
+	// common.Get 의 경로 문법(# 와일드카드, #(조건) 필터)
+	fmt.Println(common.Get(b, "data.result.#.metric.namespace"))
+	fmt.Println(common.GetSlice(b, "data.result.#(metric.namespace%\"openshift-*\")#.value.1"))
+
 }
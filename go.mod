@@ -16,6 +16,8 @@ require (
 	github.com/kiali/kiali v1.69.0
 	github.com/pkg/errors v0.9.1
 	github.com/thoas/go-funk v0.9.3
+	golang.org/x/net v0.11.0
+	k8s.io/api v0.24.2
 	k8s.io/apimachinery v0.24.2
 )
 
@@ -83,7 +85,6 @@ require (
 	golang.org/x/crypto v0.10.0 // indirect
 	golang.org/x/exp v0.0.0-20230522175609-2e198f4a06a1 // indirect
 	golang.org/x/mod v0.10.0 // indirect
-	golang.org/x/net v0.11.0 // indirect
 	golang.org/x/oauth2 v0.0.0-20211104180415-d3ed0bb246c8 // indirect
 	golang.org/x/sync v0.2.0 // indirect
 	golang.org/x/sys v0.9.0 // indirect
@@ -101,7 +102,6 @@ require (
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	istio.io/api v0.0.0-20221005164339-97dc20dc0ff3 // indirect
 	istio.io/client-go v1.15.2 // indirect
-	k8s.io/api v0.24.2 // indirect
 	k8s.io/klog/v2 v2.60.1 // indirect
 	k8s.io/kube-openapi v0.0.0-20220328201542-3ee0da9b0b42 // indirect
 	k8s.io/utils v0.0.0-20220210201930-3a6ce19ff2f9 // indirect
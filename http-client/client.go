@@ -1,30 +1,65 @@
 package main
 
 import (
-	"crypto/tls"
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
-	"go-practice/common"
 	"go-practice/http-client/config"
 	"go-practice/http-client/kubernetes"
 	"go-practice/http-client/prometheus"
-	"io/ioutil"
-	"net/http"
-	"net/url"
+	"go-practice/http-client/report"
+	"go-practice/http-client/server"
+	"os"
+	"os/signal"
 	_ "strconv"
+	"syscall"
 	_ "time"
 )
 
-const (
-	queryAPIEndpoint      = "/api/v1/query"
-	queryRangeAPIEndpoint = "/api/v1/query_range"
-)
+// listenAddress 메트릭 조회 API 서버 주소(지정하지 않으면 bodyParams 로 한 번 조회)
+var listenAddress = flag.String("listen", "", "metric API server address (e.g. :8080)")
+
+// reportFormat 용량 예측 보고서 형식(지정하면 전체 네임스페이스와 노드의 보고서를 출력)
+var reportFormat = flag.String("report", "", "print capacity report in the format (json, markdown, html)")
+
+// reportLookback 용량 예측 보고서의 추세 조회 기간
+var reportLookback = flag.String("lookback", "7d", "capacity report lookback window (e.g. 7d, 12h)")
 
 func init() {
 	config.Init()
+
+	// 메트릭 원천 등록
+	prometheus.MetricSources[prometheus.PrometheusSourceType] = &prometheus.PrometheusSource{
+		RequestURL: config.ClientConfig.PrometheusRequestURL,
+		Token:      config.ClientConfig.PrometheusToken,
+		//Version:    "2.1.5-rc1",
+		Version: "2.27.0",
+	}
+	prometheus.MetricSources[prometheus.KubernetesSourceType] = &prometheus.KubernetesSource{
+		Client: kubernetes.DynamicClient,
+	}
 }
 
 func main() {
+	flag.Parse()
+	if *listenAddress != "" {
+		fmt.Println("[   SERVER   ]", *listenAddress)
+		// 종료 시그널을 받으면 구독 연결을 끊고 서버를 정상 종료
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := server.NewServer(kubernetes.ClientSettings).ListenAndServe(ctx, *listenAddress); err != nil {
+			fmt.Printf("failed to run metric API server, err=%s\n", err)
+		}
+		return
+	}
+	if *reportFormat != "" {
+		if err := printReport(*reportFormat, *reportLookback); err != nil {
+			fmt.Printf("failed to print capacity report, err=%s\n", err)
+		}
+		return
+	}
+
 	//now := time.Now()
 	//now := time.Date(2022, 6, 27, 10, 15, 30, 0, time.Local)
 
@@ -50,11 +85,17 @@ func main() {
 		//"metricKeys": []string{"ha_proxy_traffic_in"},
 		//"metricKeys": []string{"ha_proxy_traffic_out"},
 		//"metricKeys": []string{"ha_proxy_connection_rate"},
+		//"metricKeys": []string{"limit_range"},
 		//"metricKeys": []string{"node_cpu"},
+		//"metricKeys": []string{"node_cpu_capacity"},
+		//"metricKeys": []string{"node_cpu_request"},
 		//"metricKeys": []string{"node_cpu_load_average"},
 		//"metricKeys": []string{"node_disk_io"},
 		//"metricKeys": []string{"node_file_system"},
+		//"metricKeys": []string{"node_file_system_size"},
 		//"metricKeys": []string{"node_memory"},
+		//"metricKeys": []string{"node_memory_request"},
+		//"metricKeys": []string{"node_memory_size"},
 		//"metricKeys": []string{"node_network_in"},
 		//"metricKeys": []string{"node_network_io"},
 		//"metricKeys": []string{"node_network_out"},
@@ -103,6 +144,7 @@ func main() {
 		//"metricKeys": []string{"quota_request_pod_memory"},
 		//"metricKeys": []string{"quota_request_storage_hard"},
 		//"metricKeys": []string{"quota_request_storage_used"},
+		//"metricKeys": []string{"resource_quota"},
 		//"metricKeys": []string{"summary_node_info"},
 		//"metricKeys": []string{"summary_container_cpu_info"},
 		//"metricKeys": []string{"summary_container_memory_info"},
@@ -133,186 +175,55 @@ func main() {
 		//"start": "1658970600",
 		//"end": "1658974200",
 		//"step": "120",
+		//"stats": true,
+		//"anomalyThreshold": 3,
+		//"unitPolicy": "max", // 공통 단위 선택 정책(max, median, digits)
 		//"node": "master1.ocp4.inno.com|master2.ocp4.inno.com|master3.ocp4.inno.com|worker1.ocp4.inno.com|worker2.ocp4.inno.com|worker3.ocp4.inno.com",
 		//"instance": "master1.ocp4.inno.com|master2.ocp4.inno.com|master3.ocp4.inno.com|worker1.ocp4.inno.com|worker2.ocp4.inno.com|worker3.ocp4.inno.com",
 		//"namespace": ".*",
 	}
 
-	var result = make(map[string]interface{})
-
 	// 반환값
 	for _, metricKey := range bodyParams["metricKeys"].([]string) {
-		var final []byte
-
-		// 클라이언트에서 요청한 key 에 따른 쿼리 생성
-		metricDefinition, isMetric := prometheus.MetricDefinitions[prometheus.MetricKey(metricKey)]
-
-		// 정의된 메트릭 여부 확인
-		if isMetric {
-			innerMetricKeys := metricDefinition.MetricKeys
-			if innerMetricKeys != nil { // 다른 메트릭의 값을 활용하는 메트릭 처리
-				innerResult := make(map[string]interface{})
-				innerQueries := make(map[string]interface{})
-				for _, innerMetricKey := range innerMetricKeys {
-					innerResult = common.MergeJSONMaps(innerResult, getQueryResult(innerMetricKey, bodyParams))
-					innerQueries = common.MergeJSONMaps(innerQueries, map[string]interface{}{
-						string(innerMetricKey): innerResult[string(innerMetricKey)].(prometheus.MetricResponse).Queries,
-					})
-				}
-				metricResponse := prometheus.MakeMetricResponse(prometheus.MetricKey(metricKey), nil, "", nil, false, innerResult)
-				result = common.MergeJSONMaps(result, map[string]interface{}{metricKey: metricResponse.Values})
-				//result["Queries"] = innerQueries
-			} else {
-				queryResult := getQueryResult(prometheus.MetricKey(metricKey), bodyParams)
-				result = common.MergeJSONMaps(result, queryResult)
-			}
+		// 클라이언트에서 요청한 key 에 따른 메트릭 정의의 원천(프로메테우스, Kubernetes API)에서 조회
+		result, err := prometheus.GetMetricResult(prometheus.MetricKey(metricKey), bodyParams)
+		if err != nil {
+			fmt.Printf("failed to get metric result, err=%s\n", err)
+			continue
 		}
 
 		// 최종 결과 확인
-		var err error
-		if metricKey == "limit_range" {
-			final, err = kubernetes.GetLimitRange()
-			if err != nil {
-				fmt.Printf("failed to get limit range by Kubernetes API, err=%s\n", err)
-			}
-		} else if metricKey == "number_of_pipeline" {
-			//final, err = kubernetes.GetPipelines()
-			if err != nil {
-				fmt.Printf("failed to get pipeline by Kubernetes API, err=%s\n", err)
-			}
-		} else {
-			final, _ = json.Marshal(result)
-		}
+		final, _ := json.Marshal(result)
 		fmt.Println("[   FINAL   ]", string(final))
 	}
 }
 
-func getQueryResult(metricKey prometheus.MetricKey, bodyParams map[string]interface{}) map[string]interface{} {
-	var result = make(map[string]interface{})
-
-	// 클라이언트 생성(TLS insecure 옵션, 인증 정보, 헤더 설정)
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
-	}
-	// 클라이언트 연결 종료 함수 등록
-	defer client.CloseIdleConnections()
-
-	metricDefinition := prometheus.MetricDefinitions[metricKey]
-	label := metricDefinition.Label
-	subLabels := metricDefinition.SubLabels
-	if subLabels == nil {
-		subLabels = []string{label}
-	}
-	//var clusterPrometheusVersion = "2.1.5-rc1"
-	var clusterPrometheusVersion = "2.27.0"
-
-	queryInfos := metricDefinition.QueryInfos
-	definedVersions := make([]string, 0, len(queryInfos))
-	for prometheusVersion := range queryInfos {
-		definedVersions = append(definedVersions, string(prometheusVersion))
-	}
-
-	var targetVersion prometheus.PrometheusVersion
-	clusterPrometheusVersion = prometheus.ParseVersion(clusterPrometheusVersion)
-
-	index := common.IndexOf(definedVersions, clusterPrometheusVersion)
-
-	if index != -1 { // 동일한 버전이 있는 경우
-		targetVersion = prometheus.PrometheusVersion(definedVersions[index])
-	} else { // 동일한 버전이 없는 경우
-		targetVersion = prometheus.PrometheusVersion(prometheus.GetTargetPrometheusVersion(definedVersions, clusterPrometheusVersion))
+// printReport 전체 네임스페이스와 노드의 용량 예측 보고서를 형식에 맞게 출력
+func printReport(format string, lookbackValue string) error {
+	lookback, err := report.ParseLookback(lookbackValue)
+	if err != nil {
+		return err
 	}
-
-	referenceVersion := queryInfos[targetVersion].ReferenceVersion
-	if referenceVersion != "" {
-		targetVersion = referenceVersion
+	ctx := context.Background()
+	namespaces, err := kubernetes.NamespaceNames(ctx, kubernetes.ClientSettings)
+	if err != nil {
+		return err
 	}
-	queryTemplates := queryInfos[targetVersion].QueryTemplates
-	queryTemplateParsers := queryInfos[targetVersion].QueryTemplateParserGenerators
-	unitTypeKeys := metricDefinition.UnitTypeKeys
-	primaryUnit := metricDefinition.PrimaryUnit
-
-	queries := make([]string, len(queryTemplates))
-	rangeParams := make([]string, len(queryTemplates))
-
-	for i, queryTemplate := range queryTemplates {
-		queryTemplateParser := queryTemplateParsers[i]
-		if queryTemplateParser != nil {
-			queries[i], rangeParams[i] = queryTemplateParser(queryTemplate, bodyParams)
-		} else {
-			queries[i] = queryTemplate
-		}
+	nodes, err := kubernetes.NodeNames(ctx, kubernetes.ClientSettings)
+	if err != nil {
+		return err
 	}
 
-	// 프로메테우스 모니터링 API 호출
-	responses := make([]interface{}, len(queries))
-
-	// 조회된 데이터 중 최대값을 통한 단위 저장을 위함
-	var maxValue float64
-	var maxUnit string
-	var isRange bool
-	for queryIdx, query := range queries {
-		// vector 쿼리와 range 쿼리에 따른 requestURL
-		var escapedQuery = url.QueryEscape(query)
-		var requestURL = config.ClientConfig.PrometheusRequestURL + queryAPIEndpoint + "?query=" + escapedQuery
-		isRange = rangeParams[queryIdx] != ""
-		if isRange {
-			requestURL = config.ClientConfig.PrometheusRequestURL + queryRangeAPIEndpoint + "?query=" + escapedQuery + rangeParams[queryIdx]
-		}
-		fmt.Println("[   QUERY    ]", query, requestURL)
-		request, err := http.NewRequest("GET", requestURL, nil)
-		if err != nil {
-			fmt.Printf("failed to create http request, err=%s", err)
-		}
-		request.Header.Add("Content-Type", "application/json; charset=UTF-8")
-		request.Header.Add("Access-Control-Allow-Origin", "*")
-		request.Header.Add("Access-Control-Allow-Methods", "*")
-		request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", config.ClientConfig.PrometheusToken))
-
-		// 응답 요청
-		response, err := client.Do(request)
-		if err != nil {
-			fmt.Printf(
-				"failed to call http request, err=%s\n",
-				err,
-			)
-		}
-
-		responseBytes, err := ioutil.ReadAll(response.Body)
-		if err != nil {
-			fmt.Println("failed to read response body")
-		}
-		fmt.Println("[  RESPONSE  ]", string(responseBytes))
-
-		// Primary 단위를 기준으로 컨버팅하는 값인지 확인
-		isPrimaryUnit := common.Exists(common.UnitTypes[unitTypeKeys[queryIdx]].Units, primaryUnit)
-
-		// 응답값 파싱
-		var tempMaxValue float64
-		responses[queryIdx], tempMaxValue = prometheus.ParseQueryResult(metricKey, isPrimaryUnit, responseBytes, isRange)
-		fmt.Println("[   PARSED    ]", responses[queryIdx], tempMaxValue)
-		if tempMaxValue > maxValue {
-			maxValue = tempMaxValue
-			// 최대값 단위 찾기
-			if isPrimaryUnit && unitTypeKeys[queryIdx] != "" {
-				maxUnit = common.FindMaxUnitByValues(unitTypeKeys[queryIdx], maxValue)
-			}
-		}
+	capacityReport := report.Generate(ctx, report.Options{Lookback: lookback, Namespaces: namespaces, Nodes: nodes})
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(capacityReport)
+	case "markdown":
+		return capacityReport.WriteMarkdown(os.Stdout)
+	case "html":
+		return capacityReport.WriteHTML(os.Stdout)
 	}
-
-	metricResponse := prometheus.MakeMetricResponse(metricKey, unitTypeKeys, maxUnit, subLabels, isRange, responses...)
-	fmt.Println("[   RESULT   ]", metricResponse)
-
-	metricResponse.Label = metricDefinition.Label
-	if maxUnit == "" {
-		metricResponse.Unit = metricDefinition.PrimaryUnit
-	} else {
-		metricResponse.Unit = maxUnit
-	}
-	metricResponse.Queries = queries
-	result[string(metricKey)] = metricResponse
-
-	return result
+	return fmt.Errorf("invalid report format, format=%s", format)
 }
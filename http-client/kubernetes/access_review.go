@@ -0,0 +1,77 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ErrUnauthenticated 토큰이 유효하지 않아 사용자를 확인할 수 없는 경우의 에러
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// ResolveUser 베어러 토큰을 TokenReview 로 검증하여 토큰 사용자 정보를 반환한다
+func ResolveUser(ctx context.Context, client kubernetes.Interface, token string) (authenticationv1.UserInfo, error) {
+	if token == "" {
+		return authenticationv1.UserInfo{}, ErrUnauthenticated
+	}
+	review, err := client.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, v1.CreateOptions{})
+	if err != nil {
+		return authenticationv1.UserInfo{}, fmt.Errorf("failed to review token, err=%s", err)
+	}
+	if !review.Status.Authenticated {
+		return authenticationv1.UserInfo{}, ErrUnauthenticated
+	}
+	return review.Status.User, nil
+}
+
+// CanGetPods 사용자가 네임스페이스의 파드를 조회(get pods)할 수 있는지 SubjectAccessReview 로 확인한다
+// namespace 가 빈 문자열인 경우 클러스터 전체에 대한 권한을 확인한다.
+func CanGetPods(ctx context.Context, client kubernetes.Interface, user authenticationv1.UserInfo, namespace string) (bool, error) {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	review, err := client.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Resource:  "pods",
+			},
+		},
+	}, v1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to review subject access, err=%s", err)
+	}
+	return review.Status.Allowed, nil
+}
+
+// AllowedNamespaces 전체 네임스페이스 중 사용자가 파드를 조회할 수 있는 네임스페이스 목록을 반환한다
+func AllowedNamespaces(ctx context.Context, client kubernetes.Interface, user authenticationv1.UserInfo) ([]string, error) {
+	namespaces, err := NamespaceNames(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make([]string, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		ok, err := CanGetPods(ctx, client, user, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			allowed = append(allowed, namespace)
+		}
+	}
+	return allowed, nil
+}
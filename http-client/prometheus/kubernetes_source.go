@@ -0,0 +1,114 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"go-practice/common"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// KubernetesSource Kubernetes API(dynamic client)를 통해 메트릭을 조회하는 메트릭 원천
+type KubernetesSource struct {
+	Client dynamic.Interface
+}
+
+// Query 메트릭 정의의 Kubernetes 쿼리에 따라 리소스를 조회하고 프로메테우스 메트릭과 동일한 형태의 메트릭 응답을 반환
+func (s *KubernetesSource) Query(ctx context.Context, metricKey MetricKey, bodyParams map[string]interface{}) (MetricResponse, error) {
+	metricDefinition := MetricDefinitions[metricKey]
+	query := metricDefinition.KubernetesQuery
+	if query == nil {
+		return MetricResponse{}, fmt.Errorf("kubernetes query is not defined, metricKey=%s", metricKey)
+	}
+
+	list, err := s.Client.Resource(query.Resource).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return MetricResponse{}, fmt.Errorf("failed to list %s by Kubernetes API, err=%s", query.Resource.Resource, err)
+	}
+
+	items, err := filterKubernetesItems(list.Items, query.ParamFields, bodyParams)
+	if err != nil {
+		return MetricResponse{}, err
+	}
+
+	var metricResponse MetricResponse
+	switch query.Type {
+	case CountQuery:
+		subLabels := metricDefinition.SubLabels
+		if subLabels == nil {
+			subLabels = []string{metricDefinition.Label}
+		}
+		isRange := isRangeQuery(bodyParams)
+		var resultSet interface{} = strconv.Itoa(len(items))
+		if isRange {
+			// Kubernetes API 는 이력을 제공하지 않으므로 현재 값을 end 시점의 단일 샘플로 반환
+			resultSet = []interface{}{
+				map[string]interface{}{"timestamp": rangeEndTimestamp(bodyParams), "value": resultSet},
+			}
+		}
+		metricResponse = MakeMetricResponse(metricKey, metricDefinition.UnitTypeKeys, "", subLabels, isRange, resultSet)
+	case ObjectQuery:
+		values := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			values = append(values, map[string]interface{}{
+				"namespace": item.GetNamespace(),
+				"name":      item.GetName(),
+				query.Field: common.Get(item.Object, query.Field),
+			})
+		}
+		metricResponse = MetricResponse{Values: values}
+	default:
+		return MetricResponse{}, fmt.Errorf("unsupported kubernetes query type, type=%s", query.Type)
+	}
+
+	metricResponse.Label = metricDefinition.Label
+	metricResponse.Unit = metricDefinition.PrimaryUnit
+	return metricResponse, nil
+}
+
+// filterKubernetesItems 요청 파라미터를 프로메테우스 정규식 매칭(=~)과 동일하게 리소스 필드에 적용하여 조건에 맞는 리소스만 반환하는 함수
+func filterKubernetesItems(items []unstructured.Unstructured, paramFields map[string]string,
+	bodyParams map[string]interface{}) ([]unstructured.Unstructured, error) {
+	matchers := make(map[string]*regexp.Regexp, len(paramFields))
+	for paramKey, field := range paramFields {
+		param, ok := bodyParams[paramKey].(string)
+		if !ok {
+			continue
+		}
+		matcher, err := regexp.Compile("^(?:" + param + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s parameter, err=%s", paramKey, err)
+		}
+		matchers[field] = matcher
+	}
+
+	filtered := make([]unstructured.Unstructured, 0, len(items))
+	for _, item := range items {
+		matched := true
+		for field, matcher := range matchers {
+			value := common.GetString(item.Object, field)
+			if !matcher.MatchString(value) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
+
+// rangeEndTimestamp 범위 조회 파라미터의 end 값을 프로메테우스 응답과 동일한 초 단위 timestamp 로 반환하는 함수(없으면 현재 시간)
+func rangeEndTimestamp(bodyParams map[string]interface{}) float64 {
+	if end, err := strconv.ParseFloat(fmt.Sprintf("%v", bodyParams["end"]), 64); err == nil {
+		return end
+	}
+	return float64(time.Now().Unix())
+}
@@ -0,0 +1,128 @@
+package prometheus
+
+import (
+	"context"
+	"testing"
+
+	"go-practice/common"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newUnstructured(apiVersion, kind, namespace, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	object := map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"namespace": namespace,
+			"name":      name,
+		},
+	}
+	for key, value := range fields {
+		object[key] = value
+	}
+	return &unstructured.Unstructured{Object: object}
+}
+
+func newFakeKubernetesSource() *KubernetesSource {
+	listKinds := map[schema.GroupVersionResource]string{
+		{Group: "tekton.dev", Version: "v1beta1", Resource: "pipelines"}: "PipelineList",
+		{Version: "v1", Resource: "limitranges"}:                         "LimitRangeList",
+		{Version: "v1", Resource: "resourcequotas"}:                      "ResourceQuotaList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		newUnstructured("tekton.dev/v1beta1", "Pipeline", "team-a", "build", nil),
+		newUnstructured("tekton.dev/v1beta1", "Pipeline", "team-a", "deploy", nil),
+		newUnstructured("tekton.dev/v1beta1", "Pipeline", "team-b", "build", nil),
+		newUnstructured("v1", "LimitRange", "team-a", "limits", map[string]interface{}{
+			"spec": map[string]interface{}{"limits": []interface{}{map[string]interface{}{"type": "Container"}}},
+		}),
+	)
+	return &KubernetesSource{Client: client}
+}
+
+func TestKubernetesSourceCount(t *testing.T) {
+	source := newFakeKubernetesSource()
+
+	tests := []struct {
+		namespace string
+		usage     string
+	}{
+		{".*", "3"},
+		{"team-a", "2"},
+		{"team-a|team-b", "3"},
+		{"team", "0"},
+	}
+	for _, test := range tests {
+		response, err := source.Query(context.Background(), NumberOfPipeline, map[string]interface{}{"namespace": test.namespace})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response.Usage != test.usage {
+			t.Errorf("namespace=%s: expected usage %s, got %s", test.namespace, test.usage, response.Usage)
+		}
+		if response.Label != "PIPELINE" {
+			t.Errorf("expected label PIPELINE, got %s", response.Label)
+		}
+	}
+}
+
+func TestKubernetesSourceCountRange(t *testing.T) {
+	source := newFakeKubernetesSource()
+
+	response, err := source.Query(context.Background(), NumberOfPipeline, map[string]interface{}{
+		"namespace": "team-b", "start": "1658970600", "end": "1658974200", "step": "120",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, ok := response.Values.([]interface{})
+	if !ok || len(values) != 1 {
+		t.Fatalf("expected a single sample, got %v", response.Values)
+	}
+	sample := values[0].(map[string]interface{})
+	if sample["timestamp"] != float64(1658974200) || sample["PIPELINE"] != common.NewNumber(1) {
+		t.Errorf("unexpected sample %v", sample)
+	}
+}
+
+func TestKubernetesSourceObject(t *testing.T) {
+	source := newFakeKubernetesSource()
+
+	response, err := source.Query(context.Background(), LimitRange, map[string]interface{}{"namespace": "team-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := response.Values.([]interface{})
+	if len(values) != 1 {
+		t.Fatalf("expected 1 limit range, got %d", len(values))
+	}
+	value := values[0].(map[string]interface{})
+	if value["name"] != "limits" || value["spec"] == nil {
+		t.Errorf("unexpected limit range %v", value)
+	}
+
+	if _, err = source.Query(context.Background(), LimitRange, map[string]interface{}{"namespace": "("}); err == nil {
+		t.Error("expected error for invalid namespace pattern")
+	}
+}
+
+func TestGetMetricResultUnregisteredSource(t *testing.T) {
+	delete(MetricSources, KubernetesSourceType)
+	if _, err := GetMetricResult(NumberOfPipeline, map[string]interface{}{}); err == nil {
+		t.Error("expected error for unregistered metric source")
+	}
+
+	MetricSources[KubernetesSourceType] = newFakeKubernetesSource()
+	defer delete(MetricSources, KubernetesSourceType)
+	result, err := GetMetricResult(NumberOfPipeline, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result[string(NumberOfPipeline)].(MetricResponse).Usage != "3" {
+		t.Errorf("unexpected result %v", result)
+	}
+}
@@ -1,6 +1,10 @@
 package prometheus
 
-import "go-practice/common"
+import (
+	"go-practice/common"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
 
 // QueryTemplateParserGenerators 쿼리 템플릿과 쿼리 파라미터를 인자로 받아 쿼리를 반환하는 함수 목록 타입
 type QueryTemplateParserGenerators []func(queryTemplate string, bodyParams map[string]interface{}) (string, string)
@@ -21,12 +25,30 @@ type QueryInfo struct {
 
 // MetricDefinition 메트릭 정의 구조체
 type MetricDefinition struct {
-	Label        string                          // 메트릭의 라벨
-	SubLabels    []string                        // 쿼리 템플릿의 라벨
-	QueryInfos   map[PrometheusVersion]QueryInfo // 버전별 쿼리 모음
-	UnitTypeKeys []common.UnitTypeKey            // 쿼리 결과값의 단위 타입의 키 목록(쿼리 템플릿과 맵핑)
-	PrimaryUnit  string                          // 쿼리 결과값의 단위 중 주단위
-	MetricKeys   []MetricKey                     // 다른 메트릭 정의를 활용하는 메트릭(다른 메트릭 활용 시 해당 값만 작성)
+	Label             string                          // 메트릭의 라벨
+	SubLabels         []string                        // 쿼리 템플릿의 라벨
+	QueryInfos        map[PrometheusVersion]QueryInfo // 버전별 쿼리 모음
+	UnitTypeKeys      []common.UnitTypeKey            // 쿼리 결과값의 단위 타입의 키 목록(쿼리 템플릿과 맵핑)
+	PrimaryUnit       string                          // 쿼리 결과값의 단위 중 주단위
+	MetricKeys        []MetricKey                     // 다른 메트릭 정의를 활용하는 메트릭(다른 메트릭 활용 시 해당 값만 작성)
+	KubernetesQuery   *KubernetesQuery                // Kubernetes API 를 통해 조회하는 메트릭(정의 시 QueryInfos 대신 사용)
+	CapacityMetricKey MetricKey                       // 사용량의 한계(용량)를 조회하는 메트릭, 범위 조회 통계의 소진 예상 시점 계산에 사용
+}
+
+// KubernetesQueryType Kubernetes API 를 통한 메트릭 조회 방식
+type KubernetesQueryType string
+
+const (
+	CountQuery  = KubernetesQueryType("count")  // 조건에 맞는 리소스의 수
+	ObjectQuery = KubernetesQueryType("object") // 조건에 맞는 리소스의 필드 목록
+)
+
+// KubernetesQuery Kubernetes API 를 통해 조회하는 메트릭의 쿼리 정의 구조체
+type KubernetesQuery struct {
+	Type        KubernetesQueryType         // 조회 방식
+	Resource    schema.GroupVersionResource // 조회 대상 리소스(CRD 포함)
+	ParamFields map[string]string           // 필터링에 사용하는 요청 파라미터 키와 리소스 필드 경로(common.GetE 경로, 키의 . 은 \. 로 이스케이프)
+	Field       string                      // ObjectQuery 에서 응답값으로 사용하는 리소스 필드 경로
 }
 
 // MetricDefinitions 메트릭 키에 따른 메트릭 정의 상수
@@ -490,6 +512,17 @@ var (
 			},
 			PrimaryUnit: "Bps",
 		},
+		LimitRange: {
+			Label: "LIMIT RANGE",
+			KubernetesQuery: &KubernetesQuery{
+				Type:     ObjectQuery,
+				Resource: schema.GroupVersionResource{Version: "v1", Resource: "limitranges"},
+				ParamFields: map[string]string{
+					"namespace": "metadata.namespace",
+				},
+				Field: "spec",
+			},
+		},
 		NumberOfContainer: {
 			Label: "CONTAINER",
 			QueryInfos: map[PrometheusVersion]QueryInfo{
@@ -544,6 +577,17 @@ var (
 		},
 		NumberOfPipeline: {
 			Label: "PIPELINE",
+			KubernetesQuery: &KubernetesQuery{
+				Type:     CountQuery,
+				Resource: schema.GroupVersionResource{Group: "tekton.dev", Version: "v1beta1", Resource: "pipelines"},
+				ParamFields: map[string]string{
+					"namespace": "metadata.namespace",
+				},
+			},
+			UnitTypeKeys: []common.UnitTypeKey{
+				common.Count,
+			},
+			PrimaryUnit: "",
 		},
 		NumberOfPod: {
 			Label: "POD",
@@ -646,8 +690,46 @@ var (
 			UnitTypeKeys: []common.UnitTypeKey{
 				common.Core,
 			},
+			PrimaryUnit:       "Core",
+			CapacityMetricKey: NodeCpuCapacity,
+		},
+		NodeCpuCapacity: {
+			Label: "CPU CAPACITY",
+			QueryInfos: map[PrometheusVersion]QueryInfo{
+				v2_20_0: {
+					QueryTemplates: []string{
+						// 노드의 CPU Core 수
+						"sum(kube_node_status_capacity{resource=\"cpu\",unit=\"core\",node=~\"%s\"})",
+					},
+					QueryTemplateParserGenerators: QueryTemplateParserGenerators{
+						queryTemplateParserGenerator([]interface{}{"node"}),
+					},
+				},
+			},
+			UnitTypeKeys: []common.UnitTypeKey{
+				common.Core,
+			},
 			PrimaryUnit: "Core",
 		},
+		NodeCpuRequest: {
+			Label: "CPU REQUEST",
+			QueryInfos: map[PrometheusVersion]QueryInfo{
+				v2_20_0: {
+					QueryTemplates: []string{
+						// 노드에 배치된 컨테이너의 CPU 요청량 합계(Core)
+						"sum(kube_pod_container_resource_requests{resource=\"cpu\",node=~\"%s\"})",
+					},
+					QueryTemplateParserGenerators: QueryTemplateParserGenerators{
+						queryTemplateParserGenerator([]interface{}{"node"}),
+					},
+				},
+			},
+			UnitTypeKeys: []common.UnitTypeKey{
+				common.Core,
+			},
+			PrimaryUnit:       "Core",
+			CapacityMetricKey: NodeCpuCapacity,
+		},
 		NodeCpuLoadAverage: {
 			Label: "CPU LOAD AVERAGE",
 			QueryInfos: map[PrometheusVersion]QueryInfo{
@@ -709,6 +791,25 @@ var (
 			UnitTypeKeys: []common.UnitTypeKey{
 				common.BinaryBytes,
 			},
+			PrimaryUnit:       "B",
+			CapacityMetricKey: NodeFileSystemSize,
+		},
+		NodeFileSystemSize: {
+			Label: "FILE SYSTEM SIZE",
+			QueryInfos: map[PrometheusVersion]QueryInfo{
+				v2_20_0: {
+					QueryTemplates: []string{
+						// 노드의 총 파일 시스템 크기
+						"sum(node_filesystem_size_bytes{mountpoint=\"/\",fstype!=\"rootfs\",instance=~\"%s\"})",
+					},
+					QueryTemplateParserGenerators: QueryTemplateParserGenerators{
+						queryTemplateParserGenerator([]interface{}{"instance"}),
+					},
+				},
+			},
+			UnitTypeKeys: []common.UnitTypeKey{
+				common.BinaryBytes,
+			},
 			PrimaryUnit: "B",
 		},
 		NodeMemory: {
@@ -726,6 +827,44 @@ var (
 			UnitTypeKeys: []common.UnitTypeKey{
 				common.BinaryBytes,
 			},
+			PrimaryUnit:       "B",
+			CapacityMetricKey: NodeMemorySize,
+		},
+		NodeMemoryRequest: {
+			Label: "MEMORY REQUEST",
+			QueryInfos: map[PrometheusVersion]QueryInfo{
+				v2_20_0: {
+					QueryTemplates: []string{
+						// 노드에 배치된 컨테이너의 메모리 요청량 합계(byte)
+						"sum(kube_pod_container_resource_requests{resource=\"memory\",node=~\"%s\"})",
+					},
+					QueryTemplateParserGenerators: QueryTemplateParserGenerators{
+						queryTemplateParserGenerator([]interface{}{"node"}),
+					},
+				},
+			},
+			UnitTypeKeys: []common.UnitTypeKey{
+				common.BinaryBytes,
+			},
+			PrimaryUnit:       "B",
+			CapacityMetricKey: NodeMemorySize,
+		},
+		NodeMemorySize: {
+			Label: "MEMORY SIZE",
+			QueryInfos: map[PrometheusVersion]QueryInfo{
+				v2_20_0: {
+					QueryTemplates: []string{
+						// 노드의 총 메모리 크기
+						"sum(node_memory_MemTotal_bytes{instance=~\"%s\"})",
+					},
+					QueryTemplateParserGenerators: QueryTemplateParserGenerators{
+						queryTemplateParserGenerator([]interface{}{"instance"}),
+					},
+				},
+			},
+			UnitTypeKeys: []common.UnitTypeKey{
+				common.BinaryBytes,
+			},
 			PrimaryUnit: "B",
 		},
 		NodeNetworkIO: {
@@ -942,7 +1081,8 @@ var (
 			UnitTypeKeys: []common.UnitTypeKey{
 				"",
 			},
-			PrimaryUnit: "",
+			PrimaryUnit:       "",
+			CapacityMetricKey: QuotaCountPodHard,
 		},
 		QuotaCountReplicationControllerHard: {
 			Label: "OBJECT COUNT REPLICATION CONTROLLERS HARD",
@@ -1180,7 +1320,8 @@ var (
 			UnitTypeKeys: []common.UnitTypeKey{
 				"",
 			},
-			PrimaryUnit: "Core",
+			PrimaryUnit:       "Core",
+			CapacityMetricKey: QuotaLimitCpuHard,
 		},
 		QuotaLimitMemoryHard: {
 			Label: "MEMORY LIMIT HARD",
@@ -1214,7 +1355,8 @@ var (
 			UnitTypeKeys: []common.UnitTypeKey{
 				common.BinaryBytes,
 			},
-			PrimaryUnit: "B",
+			PrimaryUnit:       "B",
+			CapacityMetricKey: QuotaLimitMemoryHard,
 		},
 		QuotaLimitPodCpu: {
 			Label: "POD CPU LIMIT",
@@ -1299,7 +1441,8 @@ var (
 			UnitTypeKeys: []common.UnitTypeKey{
 				"",
 			},
-			PrimaryUnit: "Core",
+			PrimaryUnit:       "Core",
+			CapacityMetricKey: QuotaRequestCpuHard,
 		},
 		QuotaRequestMemoryHard: {
 			Label: "MEMORY REQUEST HARD",
@@ -1333,7 +1476,8 @@ var (
 			UnitTypeKeys: []common.UnitTypeKey{
 				common.BinaryBytes,
 			},
-			PrimaryUnit: "B",
+			PrimaryUnit:       "B",
+			CapacityMetricKey: QuotaRequestMemoryHard,
 		},
 		QuotaRequestPodCpu: {
 			Label: "POD CPU REQUEST",
@@ -1418,7 +1562,19 @@ var (
 			UnitTypeKeys: []common.UnitTypeKey{
 				common.BinaryBytes,
 			},
-			PrimaryUnit: "B",
+			PrimaryUnit:       "B",
+			CapacityMetricKey: QuotaRequestStorageHard,
+		},
+		ResourceQuota: {
+			Label: "RESOURCE QUOTA",
+			KubernetesQuery: &KubernetesQuery{
+				Type:     ObjectQuery,
+				Resource: schema.GroupVersionResource{Version: "v1", Resource: "resourcequotas"},
+				ParamFields: map[string]string{
+					"namespace": "metadata.namespace",
+				},
+				Field: "status",
+			},
 		},
 		SummaryNodeInfo: {
 			MetricKeys: []MetricKey{CustomNodeCpu, CustomNodeFileSystem, CustomNodeMemory, NodeNetworkIn, NodeNetworkOut, NumberOfPod},
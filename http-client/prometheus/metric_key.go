@@ -25,11 +25,17 @@ const (
 	HaProxyTrafficIn                    = MetricKey("ha_proxy_traffic_in")
 	HaProxyTrafficOut                   = MetricKey("ha_proxy_traffic_out")
 	HaProxyConnectionRate               = MetricKey("ha_proxy_connection_rate")
+	LimitRange                          = MetricKey("limit_range")
 	NodeCpu                             = MetricKey("node_cpu")
+	NodeCpuCapacity                     = MetricKey("node_cpu_capacity")
+	NodeCpuRequest                      = MetricKey("node_cpu_request")
 	NodeCpuLoadAverage                  = MetricKey("node_cpu_load_average")
 	NodeDiskIO                          = MetricKey("node_disk_io")
 	NodeFileSystem                      = MetricKey("node_file_system")
+	NodeFileSystemSize                  = MetricKey("node_file_system_size")
 	NodeMemory                          = MetricKey("node_memory")
+	NodeMemoryRequest                   = MetricKey("node_memory_request")
+	NodeMemorySize                      = MetricKey("node_memory_size")
 	NodeNetworkIn                       = MetricKey("node_network_in")
 	NodeNetworkIO                       = MetricKey("node_network_io")
 	NodeNetworkOut                      = MetricKey("node_network_out")
@@ -78,6 +84,7 @@ const (
 	QuotaRequestPodMemory               = MetricKey("quota_request_pod_memory")
 	QuotaRequestStorageHard             = MetricKey("quota_request_storage_hard")
 	QuotaRequestStorageUsed             = MetricKey("quota_request_storage_used")
+	ResourceQuota                       = MetricKey("resource_quota")
 	SummaryNodeInfo                     = MetricKey("summary_node_info")
 	SummaryContainerCpuInfo             = MetricKey("summary_container_cpu_info")
 	SummaryContainerMemoryInfo          = MetricKey("summary_container_memory_info")
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"go-practice/common"
+	"go-practice/common/collection"
 )
 
 // MetricResponse 메트릭 응답
@@ -18,6 +19,8 @@ type MetricResponse struct {
 	Values     interface{} `json:"values,omitempty"`
 	Error      interface{} `json:"error,omitempty"`
 	Queries    []string    `json:"queries,omitempty"`
+
+	Stats map[string]SeriesStats `json:"stats,omitempty"` // 범위 조회 시 시계열(서브 라벨)별 통계(stats=true 요청 시)
 }
 
 // MakeMetricResponse QueryTemplates의 수와 동일한 resultSet이 인자로 들어오고 해당 resultSet을 이용하여 응답값을 만드는 함수
@@ -28,10 +31,10 @@ func MakeMetricResponse(metricKey MetricKey, unitTypeKeys []common.UnitTypeKey,
 	case
 		ContainerCpu, ContainerDiskIORead, ContainerDiskIOWrite, ContainerFileSystem, ContainerMemory,
 		ContainerNetworkIn, ContainerNetworkIO, ContainerNetworkOut, ContainerNetworkPacket, ContainerNetworkPacketDrop,
-		HaProxyTrafficIn, HaProxyTrafficOut, HaProxyConnectionRate, NodeCpu, NodeCpuLoadAverage,
-		NodeDiskIO, NodeFileSystem, NodeMemory, NodeNetworkIn, NodeNetworkIO,
+		HaProxyTrafficIn, HaProxyTrafficOut, HaProxyConnectionRate, NodeCpu, NodeCpuCapacity, NodeCpuLoadAverage, NodeCpuRequest,
+		NodeDiskIO, NodeFileSystem, NodeFileSystemSize, NodeMemory, NodeMemoryRequest, NodeMemorySize, NodeNetworkIn, NodeNetworkIO,
 		NodeNetworkOut, NodeNetworkPacket, NodeNetworkPacketDrop, NumberOfContainer, NumberOfDeployment,
-		NumberOfIngress, NumberOfNamespace, NumberOfPod, NumberOfService, NumberOfStatefulSet,
+		NumberOfIngress, NumberOfNamespace, NumberOfPipeline, NumberOfPod, NumberOfService, NumberOfStatefulSet,
 		NumberOfVolume, QuotaCountConfigMapHard, QuotaCountConfigMapUsed,
 		QuotaCountPersistentVolumeClaimHard, QuotaCountPersistentVolumeClaimUsed, QuotaCountPodHard,
 		QuotaCountPodUsed, QuotaCountReplicationControllerHard, QuotaCountReplicationControllerUsed,
@@ -44,18 +47,18 @@ func MakeMetricResponse(metricKey MetricKey, unitTypeKeys []common.UnitTypeKey,
 		QuotaRequestPodEphemeralStorage, QuotaRequestPodMemory, QuotaRequestStorageHard, QuotaRequestStorageUsed:
 		if !isRange {
 			var rawUsage = resultSets[0]
-			var resultSet0, _ = strconv.ParseFloat(fmt.Sprintf("%s", resultSets[0]), 64)
+			var resultSet0 = parseNumber(resultSets[0])
 
 			if rawUsage == nil {
 				rawUsage = ""
 			}
 
 			if unitTypeKeys != nil && unitTypeKeys[0] != "" {
-				resultSet0 = common.Humanize(resultSet0, unitTypeKeys[0],
-					&common.HumanizeOptions{PreferredUnit: maxValueUnit, Precision: 2}).Value
+				resultSet0 = common.Humanize(resultSet0.Value, unitTypeKeys[0],
+					&common.HumanizeOptions{PreferredUnit: commonUnits(unitTypeKeys, maxValueUnit, resultSets)[0], Precision: 2}).Number()
 			}
 			return MetricResponse{
-				Usage:    strconv.FormatFloat(resultSet0, 'f', -1, 64),
+				Usage:    resultSet0.String(),
 				RawUsage: fmt.Sprintf("%s", rawUsage),
 			}
 		} else {
@@ -69,22 +72,21 @@ func MakeMetricResponse(metricKey MetricKey, unitTypeKeys []common.UnitTypeKey,
 				}
 			}
 			if resultSets[maxIdx] != nil {
+				units := commonUnits(unitTypeKeys, maxValueUnit, resultSets)
 				resultSet0 = resultSets[maxIdx].([]interface{})
 				for idx, values := range resultSets[maxIdx].([]interface{}) {
 					temp := values.(map[string]interface{})
-					var value, _ = strconv.ParseFloat(fmt.Sprintf("%s", temp["value"]), 64)
-					temp[subLabels[maxIdx]] = common.Humanize(value, unitTypeKeys[maxIdx],
-						&common.HumanizeOptions{PreferredUnit: maxValueUnit, Precision: 2}).Value
+					var value = parseNumber(temp["value"])
+					temp[subLabels[maxIdx]] = humanizeNumber(value, unitTypeKeys[maxIdx], units[maxIdx])
 					for j := 0; j < len(resultSets); j++ {
 						if maxIdx != j {
 							if resultSets[j] == nil {
-								temp[subLabels[j]] = 0
+								temp[subLabels[j]] = common.NewNumber(0)
 
 							} else if len(resultSets[j].([]interface{})) > idx {
 								var tempJ = resultSets[j].([]interface{})[idx].(map[string]interface{})["value"]
-								var valueJ, _ = strconv.ParseFloat(fmt.Sprintf("%s", tempJ), 64)
-								temp[subLabels[j]] = common.Humanize(valueJ, unitTypeKeys[j],
-									&common.HumanizeOptions{PreferredUnit: maxValueUnit, Precision: 2}).Value
+								var valueJ = parseNumber(tempJ)
+								temp[subLabels[j]] = humanizeNumber(valueJ, unitTypeKeys[j], units[j])
 							}
 						}
 					}
@@ -99,25 +101,26 @@ func MakeMetricResponse(metricKey MetricKey, unitTypeKeys []common.UnitTypeKey,
 		CustomContainerVolume, CustomNodeCpu, CustomNodeFileSystem, CustomNodeMemory, CustomQuotaLimitCpu,
 		CustomQuotaLimitMemory, CustomQuotaRequestCpu, CustomQuotaRequestMemory:
 		if len(resultSets) != 0 {
-			var resultSet0, _ = strconv.ParseFloat(fmt.Sprintf("%s", resultSets[0]), 64)
-			var resultSet1, _ = strconv.ParseFloat(fmt.Sprintf("%s", resultSets[1]), 64)
-			var resultSet2, _ = strconv.ParseFloat(fmt.Sprintf("%s", resultSets[2]), 64)
+			var resultSet0 = parseNumber(resultSets[0])
+			var resultSet1 = parseNumber(resultSets[1])
+			var resultSet2 = parseNumber(resultSets[2])
+			units := commonUnits(unitTypeKeys, maxValueUnit, resultSets)
 			if unitTypeKeys != nil && unitTypeKeys[0] != "" {
-				resultSet0 = common.Humanize(resultSet0, unitTypeKeys[0],
-					&common.HumanizeOptions{PreferredUnit: maxValueUnit, Precision: 2}).Value
+				resultSet0 = common.Humanize(resultSet0.Value, unitTypeKeys[0],
+					&common.HumanizeOptions{PreferredUnit: units[0], Precision: 2}).Number()
 			}
 			if unitTypeKeys != nil && unitTypeKeys[1] != "" {
-				resultSet1 = common.Humanize(resultSet1, unitTypeKeys[1],
-					&common.HumanizeOptions{PreferredUnit: maxValueUnit, Precision: 2}).Value
+				resultSet1 = common.Humanize(resultSet1.Value, unitTypeKeys[1],
+					&common.HumanizeOptions{PreferredUnit: units[1], Precision: 2}).Number()
 			}
 			if unitTypeKeys != nil && unitTypeKeys[2] != "" {
-				resultSet2 = common.Humanize(resultSet2, unitTypeKeys[2],
-					&common.HumanizeOptions{PreferredUnit: maxValueUnit, Precision: 2}).Value
+				resultSet2 = common.Humanize(resultSet2.Value, unitTypeKeys[2],
+					&common.HumanizeOptions{PreferredUnit: units[2], Precision: 2}).Number()
 			}
 			return MetricResponse{
-				Usage:      fmt.Sprintf("%v", resultSet0),
-				Total:      fmt.Sprintf("%v", resultSet1),
-				Percentage: fmt.Sprintf("%v", resultSet2),
+				Usage:      resultSet0.String(),
+				Total:      resultSet1.String(),
+				Percentage: resultSet2.String(),
 			}
 		}
 	case
@@ -130,13 +133,14 @@ func MakeMetricResponse(metricKey MetricKey, unitTypeKeys []common.UnitTypeKey,
 		if len(resultSets) != 0 && resultSets[0] != nil {
 			var resultSet0 = resultSets[0].(map[int]interface{})
 			if unitTypeKeys != nil && unitTypeKeys[0] != "" {
+				unit := commonUnits(unitTypeKeys, maxValueUnit, resultSets)[0]
 				for _, values := range resultSet0 {
 					temp := values.(map[string]interface{})
-					var value, _ = strconv.ParseFloat(fmt.Sprintf("%s", temp["value"]), 64)
-					temp["value"] = strconv.FormatFloat(common.Humanize(value, unitTypeKeys[0],
-						&common.HumanizeOptions{PreferredUnit: maxValueUnit, Precision: 2}).Value, 'f', -1, 64)
-					temp["unit"] = common.Humanize(value, unitTypeKeys[0],
-						&common.HumanizeOptions{PreferredUnit: maxValueUnit, Precision: 2}).Unit
+					var value = parseNumber(temp["value"])
+					humanized := common.Humanize(value.Value, unitTypeKeys[0],
+						&common.HumanizeOptions{PreferredUnit: unit, Precision: 2})
+					temp["value"] = strconv.FormatFloat(humanized.Value, 'f', -1, 64)
+					temp["unit"] = humanized.Unit
 				}
 			}
 			return MetricResponse{
@@ -151,18 +155,18 @@ func MakeMetricResponse(metricKey MetricKey, unitTypeKeys []common.UnitTypeKey,
 			for key, value := range resultSet0 {
 				switch MetricKey(key) {
 				case CustomNodeCpu, CustomNodeFileSystem, CustomNodeMemory:
-					label := fmt.Sprintf("%s", common.Get(value, "Label"))
+					label := common.GetString(value, "Label")
 					usage := common.Get(value, "Usage")
 					percentage := common.Get(value, "Percentage")
 					unit := common.Get(value, "Unit")
 					values[label] = fmt.Sprintf("%s %s (%s%%)", usage, unit, percentage)
 				case NodeNetworkIn, NodeNetworkOut:
-					label := fmt.Sprintf("%s", common.Get(value, "Label"))
+					label := common.GetString(value, "Label")
 					usage := common.Get(value, "Usage")
 					unit := common.Get(value, "Unit")
 					values[label] = fmt.Sprintf("%s %s", usage, unit)
 				case NumberOfPod:
-					label := fmt.Sprintf("%s", common.Get(value, "Label"))
+					label := common.GetString(value, "Label")
 					usage := common.Get(value, "Usage")
 					values[label] = fmt.Sprintf("%s", usage)
 				}
@@ -238,11 +242,11 @@ func MakeMetricResponse(metricKey MetricKey, unitTypeKeys []common.UnitTypeKey,
 						if rawUsage != "0" && rawUsage != "" && rawUsage != nil {
 							floatUsage, err := strconv.ParseFloat(rawUsage.(string), 64)
 							if err != nil {
-								fmt.Println("failed to read response body, err=%s\n", err)
+								fmt.Printf("failed to read response body, err=%s\n", err)
 							}
 							limitFloat, err := strconv.ParseFloat(rawLimitValue.(string), 64)
 							if err != nil {
-								fmt.Println("failed to read response body, err=%s\n", err)
+								fmt.Printf("failed to read response body, err=%s\n", err)
 							}
 							percentage = common.RoundFloat(floatUsage/limitFloat*100, 2)
 
@@ -269,3 +273,75 @@ func MakeMetricResponse(metricKey MetricKey, unitTypeKeys []common.UnitTypeKey,
 
 	return MetricResponse{}
 }
+
+// commonUnits 결과셋별 단위 타입에서 사용할 공통 단위 목록을 반환하는 함수
+// maxValueUnit 이 단위 타입에 있으면 그대로 사용하고, 없으면 같은 단위 타입의 결과셋 값 전체에서 최대값 기준으로 단위를 고른다.
+// (결과셋의 값마다 단위를 따로 정하면 하나의 차트, 표에서 MiB 와 GiB 가 섞이게 됨)
+func commonUnits(unitTypeKeys []common.UnitTypeKey, maxValueUnit string, resultSets []interface{}) []string {
+	units := make([]string, len(resultSets))
+	if unitTypeKeys == nil {
+		return units
+	}
+	valuesByUnitType := make(map[common.UnitTypeKey][][]float64)
+	for i, resultSet := range resultSets {
+		if i < len(unitTypeKeys) && unitTypeKeys[i] != "" {
+			valuesByUnitType[unitTypeKeys[i]] = append(valuesByUnitType[unitTypeKeys[i]], resultSetValues(resultSet))
+		}
+	}
+	for i := range resultSets {
+		if i >= len(unitTypeKeys) || unitTypeKeys[i] == "" {
+			continue
+		}
+		unitType, _ := common.LookupUnitType(unitTypeKeys[i])
+		if collection.Contains(unitType.Units, maxValueUnit) {
+			units[i] = maxValueUnit
+		} else {
+			units[i] = common.ChooseUnit(unitTypeKeys[i], common.UnitPolicyMax,
+				&common.HumanizeOptions{Precision: 2}, valuesByUnitType[unitTypeKeys[i]]...)
+		}
+	}
+	return units
+}
+
+// resultSetValues ParseQueryResult 의 결과셋(단일 값, 범위 조회 목록, Top N 맵)에서 숫자 값 목록을 반환하는 함수
+func resultSetValues(resultSet interface{}) []float64 {
+	values := make([]float64, 0)
+	appendValue := func(value interface{}) {
+		if number, err := common.ParseNumber(value); err == nil && number.IsFinite() {
+			values = append(values, number.Value)
+		}
+	}
+	switch v := resultSet.(type) {
+	case nil:
+	case []interface{}:
+		for _, element := range v {
+			appendValue(common.Get(element, "value"))
+		}
+	case map[int]interface{}:
+		for _, element := range v {
+			appendValue(common.Get(element, "value"))
+		}
+	default:
+		appendValue(v)
+	}
+	return values
+}
+
+// parseNumber 결과셋의 값(프로메테우스 응답의 문자열)을 common.Number 로 변환하는 함수
+// 원본 문자열(Raw)과 NaN, +Inf, -Inf 를 그대로 유지하고 값이 없거나 숫자가 아닌 경우 0 을 반환한다.
+func parseNumber(value interface{}) common.Number {
+	number, err := common.ParseNumber(value)
+	if err != nil {
+		return common.NewNumber(0)
+	}
+	return number
+}
+
+// humanizeNumber 범위 조회 값을 단위 타입의 unit 으로 변환하여 소수점 2 자리로 반올림하는 함수
+// 단위 타입이 없는 값은 원본 10진수 문자열에서 반올림하여 float64 로 표현할 수 없는 큰 값도 자릿수를 유지한다.
+func humanizeNumber(number common.Number, unitTypeKey common.UnitTypeKey, unit string) common.Number {
+	if unitTypeKey == "" {
+		return number.Round(2, common.RoundHalfUp)
+	}
+	return common.Humanize(number.Value, unitTypeKey, &common.HumanizeOptions{PreferredUnit: unit, Precision: 2}).Number()
+}
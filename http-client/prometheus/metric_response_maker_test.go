@@ -0,0 +1,94 @@
+package prometheus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go-practice/common"
+)
+
+func TestMakeMetricResponseCommonUnit(t *testing.T) {
+	resultSets := []interface{}{
+		[]interface{}{
+			map[string]interface{}{"timestamp": 1.0, "value": "1073741824"},
+			map[string]interface{}{"timestamp": 2.0, "value": "524288000"},
+		},
+		[]interface{}{
+			map[string]interface{}{"timestamp": 1.0, "value": "2147483648"},
+			map[string]interface{}{"timestamp": 2.0, "value": "1048576"},
+		},
+	}
+	response := MakeMetricResponse(NodeMemory, []common.UnitTypeKey{common.BinaryBytes, common.BinaryBytes},
+		"", []string{"used", "total"}, true, resultSets...)
+
+	values := response.Values.([]interface{})
+	expected := []map[string]float64{{"used": 1, "total": 2}, {"used": 0.49, "total": 0.00098}}
+	for i, value := range values {
+		for label, number := range expected[i] {
+			if got := value.(map[string]interface{})[label]; got != common.NewNumber(number) {
+				t.Errorf("sample %d %s: expected %v GiB, got %v", i, label, number, got)
+			}
+		}
+	}
+
+	top := map[int]interface{}{
+		0: map[string]interface{}{"id": "a", "value": "1073741824"},
+		1: map[string]interface{}{"id": "b", "value": "1048576"},
+	}
+	MakeMetricResponse(Top5ContainerMemoryByPod, []common.UnitTypeKey{common.BinaryBytes}, "", nil, false, top)
+	for _, row := range top {
+		if unit := row.(map[string]interface{})["unit"]; unit != "GiB" {
+			t.Errorf("expected every row in GiB, got %v", unit)
+		}
+	}
+}
+
+func TestMakeMetricResponseSpecialValues(t *testing.T) {
+	resultSets := []interface{}{
+		[]interface{}{
+			map[string]interface{}{"timestamp": 1.0, "value": "NaN"},
+			map[string]interface{}{"timestamp": 2.0, "value": "+Inf"},
+			map[string]interface{}{"timestamp": 3.0, "value": "2048"},
+		},
+	}
+	response := MakeMetricResponse(ContainerMemory, []common.UnitTypeKey{common.BinaryBytes},
+		"", []string{"used"}, true, resultSets...)
+
+	data, err := json.Marshal(response.Values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"timestamp":1,"used":"NaN"},{"timestamp":2,"used":"+Inf"},{"timestamp":3,"used":2}]`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+}
+
+func TestMakeMetricResponseLargeInteger(t *testing.T) {
+	const large = "18446744073709551615"
+	response := MakeMetricResponse(NumberOfPod, []common.UnitTypeKey{""}, "", nil, false, large)
+	if response.Usage != large || response.RawUsage != large {
+		t.Errorf("expected usage %s, got %+v", large, response)
+	}
+
+	resultSets := []interface{}{
+		[]interface{}{
+			map[string]interface{}{"timestamp": 1.0, "value": large},
+			map[string]interface{}{"timestamp": 2.0, "value": "NaN"},
+		},
+	}
+	response = MakeMetricResponse(NumberOfPod, []common.UnitTypeKey{""}, "", []string{"count"}, true, resultSets...)
+	data, err := json.Marshal(response.Values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"count":` + large + `,"timestamp":1},{"count":"NaN","timestamp":2}]`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	response = MakeMetricResponse(CustomQuotaLimitCpu, []common.UnitTypeKey{"", "", ""}, "", nil, false, large, "+Inf", "12.5")
+	if response.Usage != large || response.Total != "+Inf" || response.Percentage != "12.5" {
+		t.Errorf("unexpected custom response %+v", response)
+	}
+}
@@ -0,0 +1,29 @@
+package prometheus
+
+// IsNamespaceScoped 메트릭이 namespace 파라미터로 조회 범위를 제한할 수 있는 메트릭인지 확인하는 함수
+// 목록에 없는 메트릭(노드, PersistentVolume 등 클러스터 전체 메트릭)은 클러스터 범위로 취급하여 관리자만 조회할 수 있다.
+func IsNamespaceScoped(metricKey MetricKey) bool {
+	switch metricKey {
+	case
+		ContainerCpu, ContainerDiskIORead, ContainerDiskIOWrite, ContainerFileSystem, ContainerMemory,
+		ContainerNetworkIn, ContainerNetworkIO, ContainerNetworkOut, ContainerNetworkPacket, ContainerNetworkPacketDrop,
+		HaProxyTrafficIn, HaProxyTrafficOut, HaProxyConnectionRate, LimitRange,
+		NumberOfDeployment, NumberOfIngress, NumberOfNamespace, NumberOfPipeline, NumberOfPod,
+		NumberOfService, NumberOfStatefulSet, QuotaCountConfigMapHard, QuotaCountConfigMapUsed,
+		QuotaCountPersistentVolumeClaimHard, QuotaCountPersistentVolumeClaimUsed, QuotaCountPodHard,
+		QuotaCountPodUsed, QuotaCountReplicationControllerHard, QuotaCountReplicationControllerUsed,
+		QuotaCountResourceQuotaHard, QuotaCountResourceQuotaUsed, QuotaCountSecretHard,
+		QuotaCountSecretUsed, QuotaCountServiceHard, QuotaCountServiceUsed,
+		QuotaCountServiceLoadBalancerHard, QuotaCountServiceLoadBalancerUsed, QuotaCountServiceNodePortHard,
+		QuotaCountServiceNodePortUsed, QuotaLimitCpuHard, QuotaLimitCpuUsed, QuotaLimitMemoryHard, QuotaLimitMemoryUsed,
+		QuotaLimitPodCpu, QuotaLimitPodEphemeralStorage, QuotaLimitPodMemory,
+		QuotaRequestCpuHard, QuotaRequestCpuUsed, QuotaRequestMemoryHard, QuotaRequestMemoryUsed, QuotaRequestPodCpu,
+		QuotaRequestPodEphemeralStorage, QuotaRequestPodMemory, QuotaRequestStorageHard, QuotaRequestStorageUsed,
+		ResourceQuota, SummaryContainerCpuInfo, SummaryContainerMemoryInfo, SummaryCpuQuotaInfo, SummaryMemoryQuotaInfo,
+		Top5ContainerCpuByPod, Top5ContainerFileSystemByPod, Top5ContainerMemoryByPod,
+		Top5ContainerNetworkInByNamespace, Top5ContainerNetworkInByPod, Top5ContainerNetworkOutByPod,
+		Top5CountPodByNamespace:
+		return true
+	}
+	return false
+}
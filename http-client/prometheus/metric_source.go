@@ -0,0 +1,163 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go-practice/common"
+	"go-practice/common/timeutil"
+)
+
+// MetricSource 메트릭 정의에 따라 메트릭 값을 조회하는 원천 인터페이스
+type MetricSource interface {
+	// Query 메트릭 키와 요청 파라미터로 메트릭 값을 조회하여 메트릭 응답을 반환(ctx 가 종료되면 조회를 중단)
+	Query(ctx context.Context, metricKey MetricKey, bodyParams map[string]interface{}) (MetricResponse, error)
+}
+
+// MetricSourceType 메트릭 원천 종류
+type MetricSourceType string
+
+const (
+	PrometheusSourceType = MetricSourceType("prometheus")
+	KubernetesSourceType = MetricSourceType("kubernetes")
+)
+
+// MetricSources 메트릭 원천 종류에 따른 메트릭 원천 목록(초기화 시 등록)
+var MetricSources = map[MetricSourceType]MetricSource{}
+
+// SourceType 메트릭 정의의 원천 종류를 반환하는 함수(Kubernetes 쿼리가 없으면 프로메테우스)
+func (m MetricDefinition) SourceType() MetricSourceType {
+	if m.KubernetesQuery != nil {
+		return KubernetesSourceType
+	}
+	return PrometheusSourceType
+}
+
+// GetMetricResult 메트릭 키에 해당하는 메트릭 응답을 메트릭 원천에서 조회하여 메트릭 키를 키로 하는 맵으로 반환하는 함수
+// 범위 조회 파라미터(start, end, step)는 tz 파라미터의 타임존 기준으로 해석한다(NormalizeTimeParams 참고).
+func GetMetricResult(metricKey MetricKey, bodyParams map[string]interface{}) (map[string]interface{}, error) {
+	return GetMetricResultContext(context.Background(), metricKey, bodyParams)
+}
+
+// GetMetricResultContext ctx 의 취소 및 기한을 메트릭 원천의 조회에 전달하는 GetMetricResult
+func GetMetricResultContext(ctx context.Context, metricKey MetricKey, bodyParams map[string]interface{}) (map[string]interface{}, error) {
+	metricDefinition, isMetric := MetricDefinitions[metricKey]
+	if !isMetric {
+		return nil, fmt.Errorf("undefined metric key, metricKey=%s", metricKey)
+	}
+	if err := ValidateQueryParams(bodyParams); err != nil {
+		return nil, err
+	}
+
+	bodyParams, location, err := NormalizeTimeParams(bodyParams, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// 다른 메트릭의 값을 활용하는 메트릭 처리
+	if metricDefinition.MetricKeys != nil {
+		innerResult := make(map[string]interface{})
+		for _, innerMetricKey := range metricDefinition.MetricKeys {
+			result, err := GetMetricResultContext(ctx, innerMetricKey, bodyParams)
+			if err != nil {
+				return nil, err
+			}
+			innerResult = common.DeepMerge(nil, innerResult, result)
+		}
+		metricResponse := MakeMetricResponse(metricKey, nil, "", nil, false, innerResult)
+		return map[string]interface{}{string(metricKey): metricResponse.Values}, nil
+	}
+
+	sourceType := metricDefinition.SourceType()
+	source, ok := MetricSources[sourceType]
+	if !ok {
+		return nil, fmt.Errorf("metric source is not registered, source=%s", sourceType)
+	}
+
+	metricResponse, err := source.Query(ctx, metricKey, bodyParams)
+	if err != nil {
+		return nil, err
+	}
+	if isRangeQuery(bodyParams) && location != nil {
+		addSampleTimes(metricResponse.Values, location)
+	}
+	if isRangeQuery(bodyParams) && isStatsRequested(bodyParams) {
+		if err = addStats(ctx, metricKey, &metricResponse, bodyParams); err != nil {
+			return nil, err
+		}
+	}
+	return map[string]interface{}{string(metricKey): metricResponse}, nil
+}
+
+// isRangeQuery 요청 파라미터가 범위 조회 파라미터(start, end, step)를 모두 포함하는지 확인하는 함수
+func isRangeQuery(bodyParams map[string]interface{}) bool {
+	return bodyParams["start"] != nil && bodyParams["end"] != nil && bodyParams["step"] != nil
+}
+
+// NormalizeTimeParams 범위 조회 파라미터를 tz(IANA 타임존, 기본값 서버 타임존) 기준으로 해석하여 프로메테우스 API 형식으로 변환한 복사본을 반환하는 함수
+// start, end 는 unix 초/밀리초, RFC3339, 상대 표현(now-1h, today, start of week)을, step 은 초 단위 숫자 또는 duration(5m, 1d)을 허용하며
+// start 는 타임존 기준 step 경계로 내림한다. tz 파라미터가 있으면 타임존을 함께 반환한다(없으면 nil).
+/* {"start":"today","end":"now","step":"1h","tz":"Asia/Seoul"} => {"start":"1658934000","end":"1658971800","step":"3600","tz":"Asia/Seoul"}
+ */
+func NormalizeTimeParams(bodyParams map[string]interface{}, now time.Time) (map[string]interface{}, *time.Location, error) {
+	tz := common.GetString(bodyParams, "tz")
+	if tz == "" && !isRangeQuery(bodyParams) {
+		return bodyParams, nil, nil
+	}
+	location, err := timeutil.LoadLocation(tz)
+	if err != nil {
+		return nil, nil, err
+	}
+	var responseLocation *time.Location
+	if tz != "" {
+		responseLocation = location
+	}
+	if !isRangeQuery(bodyParams) {
+		return bodyParams, responseLocation, nil
+	}
+
+	step, err := timeutil.ParseDuration(common.GetString(bodyParams, "step"))
+	if err != nil || step < time.Second {
+		return nil, nil, fmt.Errorf("failed to parse step, step=%v, err=step must be at least 1s", bodyParams["step"])
+	}
+	start, err := timeutil.Parse(common.GetString(bodyParams, "start"), now, location)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse start, err=%w", err)
+	}
+	end, err := timeutil.Parse(common.GetString(bodyParams, "end"), now, location)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse end, err=%w", err)
+	}
+	start = timeutil.AlignToStep(start, step)
+	if end.Before(start) {
+		return nil, nil, fmt.Errorf("failed to normalize range, start=%s, end=%s, err=end is before start", start, end)
+	}
+
+	params := make(map[string]interface{}, len(bodyParams))
+	for key, value := range bodyParams {
+		params[key] = value
+	}
+	params["start"] = timeutil.UnixString(start)
+	params["end"] = timeutil.UnixString(end)
+	params["step"] = strconv.FormatFloat(step.Seconds(), 'f', -1, 64)
+	return params, responseLocation, nil
+}
+
+// addSampleTimes 범위 조회 응답값의 각 샘플에 타임존 기준 RFC3339 시간(time)을 추가하는 함수
+func addSampleTimes(values interface{}, location *time.Location) {
+	samples, ok := values.([]interface{})
+	if !ok {
+		return
+	}
+	for _, sample := range samples {
+		fields, ok := sample.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if timestamp, err := common.ParseNumber(fields["timestamp"]); err == nil {
+			fields["time"] = timeutil.FormatUnix(timestamp.Value, location, time.RFC3339)
+		}
+	}
+}
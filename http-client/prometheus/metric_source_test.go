@@ -0,0 +1,51 @@
+package prometheus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeTimeParams(t *testing.T) {
+	location, _ := time.LoadLocation("Asia/Seoul")
+	now := time.Date(2022, 7, 28, 10, 30, 0, 0, location)
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		start  string
+		end    string
+		step   string
+		hasLoc bool
+	}{
+		{"unix seconds", map[string]interface{}{"start": "1658970000", "end": "1658973600", "step": "60"}, "1658970000", "1658973600", "60", false},
+		{"relative with tz", map[string]interface{}{"start": "today", "end": "now", "step": "1h", "tz": "Asia/Seoul"}, "1658934000", "1658971800", "3600", true},
+		{"aligned start", map[string]interface{}{"start": "now-1h20m", "end": "now", "step": "1h", "tz": "Asia/Seoul"}, "1658966400", "1658971800", "3600", true},
+		{"fractional step", map[string]interface{}{"start": "1658970000", "end": "1658973600", "step": "1.5s"}, "1658970000", "1658973600", "1.5", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params, loc, err := NormalizeTimeParams(test.params, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if params["start"] != test.start || params["end"] != test.end || params["step"] != test.step {
+				t.Errorf("expected %s/%s/%s, got %v/%v/%v", test.start, test.end, test.step, params["start"], params["end"], params["step"])
+			}
+			if (loc != nil) != test.hasLoc {
+				t.Errorf("unexpected location %v", loc)
+			}
+		})
+	}
+
+	invalid := []map[string]interface{}{
+		{"tz": "Mars/Olympus"},
+		{"start": "now", "end": "now-1h", "step": "60"},
+		{"start": "now-1h", "end": "now", "step": "500ms"},
+		{"start": "yesterday-ish", "end": "now", "step": "60"},
+	}
+	for _, params := range invalid {
+		if _, _, err := NormalizeTimeParams(params, now); err == nil {
+			t.Errorf("expected error for %v", params)
+		}
+	}
+}
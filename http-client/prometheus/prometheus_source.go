@@ -0,0 +1,188 @@
+package prometheus
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"go-practice/common"
+	"go-practice/common/collection"
+)
+
+const (
+	queryAPIEndpoint      = "/api/v1/query"
+	queryRangeAPIEndpoint = "/api/v1/query_range"
+)
+
+// PrometheusSource 프로메테우스 API 를 통해 메트릭을 조회하는 메트릭 원천
+type PrometheusSource struct {
+	RequestURL string // 프로메테우스 요청 URL
+	Token      string // 프로메테우스 인증 토큰
+	Version    string // 클러스터의 프로메테우스 버전
+}
+
+// Query 메트릭 정의의 버전별 쿼리를 생성하여 프로메테우스 API 를 호출하고 메트릭 응답을 반환
+func (s *PrometheusSource) Query(ctx context.Context, metricKey MetricKey, bodyParams map[string]interface{}) (MetricResponse, error) {
+	// 클라이언트 생성(TLS insecure 옵션, 인증 정보, 헤더 설정)
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	// 클라이언트 연결 종료 함수 등록
+	defer client.CloseIdleConnections()
+
+	metricDefinition := MetricDefinitions[metricKey]
+	label := metricDefinition.Label
+	subLabels := metricDefinition.SubLabels
+	if subLabels == nil {
+		subLabels = []string{label}
+	}
+
+	queryInfos := metricDefinition.QueryInfos
+	definedVersions := make([]string, 0, len(queryInfos))
+	for prometheusVersion := range queryInfos {
+		definedVersions = append(definedVersions, string(prometheusVersion))
+	}
+
+	var targetVersion PrometheusVersion
+	clusterPrometheusVersion := ParseVersion(s.Version)
+
+	index := collection.IndexOf(definedVersions, clusterPrometheusVersion)
+
+	if index != -1 { // 동일한 버전이 있는 경우
+		targetVersion = PrometheusVersion(definedVersions[index])
+	} else { // 동일한 버전이 없는 경우
+		targetVersion = PrometheusVersion(GetTargetPrometheusVersion(definedVersions, clusterPrometheusVersion))
+	}
+
+	referenceVersion := queryInfos[targetVersion].ReferenceVersion
+	if referenceVersion != "" {
+		targetVersion = referenceVersion
+	}
+	queryTemplates := queryInfos[targetVersion].QueryTemplates
+	queryTemplateParsers := queryInfos[targetVersion].QueryTemplateParserGenerators
+	unitTypeKeys := metricDefinition.UnitTypeKeys
+	primaryUnit := metricDefinition.PrimaryUnit
+
+	queries := make([]string, len(queryTemplates))
+	rangeParams := make([]string, len(queryTemplates))
+
+	for i, queryTemplate := range queryTemplates {
+		queryTemplateParser := queryTemplateParsers[i]
+		if queryTemplateParser != nil {
+			queries[i], rangeParams[i] = queryTemplateParser(queryTemplate, bodyParams)
+		} else {
+			queries[i] = queryTemplate
+		}
+	}
+
+	// 프로메테우스 모니터링 API 호출
+	responses := make([]interface{}, len(queries))
+
+	// 조회된 데이터 전체에 공통으로 사용할 단위를 정책(unitPolicy, 기본값 max)에 따라 고르기 위함
+	unitPolicy, err := common.ParseUnitPolicy(common.GetString(bodyParams, "unitPolicy"))
+	if err != nil {
+		return MetricResponse{}, err
+	}
+	var primaryUnitTypeKey common.UnitTypeKey
+	var primaryValues [][]float64
+	var isRange bool
+	for queryIdx, query := range queries {
+		// vector 쿼리와 range 쿼리에 따른 requestURL
+		var escapedQuery = url.QueryEscape(query)
+		var requestURL = s.RequestURL + queryAPIEndpoint + "?query=" + escapedQuery
+		isRange = rangeParams[queryIdx] != ""
+		if isRange {
+			requestURL = s.RequestURL + queryRangeAPIEndpoint + "?query=" + escapedQuery + rangeParams[queryIdx]
+		}
+		request, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+		if err != nil {
+			return MetricResponse{}, fmt.Errorf("failed to create http request, err=%s", err)
+		}
+		request.Header.Add("Content-Type", "application/json; charset=UTF-8")
+		request.Header.Add("Access-Control-Allow-Origin", "*")
+		request.Header.Add("Access-Control-Allow-Methods", "*")
+		request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", s.Token))
+
+		// 응답 요청
+		response, err := client.Do(request)
+		if err != nil {
+			return MetricResponse{}, fmt.Errorf("failed to call http request, err=%s", err)
+		}
+
+		responseBytes, err := ioutil.ReadAll(response.Body)
+		_ = response.Body.Close()
+		if err != nil {
+			return MetricResponse{}, fmt.Errorf("failed to read response body, err=%s", err)
+		}
+		if err := checkQueryResponse(response.StatusCode, responseBytes); err != nil {
+			return MetricResponse{}, err
+		}
+
+		// Primary 단위를 기준으로 컨버팅하는 값인지 확인
+		unitType, _ := common.LookupUnitType(unitTypeKeys[queryIdx])
+		isPrimaryUnit := collection.Contains(unitType.Units, primaryUnit)
+
+		// 응답값 파싱
+		responses[queryIdx], _ = ParseQueryResult(metricKey, isPrimaryUnit, responseBytes, isRange)
+		if isPrimaryUnit && unitTypeKeys[queryIdx] != "" {
+			primaryUnitTypeKey = unitTypeKeys[queryIdx]
+			primaryValues = append(primaryValues, resultSetValues(responses[queryIdx]))
+		}
+	}
+
+	// 공통 단위 찾기
+	var maxUnit string
+	if primaryUnitTypeKey != "" {
+		maxUnit = common.ChooseUnit(primaryUnitTypeKey, unitPolicy, &common.HumanizeOptions{Precision: 2}, primaryValues...)
+	}
+
+	metricResponse := MakeMetricResponse(metricKey, unitTypeKeys, maxUnit, subLabels, isRange, responses...)
+
+	metricResponse.Label = metricDefinition.Label
+	if maxUnit == "" {
+		metricResponse.Unit = metricDefinition.PrimaryUnit
+	} else {
+		metricResponse.Unit = maxUnit
+	}
+	metricResponse.Queries = queries
+
+	return metricResponse, nil
+}
+
+// queryStatus 프로메테우스 API 응답의 상태와 에러 정보
+/* {"status":"error","errorType":"bad_data","error":"invalid parameter \"query\": 1:5: parse error: unexpected <EOF>"}
+ */
+type queryStatus struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      *struct {
+		ResultType string            `json:"resultType"`
+		Result     []json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// checkQueryResponse 프로메테우스 API 응답이 성공(2xx, status=success)이고 결과 목록을 파싱할 수 있는지 확인하는 함수
+func checkQueryResponse(statusCode int, responseBytes []byte) error {
+	var status queryStatus
+	err := json.Unmarshal(responseBytes, &status)
+	if statusCode/100 != 2 {
+		if err == nil && status.Error != "" {
+			return fmt.Errorf("failed to query prometheus, status=%d, errorType=%s, err=%s", statusCode, status.ErrorType, status.Error)
+		}
+		return fmt.Errorf("failed to query prometheus, status=%d", statusCode)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse prometheus response, err=%s", err)
+	}
+	if status.Status != "success" || status.Data == nil {
+		return fmt.Errorf("failed to query prometheus, status=%s, errorType=%s, err=%s", status.Status, status.ErrorType, status.Error)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusSourceQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		err        string
+	}{
+		{"success", http.StatusOK, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1657560872.452,"12"]}]}}`, ""},
+		{"bad query", http.StatusBadRequest, `{"status":"error","errorType":"bad_data","error":"parse error"}`, "status=400, errorType=bad_data, err=parse error"},
+		{"execution error", http.StatusUnprocessableEntity, `{"status":"error","errorType":"execution","error":"many-to-many matching not allowed"}`, "status=422"},
+		{"unavailable", http.StatusServiceUnavailable, `upstream connect error`, "status=503"},
+		{"invalid body", http.StatusOK, `<html>login</html>`, "failed to parse prometheus response"},
+		{"invalid result", http.StatusOK, `{"status":"success","data":{"resultType":"vector","result":"12"}}`, "failed to parse prometheus response"},
+		{"error status", http.StatusOK, `{"status":"error","errorType":"timeout","error":"query timed out"}`, "errorType=timeout"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.statusCode)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			source := &PrometheusSource{RequestURL: server.URL, Version: "2.30"}
+			response, err := source.Query(context.Background(), NumberOfPod, map[string]interface{}{})
+			if test.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if response.RawUsage != "12" {
+					t.Errorf("unexpected response %+v", response)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
@@ -2,8 +2,6 @@ package prometheus
 
 import (
 	"encoding/json"
-	"fmt"
-	"strconv"
 
 	"go-practice/common"
 )
@@ -30,8 +28,8 @@ func ParseQueryResult(metricKey MetricKey, isPrimaryUnit bool, responseBytes []b
 		ContainerNetworkIn, ContainerNetworkIO, ContainerNetworkOut, ContainerNetworkPacket, ContainerNetworkPacketDrop,
 		CustomContainerVolume, CustomNodeCpu, CustomNodeFileSystem, CustomNodeMemory, CustomQuotaLimitCpu,
 		CustomQuotaLimitMemory, CustomQuotaRequestCpu, CustomQuotaRequestMemory, HaProxyTrafficIn, HaProxyTrafficOut,
-		HaProxyConnectionRate, NodeCpu, NodeCpuLoadAverage, NodeDiskIO, NodeFileSystem,
-		NodeMemory, NodeNetworkIn, NodeNetworkIO, NodeNetworkOut, NodeNetworkPacket,
+		HaProxyConnectionRate, NodeCpu, NodeCpuCapacity, NodeCpuLoadAverage, NodeCpuRequest, NodeDiskIO, NodeFileSystem,
+		NodeFileSystemSize, NodeMemory, NodeMemoryRequest, NodeMemorySize, NodeNetworkIn, NodeNetworkIO, NodeNetworkOut, NodeNetworkPacket,
 		NodeNetworkPacketDrop, NumberOfContainer, NumberOfDeployment, NumberOfIngress, NumberOfNamespace,
 		NumberOfPod, NumberOfService, NumberOfStatefulSet, NumberOfVolume, QuotaCountConfigMapHard,
 		QuotaCountConfigMapUsed, QuotaCountPersistentVolumeClaimHard, QuotaCountPersistentVolumeClaimUsed,
@@ -50,14 +48,13 @@ func ParseQueryResult(metricKey MetricKey, isPrimaryUnit bool, responseBytes []b
 			if response["data"] != nil {
 				for _, ele := range response["data"].(map[string]interface{})["result"].([]interface{}) {
 					result1 = common.Get(ele, "value").([]interface{})[1]
-					maxValue, _ = strconv.ParseFloat(fmt.Sprintf("%s", result1), 64)
+					maxValue = parseNumber(result1).Value
 				}
 			}
 		} else { // (2)
 			_ = json.Unmarshal(responseBytes, &response)
-			if len(common.Get(response, "data.result").([]interface{})) != 0 {
-				values := response["data"].(map[string]interface{})["result"].([]interface{})[0].(map[string]interface{})["values"].([]interface{})
-				for _, ele := range values {
+			if len(common.GetSlice(response, "data.result")) != 0 {
+				for _, ele := range common.GetSlice(response, "data.result.0.values") {
 					temp := make(map[string]interface{})
 					temp["timestamp"] = common.Get(ele, "0")
 					temp["value"] = common.Get(ele, "1")
@@ -65,7 +62,7 @@ func ParseQueryResult(metricKey MetricKey, isPrimaryUnit bool, responseBytes []b
 
 					// 다중 값 중 최대값 저장 후 반환
 					if isPrimaryUnit {
-						float, _ := strconv.ParseFloat(fmt.Sprintf("%s", temp["value"]), 64)
+						float := parseNumber(temp["value"]).Value
 						if maxValue < float {
 							maxValue = float
 						}
@@ -111,7 +108,7 @@ func ParseQueryResult(metricKey MetricKey, isPrimaryUnit bool, responseBytes []b
 
 			// 다중 값 중 최대값 저장 후 반환
 			if isPrimaryUnit {
-				float, _ := strconv.ParseFloat(fmt.Sprintf("%s", temp["value"]), 64)
+				float := parseNumber(temp["value"]).Value
 				if maxValue < float {
 					maxValue = float
 				}
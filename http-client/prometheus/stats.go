@@ -0,0 +1,270 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"go-practice/common"
+)
+
+const defaultAnomalyThreshold = 3.0 // 이상치로 판단하는 z-score 기본값
+
+// SeriesStats 범위 조회 결과의 시계열(서브 라벨) 하나에 대한 통계
+type SeriesStats struct {
+	Min        float64     `json:"min"`
+	Max        float64     `json:"max"`
+	Avg        float64     `json:"avg"`
+	P95        float64     `json:"p95"`
+	Last       float64     `json:"last"`               // 마지막 샘플 값
+	Capacity   *float64    `json:"capacity,omitempty"` // 용량 메트릭의 현재 값(응답과 같은 단위)
+	Trend      *Trend      `json:"trend,omitempty"`
+	Exhaustion *Exhaustion `json:"exhaustion,omitempty"`
+	Anomalies  []Anomaly   `json:"anomalies,omitempty"`
+}
+
+// Trend 선형 회귀로 구한 시계열의 추세(기울기는 초당 변화량)
+type Trend struct {
+	Slope     float64 `json:"slope"`
+	Intercept float64 `json:"intercept"`
+	R2        float64 `json:"r2"`
+}
+
+// Exhaustion 추세가 유지될 경우 용량이 소진되는 예상 시점
+type Exhaustion struct {
+	ExhaustedAt int64 `json:"exhaustedAt"` // 소진 예상 시점(unix timestamp)
+	Seconds     int64 `json:"seconds"`     // 마지막 샘플 시점부터 소진까지 남은 시간(초)
+}
+
+// Anomaly z-score 가 임계값을 넘는 샘플
+type Anomaly struct {
+	Timestamp int64   `json:"timestamp"`
+	Value     float64 `json:"value"`
+	ZScore    float64 `json:"zScore"`
+}
+
+// sample 시계열의 샘플
+type sample struct {
+	timestamp float64
+	value     float64
+}
+
+// isStatsRequested 요청 파라미터에 통계 요청(stats=true)이 있는지 확인하는 함수
+func isStatsRequested(bodyParams map[string]interface{}) bool {
+	switch stats := bodyParams["stats"].(type) {
+	case bool:
+		return stats
+	case string:
+		requested, _ := strconv.ParseBool(stats)
+		return requested
+	}
+	return false
+}
+
+// anomalyThreshold 요청 파라미터의 anomalyThreshold(z-score 임계값)를 반환하는 함수
+func anomalyThreshold(bodyParams map[string]interface{}) float64 {
+	if threshold, err := strconv.ParseFloat(fmt.Sprintf("%v", bodyParams["anomalyThreshold"]), 64); err == nil && threshold > 0 {
+		return threshold
+	}
+	return defaultAnomalyThreshold
+}
+
+// addStats 범위 조회 응답의 시계열별 통계를 계산하여 응답에 추가하는 함수
+// 메트릭 정의에 용량 메트릭이 있으면 현재 용량을 조회하여 소진 예상 시점을 함께 계산한다.
+func addStats(ctx context.Context, metricKey MetricKey, metricResponse *MetricResponse, bodyParams map[string]interface{}) error {
+	series := groupSeries(metricResponse.Values)
+	if len(series) == 0 {
+		return nil
+	}
+
+	var capacity *float64
+	if capacityMetricKey := MetricDefinitions[metricKey].CapacityMetricKey; capacityMetricKey != "" {
+		value, err := getCapacity(ctx, capacityMetricKey, metricResponse.Unit, bodyParams)
+		if err != nil {
+			return err
+		}
+		capacity = value
+	}
+
+	threshold := anomalyThreshold(bodyParams)
+	metricResponse.Stats = make(map[string]SeriesStats, len(series))
+	for label, samples := range series {
+		metricResponse.Stats[label] = computeStats(samples, capacity, threshold)
+	}
+	return nil
+}
+
+// groupSeries 범위 조회 응답값([{timestamp, 서브 라벨: 값}])을 서브 라벨별 시계열로 변환하는 함수
+/* [map[timestamp:1.657561614e+09 used:4.19 total:8] map[timestamp:1.657561634e+09 used:4.31 total:8]]
+ *   => map[used:[{1.657561614e+09 4.19} {1.657561634e+09 4.31}] total:[{1.657561614e+09 8} {1.657561634e+09 8}]]
+ */
+func groupSeries(values interface{}) map[string][]sample {
+	series := make(map[string][]sample)
+	points, ok := values.([]interface{})
+	if !ok {
+		return series
+	}
+	for _, point := range points {
+		fields, ok := point.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		timestamp, err := common.ParseNumber(fields["timestamp"])
+		if err != nil {
+			continue
+		}
+		for label, field := range fields {
+			if label == "timestamp" || label == "time" {
+				continue
+			}
+			// NaN, ±Inf 샘플은 통계에서 제외
+			if value, err := common.ParseNumber(field); err == nil && value.IsFinite() {
+				series[label] = append(series[label], sample{timestamp.Value, value.Value})
+			}
+		}
+	}
+	return series
+}
+
+// getCapacity 용량 메트릭의 현재 값을 조회하여 범위 조회 응답과 같은 단위로 변환하는 함수(값이 없으면 nil)
+func getCapacity(ctx context.Context, capacityMetricKey MetricKey, unit string, bodyParams map[string]interface{}) (*float64, error) {
+	params := make(map[string]interface{}, len(bodyParams))
+	for key, value := range bodyParams {
+		switch key {
+		case "start", "end", "step", "stats", "tz":
+		default:
+			params[key] = value
+		}
+	}
+	source, ok := MetricSources[MetricDefinitions[capacityMetricKey].SourceType()]
+	if !ok {
+		return nil, fmt.Errorf("metric source is not registered, source=%s", MetricDefinitions[capacityMetricKey].SourceType())
+	}
+	capacityResponse, err := source.Query(ctx, capacityMetricKey, params)
+	if err != nil {
+		return nil, err
+	}
+
+	capacity, err := strconv.ParseFloat(capacityResponse.RawUsage, 64)
+	if err != nil || capacity <= 0 {
+		return nil, nil
+	}
+	unitTypeKeys := MetricDefinitions[capacityMetricKey].UnitTypeKeys
+	if len(unitTypeKeys) != 0 && unitTypeKeys[0] != "" {
+		capacity = common.Humanize(capacity, unitTypeKeys[0],
+			&common.HumanizeOptions{PreferredUnit: unit, Precision: 2}).Value
+	}
+	return &capacity, nil
+}
+
+// computeStats 시계열의 최소/최대/평균/p95, 선형 회귀 추세, 용량 소진 예상 시점, z-score 이상치를 계산하는 함수
+func computeStats(samples []sample, capacity *float64, threshold float64) SeriesStats {
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].timestamp < samples[j].timestamp
+	})
+
+	values := make([]float64, len(samples))
+	var sum float64
+	for i, s := range samples {
+		values[i] = s.value
+		sum += s.value
+	}
+	avg := sum / float64(len(values))
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	stats := SeriesStats{
+		Min:      sorted[0],
+		Max:      sorted[len(sorted)-1],
+		Avg:      common.RoundFloat(avg, 2),
+		P95:      percentile(sorted, 95),
+		Last:     samples[len(samples)-1].value,
+		Capacity: capacity,
+	}
+
+	if trend := linearRegression(samples); trend != nil {
+		stats.Trend = trend
+		if capacity != nil {
+			stats.Exhaustion = projectExhaustion(*trend, samples[len(samples)-1].timestamp, *capacity)
+		}
+	}
+
+	var variance float64
+	for _, value := range values {
+		variance += (value - avg) * (value - avg)
+	}
+	if stddev := math.Sqrt(variance / float64(len(values))); stddev != 0 {
+		for _, s := range samples {
+			if zScore := (s.value - avg) / stddev; math.Abs(zScore) >= threshold {
+				stats.Anomalies = append(stats.Anomalies, Anomaly{
+					Timestamp: int64(s.timestamp),
+					Value:     s.value,
+					ZScore:    common.RoundFloat(zScore, 2),
+				})
+			}
+		}
+	}
+	return stats
+}
+
+// percentile 정렬된 값 목록에서 nearest-rank 방식으로 백분위수를 구하는 함수
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// linearRegression 최소제곱법으로 시계열의 추세를 구하는 함수(샘플이 2개 미만이거나 시점이 모두 같으면 nil)
+func linearRegression(samples []sample) *Trend {
+	n := float64(len(samples))
+	if n < 2 {
+		return nil
+	}
+	var sumX, sumY float64
+	for _, s := range samples {
+		sumX += s.timestamp
+		sumY += s.value
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var sxx, sxy, syy float64
+	for _, s := range samples {
+		dx, dy := s.timestamp-meanX, s.value-meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	if sxx == 0 {
+		return nil
+	}
+	slope := sxy / sxx
+	r2 := 1.0
+	if syy != 0 {
+		r2 = sxy * sxy / (sxx * syy)
+	}
+	return &Trend{
+		Slope:     slope,
+		Intercept: meanY - slope*meanX,
+		R2:        common.RoundFloat(r2, 4),
+	}
+}
+
+// projectExhaustion 추세선이 용량에 도달하는 시점을 구하는 함수(감소 또는 정체 추세이면 nil)
+func projectExhaustion(trend Trend, lastTimestamp float64, capacity float64) *Exhaustion {
+	if trend.Slope <= 0 {
+		return nil
+	}
+	exhaustedAt := (capacity - trend.Intercept) / trend.Slope
+	seconds := exhaustedAt - lastTimestamp
+	if seconds < 0 {
+		seconds, exhaustedAt = 0, lastTimestamp
+	}
+	return &Exhaustion{
+		ExhaustedAt: int64(exhaustedAt),
+		Seconds:     int64(seconds),
+	}
+}
@@ -0,0 +1,80 @@
+package prometheus
+
+import (
+	"context"
+	"testing"
+)
+
+// rangeStubSource 범위 조회에는 고정된 시계열을, 단일 조회에는 용량을 반환하는 메트릭 원천
+type rangeStubSource struct {
+	values   []interface{}
+	capacity string
+}
+
+func (s rangeStubSource) Query(ctx context.Context, metricKey MetricKey, bodyParams map[string]interface{}) (MetricResponse, error) {
+	if isRangeQuery(bodyParams) {
+		return MetricResponse{Values: s.values, Unit: "GiB"}, nil
+	}
+	return MetricResponse{RawUsage: s.capacity}, nil
+}
+
+func TestComputeStats(t *testing.T) {
+	samples := make([]sample, 0, 20)
+	for i := 0; i < 20; i++ {
+		samples = append(samples, sample{timestamp: float64(1000 + i*60), value: 10})
+	}
+	samples[10].value = 100
+
+	stats := computeStats(samples, nil, defaultAnomalyThreshold)
+	if stats.Min != 10 || stats.Max != 100 || stats.Avg != 14.5 || stats.P95 != 10 {
+		t.Errorf("unexpected summary %+v", stats)
+	}
+	if len(stats.Anomalies) != 1 || stats.Anomalies[0].Timestamp != 1600 {
+		t.Errorf("expected a single anomaly at 1600, got %+v", stats.Anomalies)
+	}
+	if stats.Exhaustion != nil {
+		t.Errorf("expected no exhaustion without capacity, got %+v", stats.Exhaustion)
+	}
+}
+
+func TestGetMetricResultStats(t *testing.T) {
+	// 60초마다 1GiB 씩 증가하는 파일 시스템 사용량(용량 100GiB)
+	values := make([]interface{}, 0, 10)
+	for i := 0; i < 10; i++ {
+		values = append(values, map[string]interface{}{"timestamp": float64(1000 + i*60), "FILE SYSTEM": float64(10 + i)})
+	}
+	MetricSources[PrometheusSourceType] = rangeStubSource{values: values, capacity: "107374182400"}
+	defer delete(MetricSources, PrometheusSourceType)
+
+	bodyParams := map[string]interface{}{"start": "1000", "end": "1540", "step": "60"}
+	result, err := GetMetricResult(NodeFileSystem, bodyParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response := result[string(NodeFileSystem)].(MetricResponse); response.Stats != nil {
+		t.Errorf("expected no stats without stats parameter, got %v", response.Stats)
+	}
+
+	bodyParams["stats"] = "true"
+	result, err = GetMetricResult(NodeFileSystem, bodyParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, ok := result[string(NodeFileSystem)].(MetricResponse).Stats["FILE SYSTEM"]
+	if !ok {
+		t.Fatal("expected stats for FILE SYSTEM series")
+	}
+	if stats.Min != 10 || stats.Max != 19 || stats.Avg != 14.5 || stats.Last != 19 {
+		t.Errorf("unexpected summary %+v", stats)
+	}
+	if stats.Trend == nil || stats.Trend.Slope != 1.0/60 || stats.Trend.R2 != 1 {
+		t.Fatalf("unexpected trend %+v", stats.Trend)
+	}
+	// 마지막 샘플(1540초, 19GiB)부터 81GiB 가 남아 81분 후 소진
+	if stats.Capacity == nil || *stats.Capacity != 100 || stats.Exhaustion == nil || stats.Exhaustion.Seconds != 81*60 {
+		t.Errorf("unexpected exhaustion %+v", stats.Exhaustion)
+	}
+	if len(stats.Anomalies) != 0 {
+		t.Errorf("expected no anomalies for linear growth, got %+v", stats.Anomalies)
+	}
+}
@@ -2,7 +2,7 @@ package prometheus
 
 import (
 	"fmt"
-	"go-practice/common"
+	"go-practice/common/collection"
 	"math"
 	"regexp"
 	"sort"
@@ -19,7 +19,7 @@ func GetTargetPrometheusVersion(definedVersions []string, clusterPrometheusVersi
 
 	definedVersions = append(definedVersions, clusterPrometheusVersion)
 	definedVersions = sortVersions(definedVersions)
-	inputVersionIdx := common.IndexOf(sortVersions(definedVersions), clusterPrometheusVersion)
+	inputVersionIdx := collection.IndexOf(sortVersions(definedVersions), clusterPrometheusVersion)
 
 	if inputVersionIdx == 0 {
 		targetVersion = definedVersions[inputVersionIdx+1]
@@ -79,10 +79,39 @@ func pad(max float64, version []string) int {
 	return n
 }
 
+// queryOperators 쿼리 템플릿의 operator 파라미터(라벨 값이 아닌 PromQL 집계 함수 자리)로 사용할 수 있는 연산자
+var queryOperators = []string{"sum", "avg", "max", "min", "count"}
+
+// labelParamKeys 쿼리 템플릿의 라벨 값(="%s", =~"%s")으로 들어가는 파라미터
+var labelParamKeys = []string{"namespace", "pod", "node", "instance", "route"}
+
+// labelValueReplacer 라벨 값이 PromQL 문자열을 닫거나 이스케이프하지 못하도록 \ 와 " 를 이스케이프
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// ValidateQueryParams 쿼리 템플릿에 들어가는 파라미터를 확인하는 함수
+// operator 는 허용한 집계 연산자만, 라벨 파라미터는 문자열만 허용한다.
+func ValidateQueryParams(bodyParams map[string]interface{}) error {
+	if operator, ok := bodyParams["operator"]; ok && operator != nil {
+		name, isString := operator.(string)
+		if !isString || !collection.Contains(queryOperators, name) {
+			return fmt.Errorf("invalid operator parameter, operator=%v, allowed=%s", operator, strings.Join(queryOperators, "|"))
+		}
+	}
+	for _, key := range labelParamKeys {
+		if value, ok := bodyParams[key]; ok && value != nil {
+			if _, isString := value.(string); !isString {
+				return fmt.Errorf("invalid %s parameter, %s=%v", key, key, value)
+			}
+		}
+	}
+	return nil
+}
+
 // queryTemplateParserGenerator 쿼리 템플릿과 쿼리 파라미터를 인자로 받아서 쿼리를 생성하는 클로저를 반환하는 함수
 func queryTemplateParserGenerator(paramKeys []interface{}) func(string, map[string]interface{}) (string, string) {
-	params := make([]interface{}, len(paramKeys))
 	return func(queryTemplate string, bodyParams map[string]interface{}) (string, string) {
+		// 서버 요청과 구독 조회가 동시에 호출하므로 호출마다 파라미터 목록을 만든다
+		params := make([]interface{}, len(paramKeys))
 		var rangeParams string
 		for i, paramKey := range paramKeys {
 			param := bodyParams[paramKey.(string)]
@@ -93,12 +122,12 @@ func queryTemplateParserGenerator(paramKeys []interface{}) func(string, map[stri
 					param = ".*"
 				}
 			}
-			if param == "" {
-				param = ""
+			if paramKey != "operator" {
+				param = labelValueReplacer.Replace(fmt.Sprint(param))
 			}
 			params[i] = param
 		}
-		if bodyParams["start"] != nil && bodyParams["end"] != nil && bodyParams["step"] != nil {
+		if isRangeQuery(bodyParams) {
 			start := bodyParams["start"]
 			end := bodyParams["end"]
 			step := bodyParams["step"]
@@ -0,0 +1,32 @@
+package prometheus
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestQueryTemplateParserConcurrent(t *testing.T) {
+	queryInfo := MetricDefinitions[ContainerMemory].QueryInfos[v2_20_0]
+	parser := queryInfo.QueryTemplateParserGenerators[0]
+
+	start := make(chan struct{})
+	var wait sync.WaitGroup
+	for _, namespace := range []string{"team-a", "team-b"} {
+		namespace := namespace
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			<-start
+			for i := 0; i < 10000; i++ {
+				query, _ := parser(queryInfo.QueryTemplates[0], map[string]interface{}{"namespace": namespace})
+				if !strings.Contains(query, `namespace=~"`+namespace+`"`) {
+					t.Errorf("query for %s has another namespace, query=%s", namespace, query)
+					return
+				}
+			}
+		}()
+	}
+	close(start)
+	wait.Wait()
+}
@@ -0,0 +1,112 @@
+package report
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// formatValue 값과 단위를 표시 문자열로 변환하는 함수
+func formatValue(value float64, unit string) string {
+	if unit == "" {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64) + " " + unit
+}
+
+// markdownCellReplacer 표의 셀 구분자(|)와 행을 나누는 줄바꿈을 Markdown 셀 안에서 표시할 수 있도록 변환
+var markdownCellReplacer = strings.NewReplacer("|", "\\|", "\r\n", " ", "\n", " ", "\r", " ")
+
+// templateFuncs 보고서 템플릿에서 사용하는 값 표시 함수 목록
+var templateFuncs = map[string]interface{}{
+	"value": formatValue,
+	"optional": func(value *float64, unit string) string {
+		if value == nil {
+			return "-"
+		}
+		return formatValue(*value, unit)
+	},
+	"percentage": func(value *float64) string {
+		if value == nil {
+			return "-"
+		}
+		return strconv.FormatFloat(*value, 'f', -1, 64) + "%"
+	},
+	"growth": func(value float64, unit string) string {
+		if value > 0 {
+			return "+" + formatValue(value, unit)
+		}
+		return formatValue(value, unit)
+	},
+	"date": func(value *time.Time) string {
+		if value == nil {
+			return "-"
+		}
+		return value.Format("2006-01-02")
+	},
+	"cell": markdownCellReplacer.Replace,
+}
+
+const markdownTemplate = `# Capacity report
+
+Generated at {{ .GeneratedAt.Format "2006-01-02 15:04:05 MST" }} (lookback {{ .Lookback }})
+{{ if .Namespaces }}
+## Namespaces
+
+| Namespace | Resource | Used | Capacity | Headroom | Growth/day | Saturation | Status |
+|---|---|---|---|---|---|---|---|
+{{ range $target := .Namespaces }}{{ range .Resources }}| {{ $target.Name }} | {{ template "row" . }}
+{{ end }}{{ end }}{{ end }}{{ if .Nodes }}
+## Nodes
+
+| Node | Resource | Used | Capacity | Headroom | Growth/day | Saturation | Status |
+|---|---|---|---|---|---|---|---|
+{{ range $target := .Nodes }}{{ range .Resources }}| {{ $target.Name }} | {{ template "row" . }}
+{{ end }}{{ end }}{{ end }}
+{{- define "row" }}{{ .Resource }} | {{ value .Used .Unit }} | {{ optional .Capacity .Unit }} | {{ optional .Headroom .Unit }} ({{ percentage .HeadroomPercentage }}) | {{ growth .GrowthPerDay .Unit }} | {{ date .SaturatedAt }} | {{ .Status }}{{ if .Error }} ({{ cell .Error }}){{ end }} |{{ end }}`
+
+const htmlTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Capacity report</title>
+<style>
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+th:first-child, td:first-child, th:nth-child(2), td:nth-child(2) { text-align: left; }
+.warning { background: #fff4ce; }
+.critical { background: #fde7e9; }
+</style>
+</head>
+<body>
+<h1>Capacity report</h1>
+<p>Generated at {{ .GeneratedAt.Format "2006-01-02 15:04:05 MST" }} (lookback {{ .Lookback }})</p>
+{{ if .Namespaces }}<h2>Namespaces</h2>
+{{ template "table" .Namespaces }}{{ end }}
+{{ if .Nodes }}<h2>Nodes</h2>
+{{ template "table" .Nodes }}{{ end }}
+</body>
+</html>
+{{- define "table" }}<table>
+<tr><th>Name</th><th>Resource</th><th>Used</th><th>Capacity</th><th>Headroom</th><th>Growth/day</th><th>Saturation</th><th>Status</th></tr>
+{{ range $target := . }}{{ range .Resources }}<tr class="{{ .Status }}"><td>{{ $target.Name }}</td><td>{{ .Resource }}</td><td>{{ value .Used .Unit }}</td><td>{{ optional .Capacity .Unit }}</td><td>{{ optional .Headroom .Unit }} ({{ percentage .HeadroomPercentage }})</td><td>{{ growth .GrowthPerDay .Unit }}</td><td>{{ date .SaturatedAt }}</td><td>{{ .Status }}{{ if .Error }} ({{ .Error }}){{ end }}</td></tr>
+{{ end }}{{ end }}</table>
+{{ end }}`
+
+var (
+	markdown = template.Must(template.New("markdown").Funcs(templateFuncs).Parse(markdownTemplate))
+	html     = htmltemplate.Must(htmltemplate.New("html").Funcs(templateFuncs).Parse(htmlTemplate))
+)
+
+// WriteMarkdown 보고서를 Markdown 표로 작성한다
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	return markdown.Execute(w, r)
+}
+
+// WriteHTML 보고서를 HTML 문서로 작성한다
+func (r *Report) WriteHTML(w io.Writer) error {
+	return html.Execute(w, r)
+}
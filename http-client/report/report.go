@@ -0,0 +1,238 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-practice/common"
+	"go-practice/common/timeutil"
+	"go-practice/http-client/prometheus"
+)
+
+const (
+	DefaultLookback    = 7 * 24 * time.Hour // 추세를 계산하는 조회 기간 기본값
+	DefaultTimeout     = 2 * time.Minute    // 보고서 생성 제한 시간 기본값
+	DefaultConcurrency = 8                  // 동시에 조회하는 리소스 수 기본값
+	minStep            = time.Minute        // 범위 조회 간격 최소값
+	samplesPerRange    = 120                // 조회 기간 당 샘플 수(범위 조회 간격 계산에 사용)
+
+	warningDuration  = 30 * 24 * time.Hour // 포화 예상 시점이 이 기간 이내이면 warning
+	criticalDuration = 7 * 24 * time.Hour  // 포화 예상 시점이 이 기간 이내이면 critical
+)
+
+// Status 리소스의 용량 상태
+type Status string
+
+const (
+	StatusOK        = Status("ok")        // 조회 기간의 추세로는 포화되지 않음
+	StatusWarning   = Status("warning")   // 30일 이내 포화 예상
+	StatusCritical  = Status("critical")  // 7일 이내 포화 예상 또는 이미 포화
+	StatusUnlimited = Status("unlimited") // 용량(hard, 노드 용량)이 없음
+	StatusUnknown   = Status("unknown")   // 조회 실패 또는 데이터 없음
+)
+
+// resource 보고서에 포함하는 리소스(사용량 메트릭의 용량 메트릭은 메트릭 정의의 CapacityMetricKey)
+type resource struct {
+	name      string
+	metricKey prometheus.MetricKey
+}
+
+// namespaceResources 네임스페이스별 ResourceQuota 사용량(used) 대비 한도(hard)를 비교하는 리소스 목록
+var namespaceResources = []resource{
+	{"requests.cpu", prometheus.QuotaRequestCpuUsed},
+	{"requests.memory", prometheus.QuotaRequestMemoryUsed},
+	{"limits.cpu", prometheus.QuotaLimitCpuUsed},
+	{"limits.memory", prometheus.QuotaLimitMemoryUsed},
+	{"requests.storage", prometheus.QuotaRequestStorageUsed},
+	{"pods", prometheus.QuotaCountPodUsed},
+}
+
+// nodeResources 노드별 사용량 및 요청량 대비 노드 용량을 비교하는 리소스 목록
+var nodeResources = []resource{
+	{"cpu usage", prometheus.NodeCpu},
+	{"cpu requests", prometheus.NodeCpuRequest},
+	{"memory usage", prometheus.NodeMemory},
+	{"memory requests", prometheus.NodeMemoryRequest},
+	{"file system", prometheus.NodeFileSystem},
+}
+
+// Options 보고서 생성 옵션
+type Options struct {
+	Lookback    time.Duration // 추세를 계산하는 조회 기간
+	Namespaces  []string      // 보고서에 포함할 네임스페이스 목록
+	Nodes       []string      // 보고서에 포함할 노드 목록
+	Now         time.Time     // 조회 기간의 끝(지정하지 않으면 현재 시각)
+	Timeout     time.Duration // 보고서 생성 제한 시간(지정하지 않으면 DefaultTimeout)
+	Concurrency int           // 동시에 조회하는 리소스 수(지정하지 않으면 DefaultConcurrency)
+}
+
+// Report 용량 예측 보고서
+type Report struct {
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Lookback    string         `json:"lookback"`
+	Namespaces  []TargetReport `json:"namespaces,omitempty"`
+	Nodes       []TargetReport `json:"nodes,omitempty"`
+}
+
+// TargetReport 네임스페이스 또는 노드 하나의 리소스별 용량 현황
+type TargetReport struct {
+	Name      string           `json:"name"`
+	Resources []ResourceReport `json:"resources"`
+}
+
+// ResourceReport 리소스 하나의 사용량, 여유분, 증가율, 포화 예상 시점
+type ResourceReport struct {
+	Resource           string     `json:"resource"`
+	Unit               string     `json:"unit,omitempty"`
+	Used               float64    `json:"used"`
+	Capacity           *float64   `json:"capacity,omitempty"`
+	Headroom           *float64   `json:"headroom,omitempty"`
+	HeadroomPercentage *float64   `json:"headroomPercentage,omitempty"`
+	GrowthPerDay       float64    `json:"growthPerDay"`          // 선형 회귀로 구한 일간 증가량
+	SaturatedAt        *time.Time `json:"saturatedAt,omitempty"` // 추세가 유지될 경우 포화 예상 시점
+	Status             Status     `json:"status"`
+	Error              string     `json:"error,omitempty"`
+}
+
+// ParseLookback 조회 기간(7d, 12h, 1w 와 같은 duration)을 파싱하는 함수, 빈 문자열이면 기본값을 반환
+func ParseLookback(value string) (time.Duration, error) {
+	if value == "" {
+		return DefaultLookback, nil
+	}
+	lookback, err := timeutil.ParseDuration(value)
+	if err != nil || lookback <= 0 {
+		return 0, fmt.Errorf("invalid lookback, lookback=%s", value)
+	}
+	return lookback, nil
+}
+
+// Generate 조회 기간 동안의 사용량 추세로 네임스페이스와 노드의 용량 예측 보고서를 생성한다
+// 리소스는 최대 Concurrency 개씩 동시에 조회하며, 개별 리소스의 조회 실패(제한 시간 초과 포함)는
+// 보고서 생성을 중단하지 않고 해당 리소스의 Error 로 기록한다.
+func Generate(ctx context.Context, options Options) *Report {
+	if options.Lookback <= 0 {
+		options.Lookback = DefaultLookback
+	}
+	if options.Now.IsZero() {
+		options.Now = time.Now()
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultConcurrency
+	}
+	ctx, cancel := context.WithTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	step := options.Lookback / samplesPerRange
+	if step < minStep {
+		step = minStep
+	}
+	rangeParams := map[string]interface{}{
+		"start": strconv.FormatInt(options.Now.Add(-options.Lookback).Unix(), 10),
+		"end":   strconv.FormatInt(options.Now.Unix(), 10),
+		"step":  strconv.FormatFloat(step.Seconds(), 'f', -1, 64),
+		"stats": true,
+	}
+
+	report := &Report{
+		GeneratedAt: options.Now,
+		Lookback:    options.Lookback.String(),
+	}
+	g := &generator{ctx: ctx, now: options.Now, limit: make(chan struct{}, options.Concurrency)}
+	report.Namespaces = make([]TargetReport, len(options.Namespaces))
+	for i, namespace := range options.Namespaces {
+		params := common.MergeJSONMaps(map[string]interface{}{"namespace": regexp.QuoteMeta(namespace)}, rangeParams)
+		g.target(&report.Namespaces[i], namespace, namespaceResources, params)
+	}
+	report.Nodes = make([]TargetReport, len(options.Nodes))
+	for i, node := range options.Nodes {
+		// 노드 메트릭은 노드 이름(node)과 node-exporter 의 instance 라벨을 모두 사용
+		quoted := regexp.QuoteMeta(node)
+		params := common.MergeJSONMaps(map[string]interface{}{"node": quoted, "instance": quoted}, rangeParams)
+		g.target(&report.Nodes[i], node, nodeResources, params)
+	}
+	g.wait.Wait()
+	return report
+}
+
+// generator 리소스별 조회를 동시 조회 수 제한 내에서 실행하는 보고서 생성기
+type generator struct {
+	ctx   context.Context
+	now   time.Time
+	limit chan struct{}
+	wait  sync.WaitGroup
+}
+
+// target 네임스페이스 또는 노드 하나의 리소스별 용량 현황 조회를 시작하는 함수(결과는 리소스 순서대로 target 에 기록)
+func (g *generator) target(target *TargetReport, name string, resources []resource, bodyParams map[string]interface{}) {
+	*target = TargetReport{Name: name, Resources: make([]ResourceReport, len(resources))}
+	for i, r := range resources {
+		g.wait.Add(1)
+		go func(resourceReport *ResourceReport, r resource) {
+			defer g.wait.Done()
+			select {
+			case g.limit <- struct{}{}:
+				defer func() { <-g.limit }()
+				*resourceReport = generateResource(g.ctx, r, bodyParams, g.now)
+			case <-g.ctx.Done():
+				*resourceReport = ResourceReport{Resource: r.name, Status: StatusUnknown, Error: g.ctx.Err().Error()}
+			}
+		}(&target.Resources[i], r)
+	}
+}
+
+// generateResource 사용량 메트릭의 범위 조회 통계(stats)로 리소스의 용량 현황을 계산하는 함수
+func generateResource(ctx context.Context, r resource, bodyParams map[string]interface{}, now time.Time) ResourceReport {
+	resourceReport := ResourceReport{Resource: r.name, Status: StatusUnknown}
+
+	result, err := prometheus.GetMetricResultContext(ctx, r.metricKey, bodyParams)
+	if err != nil {
+		resourceReport.Error = err.Error()
+		return resourceReport
+	}
+	metricResponse, _ := result[string(r.metricKey)].(prometheus.MetricResponse)
+	resourceReport.Unit = metricResponse.Unit
+	stats, ok := metricResponse.Stats[prometheus.MetricDefinitions[r.metricKey].Label]
+	if !ok {
+		resourceReport.Error = "no data in lookback window"
+		return resourceReport
+	}
+
+	resourceReport.Used = stats.Last
+	if stats.Trend != nil {
+		resourceReport.GrowthPerDay = common.RoundFloat(stats.Trend.Slope*(24*time.Hour).Seconds(), 2)
+	}
+	if stats.Capacity == nil {
+		resourceReport.Status = StatusUnlimited
+		return resourceReport
+	}
+
+	capacity := *stats.Capacity
+	headroom := common.RoundFloat(capacity-stats.Last, 2)
+	headroomPercentage := common.RoundFloat(headroom/capacity*100, 2)
+	resourceReport.Capacity = &capacity
+	resourceReport.Headroom = &headroom
+	resourceReport.HeadroomPercentage = &headroomPercentage
+
+	resourceReport.Status = StatusOK
+	if stats.Exhaustion != nil {
+		saturatedAt := time.Unix(stats.Exhaustion.ExhaustedAt, 0)
+		resourceReport.SaturatedAt = &saturatedAt
+		switch remaining := saturatedAt.Sub(now); {
+		case remaining <= criticalDuration:
+			resourceReport.Status = StatusCritical
+		case remaining <= warningDuration:
+			resourceReport.Status = StatusWarning
+		}
+	}
+	if headroom <= 0 {
+		resourceReport.Status = StatusCritical
+	}
+	return resourceReport
+}
@@ -0,0 +1,166 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-practice/http-client/prometheus"
+)
+
+// stubSource 한 시간마다 0.5 씩 증가하여 현재 10 이 되는 사용량과 네임스페이스별 용량을 반환하는 메트릭 원천
+type stubSource struct {
+	now        time.Time
+	capacities map[string]string
+}
+
+func (s stubSource) Query(ctx context.Context, metricKey prometheus.MetricKey, bodyParams map[string]interface{}) (prometheus.MetricResponse, error) {
+	if bodyParams["start"] == nil {
+		return prometheus.MetricResponse{RawUsage: s.capacities[bodyParams["namespace"].(string)]}, nil
+	}
+	label := prometheus.MetricDefinitions[metricKey].Label
+	values := make([]interface{}, 0, 11)
+	for i := 0; i <= 10; i++ {
+		timestamp := s.now.Add(time.Duration(i-10) * time.Hour).Unix()
+		values = append(values, map[string]interface{}{"timestamp": float64(timestamp), label: 5 + float64(i)*0.5})
+	}
+	return prometheus.MetricResponse{Values: values}, nil
+}
+
+func TestGenerate(t *testing.T) {
+	now := time.Date(2022, 7, 28, 0, 0, 0, 0, time.UTC)
+	prometheus.MetricSources[prometheus.PrometheusSourceType] = stubSource{
+		now:        now,
+		capacities: map[string]string{"team-a": "100", "team-b": "20", "team-c": "10"},
+	}
+	defer delete(prometheus.MetricSources, prometheus.PrometheusSourceType)
+
+	report := Generate(context.Background(), Options{
+		Lookback:   10 * time.Hour,
+		Namespaces: []string{"team-a", "team-b", "team-c", "team-d"},
+		Now:        now,
+	})
+	if len(report.Namespaces) != 4 || len(report.Nodes) != 0 {
+		t.Fatalf("unexpected targets %+v", report)
+	}
+
+	tests := []struct {
+		namespace   string
+		status      Status
+		headroom    float64
+		saturatedAt string
+	}{
+		{"team-a", StatusWarning, 90, "2022-08-04T12:00:00Z"},
+		{"team-b", StatusCritical, 10, "2022-07-28T20:00:00Z"},
+		{"team-c", StatusCritical, 0, "2022-07-28T00:00:00Z"},
+		{"team-d", StatusUnlimited, 0, ""},
+	}
+	for i, test := range tests {
+		resource := report.Namespaces[i].Resources[0]
+		if report.Namespaces[i].Name != test.namespace || resource.Resource != "requests.cpu" {
+			t.Fatalf("unexpected target %s %s", report.Namespaces[i].Name, resource.Resource)
+		}
+		if resource.Status != test.status || resource.Used != 10 || resource.GrowthPerDay != 12 {
+			t.Errorf("%s: unexpected resource %+v", test.namespace, resource)
+		}
+		if test.saturatedAt == "" {
+			if resource.Headroom != nil || resource.SaturatedAt != nil {
+				t.Errorf("%s: expected no headroom without capacity, got %+v", test.namespace, resource)
+			}
+			continue
+		}
+		if resource.Headroom == nil || *resource.Headroom != test.headroom {
+			t.Errorf("%s: expected headroom %v, got %v", test.namespace, test.headroom, resource.Headroom)
+		}
+		if resource.SaturatedAt == nil || resource.SaturatedAt.UTC().Format(time.RFC3339) != test.saturatedAt {
+			t.Errorf("%s: expected saturation at %s, got %v", test.namespace, test.saturatedAt, resource.SaturatedAt)
+		}
+	}
+
+	var markdown bytes.Buffer
+	if err := report.WriteMarkdown(&markdown); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(markdown.String(), "| team-a | requests.cpu | 10 | 100 | 90 (90%) | +12 | 2022-08-04 | warning |") {
+		t.Errorf("unexpected markdown report\n%s", markdown.String())
+	}
+	var html bytes.Buffer
+	if err := report.WriteHTML(&html); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html.String(), `<tr class="critical"><td>team-b</td>`) {
+		t.Errorf("unexpected html report\n%s", html.String())
+	}
+}
+
+func TestParseLookback(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":    DefaultLookback,
+		"7d":  7 * 24 * time.Hour,
+		"12h": 12 * time.Hour,
+	}
+	for value, expected := range tests {
+		if lookback, err := ParseLookback(value); err != nil || lookback != expected {
+			t.Errorf("%q: expected %s, got %s (%v)", value, expected, lookback, err)
+		}
+	}
+	for _, value := range []string{"0d", "-1h", "week"} {
+		if _, err := ParseLookback(value); err == nil {
+			t.Errorf("%q: expected an error", value)
+		}
+	}
+}
+
+// failingSource 조회 기한까지 응답하지 않거나 여러 줄의 에러를 반환하는 메트릭 원천
+type failingSource struct {
+	inFlight    *int32
+	maxInFlight *int32
+}
+
+func (s failingSource) Query(ctx context.Context, metricKey prometheus.MetricKey, bodyParams map[string]interface{}) (prometheus.MetricResponse, error) {
+	n := atomic.AddInt32(s.inFlight, 1)
+	defer atomic.AddInt32(s.inFlight, -1)
+	for max := atomic.LoadInt32(s.maxInFlight); n > max && !atomic.CompareAndSwapInt32(s.maxInFlight, max, n); {
+		max = atomic.LoadInt32(s.maxInFlight)
+	}
+	if bodyParams["namespace"] == "team-a" {
+		return prometheus.MetricResponse{}, errors.New("bad query | status=400\nparse error")
+	}
+	<-ctx.Done()
+	return prometheus.MetricResponse{}, ctx.Err()
+}
+
+func TestGenerateTimeout(t *testing.T) {
+	var inFlight, maxInFlight int32
+	prometheus.MetricSources[prometheus.PrometheusSourceType] = failingSource{&inFlight, &maxInFlight}
+	defer delete(prometheus.MetricSources, prometheus.PrometheusSourceType)
+
+	report := Generate(context.Background(), Options{
+		Namespaces:  []string{"team-a", "team-b", "team-c"},
+		Timeout:     50 * time.Millisecond,
+		Concurrency: 2,
+	})
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 concurrent queries, got %d", maxInFlight)
+	}
+	for _, target := range report.Namespaces[1:] {
+		for _, resource := range target.Resources {
+			if resource.Status != StatusUnknown || resource.Error != context.DeadlineExceeded.Error() {
+				t.Errorf("%s: expected deadline exceeded, got %+v", target.Name, resource)
+			}
+		}
+	}
+
+	// 에러 메시지가 Markdown 표의 행을 깨뜨리지 않도록 변환
+	var markdown bytes.Buffer
+	if err := report.WriteMarkdown(&markdown); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(markdown.String(), "| unknown (bad query \\| status=400 parse error) |\n") {
+		t.Errorf("unexpected markdown report\n%s", markdown.String())
+	}
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"go-practice/http-client/kubernetes"
+	"go-practice/http-client/prometheus"
+)
+
+// authorize 요청자의 토큰으로 사용자를 확인하고 사용자가 파드를 조회할 수 있는 네임스페이스로 namespace 파라미터를 제한한다
+// 클러스터 전체 파드 조회 권한이 있는 사용자(관리자)는 요청을 그대로 허용하고,
+// 그 외 사용자는 클러스터 범위 메트릭(node_* 등)을 조회할 수 없다.
+func (s *Server) authorize(r *http.Request, metricKeys []prometheus.MetricKey, bodyParams map[string]interface{}) error {
+	ctx := r.Context()
+	user, err := kubernetes.ResolveUser(ctx, s.KubeClient, bearerToken(r))
+	if err != nil {
+		return err
+	}
+
+	isAdmin, err := kubernetes.CanGetPods(ctx, s.KubeClient, user, "")
+	if err != nil {
+		return err
+	}
+	if isAdmin {
+		return nil
+	}
+
+	for _, metricKey := range metricKeys {
+		if !prometheus.IsNamespaceScoped(metricKey) {
+			return &statusError{http.StatusForbidden, fmt.Sprintf("cluster-wide metric is not allowed, metricKey=%s", metricKey)}
+		}
+	}
+
+	allowedNamespaces, err := kubernetes.AllowedNamespaces(ctx, s.KubeClient, user)
+	if err != nil {
+		return err
+	}
+	namespace, err := restrictNamespace(bodyParams["namespace"], allowedNamespaces)
+	if err != nil {
+		return err
+	}
+	bodyParams["namespace"] = namespace
+	return nil
+}
+
+// restrictNamespace 요청한 namespace 패턴(프로메테우스 정규식)과 일치하는 허용 네임스페이스만으로 namespace 파라미터를 다시 작성하는 함수
+func restrictNamespace(requested interface{}, allowedNamespaces []string) (string, error) {
+	pattern := ".*"
+	if requested != nil {
+		var ok bool
+		if pattern, ok = requested.(string); !ok {
+			return "", &statusError{http.StatusBadRequest, fmt.Sprintf("invalid namespace parameter, namespace=%v", requested)}
+		}
+	}
+	permitted, err := matchNames("namespace", pattern, allowedNamespaces)
+	if err != nil {
+		return "", err
+	}
+	if len(permitted) == 0 {
+		return "", &statusError{http.StatusForbidden, fmt.Sprintf("no permitted namespace matches, namespace=%s", pattern)}
+	}
+	for i, namespace := range permitted {
+		permitted[i] = regexp.QuoteMeta(namespace)
+	}
+	return strings.Join(permitted, "|"), nil
+}
+
+// matchNames 이름 목록 중 패턴(프로메테우스 정규식과 같이 전체 일치)과 일치하는 이름만 반환하는 함수
+func matchNames(param string, pattern string, names []string) ([]string, error) {
+	matcher, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, &statusError{http.StatusBadRequest, fmt.Sprintf("invalid %s parameter, err=%s", param, err)}
+	}
+	matched := make([]string, 0, len(names))
+	for _, name := range names {
+		if matcher.MatchString(name) {
+			matched = append(matched, name)
+		}
+	}
+	return matched, nil
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"go-practice/http-client/kubernetes"
+	"go-practice/http-client/report"
+)
+
+const capacityReportAPIEndpoint = "/api/v1/reports/capacity"
+
+// handleCapacityReport 네임스페이스(ResourceQuota)와 노드의 용량 예측 보고서를 반환
+/* format 은 json(기본값), markdown, html 중 하나이며, namespace 와 node 는 대상 이름의 정규식
+ * GET /api/v1/reports/capacity?lookback=7d&namespace=team-.*&format=markdown
+ * => | Namespace | Resource | Used | Capacity | Headroom | Growth/day | Saturation | Status |
+ *    | team-a | requests.cpu | 1.5 Core | 4 Core | 2.5 Core (62.5%) | +0.1 Core | 2026-11-13 | warning |
+ */
+func (s *Server) handleCapacityReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, &statusError{http.StatusMethodNotAllowed, "method not allowed"})
+		return
+	}
+	query := r.URL.Query()
+
+	lookback, err := report.ParseLookback(query.Get("lookback"))
+	if err != nil {
+		writeError(w, &statusError{http.StatusBadRequest, err.Error()})
+		return
+	}
+	format := query.Get("format")
+	switch format {
+	case "":
+		format = "json"
+	case "json", "markdown", "html":
+	default:
+		writeError(w, &statusError{http.StatusBadRequest, fmt.Sprintf("invalid format, format=%s", format)})
+		return
+	}
+
+	namespaces, nodes, err := s.reportTargets(r, query.Get("namespace"), query.Get("node"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	capacityReport := report.Generate(r.Context(), report.Options{Lookback: lookback, Namespaces: namespaces, Nodes: nodes})
+	switch format {
+	case "markdown":
+		w.Header().Set("Content-Type", "text/markdown; charset=UTF-8")
+		err = capacityReport.WriteMarkdown(w)
+	case "html":
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		err = capacityReport.WriteHTML(w)
+	default:
+		writeJSON(w, http.StatusOK, capacityReport)
+	}
+	if err != nil {
+		fmt.Printf("failed to write capacity report, err=%s\n", err)
+	}
+}
+
+// reportTargets 사용자 권한 범위 내에서 패턴과 일치하는 보고서 대상 네임스페이스와 노드 목록을 반환한다
+// 노드는 클러스터 범위 리소스이므로 관리자만 조회할 수 있고, 그 외 사용자는 파드 조회 권한이 있는 네임스페이스만 포함한다.
+func (s *Server) reportTargets(r *http.Request, namespacePattern string, nodePattern string) ([]string, []string, error) {
+	ctx := r.Context()
+	user, err := kubernetes.ResolveUser(ctx, s.KubeClient, bearerToken(r))
+	if err != nil {
+		return nil, nil, err
+	}
+	isAdmin, err := kubernetes.CanGetPods(ctx, s.KubeClient, user, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var namespaces []string
+	if isAdmin {
+		namespaces, err = kubernetes.NamespaceNames(ctx, s.KubeClient)
+	} else {
+		namespaces, err = kubernetes.AllowedNamespaces(ctx, s.KubeClient, user)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if namespacePattern == "" {
+		namespacePattern = ".*"
+	}
+	if namespaces, err = matchNames("namespace", namespacePattern, namespaces); err != nil {
+		return nil, nil, err
+	}
+
+	if !isAdmin {
+		if nodePattern != "" {
+			return nil, nil, &statusError{http.StatusForbidden, "node report is not allowed"}
+		}
+		return namespaces, nil, nil
+	}
+	nodes, err := kubernetes.NodeNames(ctx, s.KubeClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	if nodePattern == "" {
+		nodePattern = ".*"
+	}
+	if nodes, err = matchNames("node", nodePattern, nodes); err != nil {
+		return nil, nil, err
+	}
+	return namespaces, nodes, nil
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-practice/http-client/prometheus"
+	"go-practice/http-client/report"
+)
+
+func TestHandleCapacityReport(t *testing.T) {
+	prometheus.MetricSources[prometheus.PrometheusSourceType] = stubSource{}
+	defer delete(prometheus.MetricSources, prometheus.PrometheusSourceType)
+	server := NewServer(newFakeClient())
+
+	tests := []struct {
+		name       string
+		token      string
+		query      string
+		status     int
+		namespaces []string
+	}{
+		{"no token", "", "", http.StatusUnauthorized, nil},
+		{"invalid lookback", "user-token", "?lookback=week", http.StatusBadRequest, nil},
+		{"invalid format", "user-token", "?format=pdf", http.StatusBadRequest, nil},
+		{"user permitted namespaces", "user-token", "", http.StatusOK, []string{"team-a", "team-b"}},
+		{"user namespace pattern", "user-token", "?namespace=team-(b|c)", http.StatusOK, []string{"team-b"}},
+		{"user node report", "user-token", "?node=.*", http.StatusForbidden, nil},
+		{"admin all namespaces", "admin-token", "?lookback=1d", http.StatusOK, []string{"team-a", "team-b", "team-c"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, capacityReportAPIEndpoint+test.query, nil)
+			if test.token != "" {
+				r.Header.Set("Authorization", "Bearer "+test.token)
+			}
+			w := httptest.NewRecorder()
+			server.Handler().ServeHTTP(w, r)
+			if w.Code != test.status {
+				t.Fatalf("expected status %d, got %d (%s)", test.status, w.Code, w.Body.String())
+			}
+			if test.namespaces == nil {
+				return
+			}
+
+			var capacityReport report.Report
+			if err := json.Unmarshal(w.Body.Bytes(), &capacityReport); err != nil {
+				t.Fatal(err)
+			}
+			namespaces := make([]string, 0, len(capacityReport.Namespaces))
+			for _, target := range capacityReport.Namespaces {
+				namespaces = append(namespaces, target.Name)
+			}
+			if strings.Join(namespaces, ",") != strings.Join(test.namespaces, ",") {
+				t.Errorf("expected namespaces %v, got %v", test.namespaces, namespaces)
+			}
+		})
+	}
+}
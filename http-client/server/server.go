@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go-practice/common"
+	"go-practice/http-client/kubernetes"
+	"go-practice/http-client/prometheus"
+
+	"golang.org/x/net/websocket"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+const (
+	metricsAPIEndpoint = "/api/v1/metrics"
+
+	shutdownTimeout = 10 * time.Second // 서버 종료 시 처리 중인 요청을 기다리는 최대 시간
+)
+
+// Error API 에러 응답
+type Error struct {
+	Message string `json:"message"`
+}
+
+// statusError 응답 상태 코드를 포함하는 에러
+type statusError struct {
+	status  int
+	message string
+}
+
+func (e *statusError) Error() string {
+	return e.message
+}
+
+// Server 메트릭 조회 API 서버
+type Server struct {
+	KubeClient          k8s.Interface // 사용자 인증(TokenReview) 및 권한 확인(SubjectAccessReview)에 사용하는 클라이언트
+	ReauthorizeInterval time.Duration // 구독 연결의 토큰 및 권한 재확인 주기
+	hub                 *subscriptionHub
+	tickets             *ticketStore
+	closed              chan struct{}
+	closeOnce           sync.Once
+}
+
+// NewServer 메트릭 조회 API 서버를 생성한다
+func NewServer(kubeClient k8s.Interface) *Server {
+	return &Server{
+		KubeClient:          kubeClient,
+		ReauthorizeInterval: defaultReauthorizeInterval,
+		hub:                 newSubscriptionHub(),
+		tickets:             newTicketStore(),
+		closed:              make(chan struct{}),
+	}
+}
+
+// Handler 메트릭 조회 API 의 http.Handler 를 반환한다
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(metricsAPIEndpoint, s.handleMetrics)
+	mux.HandleFunc(streamAPIEndpoint, s.handleStream)
+	mux.HandleFunc(ticketAPIEndpoint, s.handleTicket)
+	mux.HandleFunc(capacityReportAPIEndpoint, s.handleCapacityReport)
+	mux.Handle(webSocketAPIEndpoint, websocket.Handler(s.handleWebSocket))
+	return mux
+}
+
+// Close 실행 중인 구독 연결을 종료하고 조회 루프를 모두 중지한다
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		s.hub.close()
+	})
+}
+
+// ListenAndServe 주소에서 메트릭 조회 API 서버를 시작하고, ctx 가 종료되면 서버를 정상 종료한다
+// 구독 연결은 유휴 상태가 되지 않으므로 종료를 시작할 때 Close 로 구독을 먼저 끊는다.
+func (s *Server) ListenAndServe(ctx context.Context, address string) error {
+	httpServer := &http.Server{Addr: address, Handler: s.Handler()}
+	httpServer.RegisterOnShutdown(s.Close)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.ListenAndServe()
+	}()
+	select {
+	case err := <-errs:
+		s.Close()
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shutdown metric API server, err=%s", err)
+	}
+	return nil
+}
+
+// handleMetrics 요청한 메트릭 키 목록을 사용자 권한 범위 내에서 조회하여 반환
+/* 요청 본문은 client.go 의 bodyParams 와 동일한 형태
+ * {"metricKeys":["container_cpu"],"namespace":"team-a|team-b","start":"1658970600","end":"1658974200","step":"120"}
+ */
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, &statusError{http.StatusMethodNotAllowed, "method not allowed"})
+		return
+	}
+
+	bodyParams := make(map[string]interface{})
+	if err := json.NewDecoder(r.Body).Decode(&bodyParams); err != nil {
+		writeError(w, &statusError{http.StatusBadRequest, fmt.Sprintf("invalid request body, err=%s", err)})
+		return
+	}
+
+	metricKeys, err := parseMetricKeys(bodyParams)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err = validateParams(bodyParams); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err = s.authorize(r, metricKeys, bodyParams); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	result, err := getMetricResults(r.Context(), metricKeys, bodyParams)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// validateParams 범위 조회 파라미터(start, end, step)와 tz 를 해석할 수 있고 쿼리 파라미터(operator, 라벨 값)가 올바른지 확인하는 함수
+// 상대 시간(now-1h)은 조회할 때마다 다시 해석되도록 요청 파라미터는 변환하지 않는다.
+func validateParams(bodyParams map[string]interface{}) error {
+	if _, _, err := prometheus.NormalizeTimeParams(bodyParams, time.Now()); err != nil {
+		return &statusError{http.StatusBadRequest, err.Error()}
+	}
+	if err := prometheus.ValidateQueryParams(bodyParams); err != nil {
+		return &statusError{http.StatusBadRequest, err.Error()}
+	}
+	return nil
+}
+
+// parseMetricKeys 요청 파라미터의 metricKeys 를 정의된 메트릭 키 목록으로 변환하는 함수
+func parseMetricKeys(bodyParams map[string]interface{}) ([]prometheus.MetricKey, error) {
+	rawKeys, ok := bodyParams["metricKeys"].([]interface{})
+	if !ok || len(rawKeys) == 0 {
+		return nil, &statusError{http.StatusBadRequest, "metricKeys is required"}
+	}
+	metricKeys := make([]prometheus.MetricKey, 0, len(rawKeys))
+	for _, rawKey := range rawKeys {
+		key, ok := rawKey.(string)
+		if !ok {
+			return nil, &statusError{http.StatusBadRequest, fmt.Sprintf("invalid metric key, metricKey=%v", rawKey)}
+		}
+		if _, isMetric := prometheus.MetricDefinitions[prometheus.MetricKey(key)]; !isMetric {
+			return nil, &statusError{http.StatusBadRequest, fmt.Sprintf("undefined metric key, metricKey=%s", key)}
+		}
+		metricKeys = append(metricKeys, prometheus.MetricKey(key))
+	}
+	return metricKeys, nil
+}
+
+// getMetricResults 메트릭 키 목록의 조회 결과를 하나의 맵으로 병합하여 반환하는 함수
+func getMetricResults(ctx context.Context, metricKeys []prometheus.MetricKey, bodyParams map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	for _, metricKey := range metricKeys {
+		metricResult, err := prometheus.GetMetricResultContext(ctx, metricKey, bodyParams)
+		if err != nil {
+			return nil, &statusError{http.StatusBadGateway, err.Error()}
+		}
+		result = common.DeepMerge(nil, result, metricResult)
+	}
+	return result, nil
+}
+
+// bearerToken Authorization 헤더의 베어러 토큰을 반환하는 함수
+func bearerToken(r *http.Request) string {
+	authorization := r.Header.Get("Authorization")
+	if len(authorization) > len("Bearer ") && strings.EqualFold(authorization[:len("Bearer ")], "Bearer ") {
+		return strings.TrimSpace(authorization[len("Bearer "):])
+	}
+	return ""
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		writeJSON(w, statusErr.status, Error{Message: statusErr.message})
+		return
+	}
+	if errors.Is(err, kubernetes.ErrUnauthenticated) {
+		writeJSON(w, http.StatusUnauthorized, Error{Message: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusInternalServerError, Error{Message: err.Error()})
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-practice/http-client/prometheus"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// stubSource 요청 파라미터의 namespace 를 사용량으로 반환하는 메트릭 원천
+type stubSource struct{}
+
+func (stubSource) Query(ctx context.Context, metricKey prometheus.MetricKey, bodyParams map[string]interface{}) (prometheus.MetricResponse, error) {
+	namespace, _ := bodyParams["namespace"].(string)
+	return prometheus.MetricResponse{Usage: namespace}, nil
+}
+
+func newFakeClient() *fake.Clientset {
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-c"}},
+	)
+	users := map[string]string{"admin-token": "admin", "user-token": "alice"}
+	permissions := map[string]map[string]bool{
+		"admin": {"": true},
+		"alice": {"team-a": true, "team-b": true},
+	}
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if username, ok := users[review.Spec.Token]; ok {
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User:          authenticationv1.UserInfo{Username: username},
+			}
+		}
+		return true, review, nil
+	})
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		permission := permissions[review.Spec.User]
+		review.Status.Allowed = permission[""] || permission[review.Spec.ResourceAttributes.Namespace]
+		return true, review, nil
+	})
+	return client
+}
+
+func request(t *testing.T, server *Server, token string, body string) (int, map[string]interface{}) {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, metricsAPIEndpoint, strings.NewReader(body))
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	server.Handler().ServeHTTP(w, r)
+
+	result := make(map[string]interface{})
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("invalid response body %q", w.Body.String())
+	}
+	return w.Code, result
+}
+
+func TestHandleMetricsAuthorization(t *testing.T) {
+	prometheus.MetricSources[prometheus.PrometheusSourceType] = stubSource{}
+	defer delete(prometheus.MetricSources, prometheus.PrometheusSourceType)
+	server := NewServer(newFakeClient())
+
+	tests := []struct {
+		name      string
+		token     string
+		body      string
+		status    int
+		namespace string
+	}{
+		{"no token", "", `{"metricKeys":["container_cpu"]}`, http.StatusUnauthorized, ""},
+		{"invalid token", "unknown", `{"metricKeys":["container_cpu"]}`, http.StatusUnauthorized, ""},
+		{"undefined metric", "user-token", `{"metricKeys":["unknown"]}`, http.StatusBadRequest, ""},
+		{"admin cluster-wide metric", "admin-token", `{"metricKeys":["node_cpu"]}`, http.StatusOK, ""},
+		{"admin namespace unchanged", "admin-token", `{"metricKeys":["container_cpu"],"namespace":".*"}`, http.StatusOK, ".*"},
+		{"user cluster-wide metric", "user-token", `{"metricKeys":["node_cpu"]}`, http.StatusForbidden, ""},
+		{"user persistent volumes", "user-token", `{"metricKeys":["number_of_volume"]}`, http.StatusForbidden, ""},
+		{"user all namespaces", "user-token", `{"metricKeys":["container_cpu"],"namespace":".*"}`, http.StatusOK, "team-a|team-b"},
+		{"user default namespace", "user-token", `{"metricKeys":["container_cpu"]}`, http.StatusOK, "team-a|team-b"},
+		{"user partially permitted", "user-token", `{"metricKeys":["container_cpu"],"namespace":"team-a|team-c"}`, http.StatusOK, "team-a"},
+		{"user forbidden namespace", "user-token", `{"metricKeys":["container_cpu"],"namespace":"team-c"}`, http.StatusForbidden, ""},
+		{"user invalid namespace", "user-token", `{"metricKeys":["container_cpu"],"namespace":"("}`, http.StatusBadRequest, ""},
+		{"invalid timezone", "admin-token", `{"metricKeys":["container_cpu"],"tz":"Mars/Olympus"}`, http.StatusBadRequest, ""},
+		{"invalid step", "admin-token", `{"metricKeys":["container_cpu"],"start":"now-1h","end":"now","step":"0"}`, http.StatusBadRequest, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, result := request(t, server, test.token, test.body)
+			if status != test.status {
+				t.Fatalf("expected status %d, got %d (%v)", test.status, status, result)
+			}
+			if test.namespace != "" {
+				usage := result["container_cpu"].(map[string]interface{})["usage"]
+				if usage != test.namespace {
+					t.Errorf("expected namespace %q, got %q", test.namespace, usage)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleMetricsQueryInjection(t *testing.T) {
+	queries := make(chan string, 1)
+	prometheusServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries <- r.URL.Query().Get("query")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1657560872.452,"1"]}]}}`))
+	}))
+	defer prometheusServer.Close()
+	prometheus.MetricSources[prometheus.PrometheusSourceType] = &prometheus.PrometheusSource{RequestURL: prometheusServer.URL, Version: "2.30"}
+	defer delete(prometheus.MetricSources, prometheus.PrometheusSourceType)
+	server := NewServer(newFakeClient())
+
+	// 라벨 값의 따옴표로 셀렉터를 닫고 다른 네임스페이스를 조회하려는 요청은 라벨 값 안에 이스케이프되어 남는다
+	status, result := request(t, server, "user-token", `{"metricKeys":["container_memory"],"pod":".*\"}) or sum(container_memory_usage_bytes{namespace=\"kube-system"}`)
+	if status != http.StatusOK {
+		t.Fatalf("unexpected status %d (%v)", status, result)
+	}
+	expected := `sum(container_memory_working_set_bytes{cluster="",container!="",namespace=~"team-a|team-b",pod=~".*\"}) or sum(container_memory_usage_bytes{namespace=\"kube-system"})`
+	if query := <-queries; query != expected {
+		t.Errorf("unexpected query\n got %s\nwant %s", query, expected)
+	}
+
+	status, _ = request(t, server, "user-token", `{"metricKeys":["container_memory"],"pod":"web\\\"})"}`)
+	if query := <-queries; status != http.StatusOK || !strings.Contains(query, `pod=~"web\\\"})"}`) {
+		t.Errorf("unexpected query %s", query)
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"operator expression", `{"metricKeys":["quota_limit_cpu_hard"],"operator":"count(kube_pod_info) or sum"}`},
+		{"unknown operator", `{"metricKeys":["quota_limit_cpu_hard"],"operator":"topk"}`},
+		{"non-string label", `{"metricKeys":["container_memory"],"pod":{"namespace":"kube-system"}}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if status, result := request(t, server, "user-token", test.body); status != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d (%v)", http.StatusBadRequest, status, result)
+			}
+		})
+	}
+}
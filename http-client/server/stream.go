@@ -0,0 +1,251 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-practice/http-client/prometheus"
+
+	"golang.org/x/net/websocket"
+)
+
+const (
+	streamAPIEndpoint    = "/api/v1/metrics/stream"
+	webSocketAPIEndpoint = "/api/v1/metrics/ws"
+
+	streamKeepAliveInterval    = 15 * time.Second // SSE 연결 유지를 위한 주석 이벤트 전송 주기
+	defaultReauthorizeInterval = 5 * time.Minute  // 구독 연결의 토큰 및 권한 재확인 주기 기본값
+)
+
+// subscriptionRequest 권한 확인을 마친 구독 요청
+type subscriptionRequest struct {
+	metricKeys []prometheus.MetricKey
+	interval   time.Duration
+	bodyParams map[string]interface{} // 사용자 권한 범위로 제한한 조회 파라미터
+	requested  map[string]interface{} // 권한 재확인에 사용하는 제한 전 조회 파라미터
+}
+
+// handleStream 요청한 메트릭을 구독하여 주기마다 Server-Sent Events 로 전달
+/* EventSource 는 요청 본문과 헤더를 지정할 수 없으므로 조회 조건은 쿼리 스트링으로 전달한다
+ * 토큰은 URL 에 넣지 않고 Authorization 헤더 또는 /api/v1/metrics/ticket 에서 발급한 일회용 ticket 으로 전달한다
+ * GET /api/v1/metrics/stream?metricKeys=container_cpu,container_memory&namespace=team-a&interval=10s&ticket=5f0c...
+ * => event: metrics
+ *    data: {"timestamp":1658974200,"result":{"container_cpu":{...},"container_memory":{...}}}
+ */
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, &statusError{http.StatusMethodNotAllowed, "method not allowed"})
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, &statusError{http.StatusInternalServerError, "streaming is not supported"})
+		return
+	}
+
+	if err := s.redeemTicket(r); err != nil {
+		writeError(w, err)
+		return
+	}
+	query := r.URL.Query()
+	bodyParams := make(map[string]interface{})
+	for key := range query {
+		if key != "ticket" {
+			bodyParams[key] = query.Get(key)
+		}
+	}
+	bodyParams["metricKeys"] = splitMetricKeys(query["metricKeys"])
+
+	request, err := s.prepareSubscription(r, bodyParams)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	t, sub := s.hub.subscribe(request.metricKeys, request.bodyParams, request.interval)
+	defer s.hub.unsubscribe(t, sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAliveInterval)
+	defer keepAlive.Stop()
+	reauthorize := time.NewTicker(s.ReauthorizeInterval)
+	defer reauthorize.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.closed:
+			return
+		case <-reauthorize.C:
+			if err = s.reauthorize(r, request); err != nil {
+				data, _ := json.Marshal(metricUpdate{Timestamp: time.Now().Unix(), Error: err.Error()})
+				_, _ = fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+				flusher.Flush()
+				return
+			}
+		case <-keepAlive.C:
+			if _, err = fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case update := <-sub.updates:
+			event := "metrics"
+			if update.Error != "" {
+				event = "error"
+			}
+			data, _ := json.Marshal(update)
+			if _, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// handleWebSocket 첫 메시지로 받은 조회 조건의 메트릭을 구독하여 주기마다 WebSocket 으로 전달
+/* 토큰은 Authorization 헤더 또는 /api/v1/metrics/ticket 에서 발급한 일회용 ticket 으로 전달한다(ws://.../api/v1/metrics/ws?ticket=5f0c...)
+ * 첫 메시지는 metrics API 의 요청 본문에 interval 을 추가한 형태
+ * {"metricKeys":["container_cpu"],"namespace":"team-a","interval":"10s"}
+ * => {"timestamp":1658974200,"result":{"container_cpu":{...}}}
+ */
+func (s *Server) handleWebSocket(ws *websocket.Conn) {
+	defer func() {
+		_ = ws.Close()
+	}()
+	r := ws.Request()
+	if err := s.redeemTicket(r); err != nil {
+		_ = websocket.JSON.Send(ws, Error{Message: err.Error()})
+		return
+	}
+
+	bodyParams := make(map[string]interface{})
+	if err := websocket.JSON.Receive(ws, &bodyParams); err != nil {
+		_ = websocket.JSON.Send(ws, Error{Message: fmt.Sprintf("invalid subscription request, err=%s", err)})
+		return
+	}
+	request, err := s.prepareSubscription(r, bodyParams)
+	if err != nil {
+		_ = websocket.JSON.Send(ws, Error{Message: err.Error()})
+		return
+	}
+
+	t, sub := s.hub.subscribe(request.metricKeys, request.bodyParams, request.interval)
+	defer s.hub.unsubscribe(t, sub)
+
+	// 클라이언트가 연결을 종료하면 수신이 실패하므로 이를 통해 구독을 해제
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var discard interface{}
+		for websocket.JSON.Receive(ws, &discard) == nil {
+		}
+	}()
+
+	reauthorize := time.NewTicker(s.ReauthorizeInterval)
+	defer reauthorize.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-s.closed:
+			return
+		case <-reauthorize.C:
+			if err = s.reauthorize(r, request); err != nil {
+				_ = websocket.JSON.Send(ws, Error{Message: err.Error()})
+				return
+			}
+		case update := <-sub.updates:
+			if err = websocket.JSON.Send(ws, update); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// prepareSubscription 구독 요청의 갱신 주기를 분리하고 메트릭 키 검증 및 사용자 권한에 따른 파라미터 제한을 적용한다
+func (s *Server) prepareSubscription(r *http.Request, bodyParams map[string]interface{}) (*subscriptionRequest, error) {
+	interval, err := parseInterval(bodyParams["interval"])
+	if err != nil {
+		return nil, err
+	}
+	delete(bodyParams, "interval")
+
+	metricKeys, err := parseMetricKeys(bodyParams)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateParams(bodyParams); err != nil {
+		return nil, err
+	}
+	requested := copyParams(bodyParams)
+	if err = s.authorize(r, metricKeys, bodyParams); err != nil {
+		return nil, err
+	}
+	return &subscriptionRequest{metricKeys: metricKeys, interval: interval, bodyParams: bodyParams, requested: requested}, nil
+}
+
+// reauthorize 구독 중인 사용자의 토큰과 권한을 다시 확인하는 함수
+// 토큰이 만료되었거나 권한 변경으로 허용 네임스페이스 범위가 달라진 경우 에러를 반환하여 구독을 종료한다.
+func (s *Server) reauthorize(r *http.Request, request *subscriptionRequest) error {
+	bodyParams := copyParams(request.requested)
+	if err := s.authorize(r, request.metricKeys, bodyParams); err != nil {
+		return err
+	}
+	if bodyParams["namespace"] != request.bodyParams["namespace"] {
+		return &statusError{http.StatusForbidden, "permitted namespaces changed, subscribe again"}
+	}
+	return nil
+}
+
+// copyParams 권한 확인으로 변경되지 않도록 요청 파라미터를 복사하는 함수
+func copyParams(bodyParams map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(bodyParams))
+	for key, value := range bodyParams {
+		copied[key] = value
+	}
+	return copied
+}
+
+// parseInterval 갱신 주기(10s 와 같은 duration 또는 초 단위 숫자)를 파싱하는 함수
+func parseInterval(value interface{}) (time.Duration, error) {
+	var interval time.Duration
+	switch v := value.(type) {
+	case nil:
+		return defaultSubscriptionInterval, nil
+	case float64:
+		interval = time.Duration(v * float64(time.Second))
+	case string:
+		if seconds, err := strconv.ParseFloat(v, 64); err == nil {
+			interval = time.Duration(seconds * float64(time.Second))
+		} else if interval, err = time.ParseDuration(v); err != nil {
+			return 0, &statusError{http.StatusBadRequest, fmt.Sprintf("invalid interval, interval=%s", v)}
+		}
+	default:
+		return 0, &statusError{http.StatusBadRequest, fmt.Sprintf("invalid interval, interval=%v", v)}
+	}
+	if interval < minSubscriptionInterval {
+		return 0, &statusError{http.StatusBadRequest, fmt.Sprintf("interval must be at least %s", minSubscriptionInterval)}
+	}
+	return interval, nil
+}
+
+// splitMetricKeys 쿼리 스트링의 metricKeys(반복 또는 콤마 구분)를 요청 본문과 같은 형태로 변환하는 함수
+func splitMetricKeys(values []string) []interface{} {
+	metricKeys := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		for _, key := range strings.Split(value, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				metricKeys = append(metricKeys, key)
+			}
+		}
+	}
+	return metricKeys
+}
@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"go-practice/http-client/prometheus"
+)
+
+const (
+	defaultSubscriptionInterval = 10 * time.Second // 구독 갱신 주기 기본값
+	minSubscriptionInterval     = 5 * time.Second  // 구독 갱신 주기 최소값(프로메테우스 부하 제한)
+	defaultSubscriptionIdle     = time.Minute      // 구독자가 없는 조회 루프를 정리하기까지의 대기 시간
+)
+
+// metricUpdate 구독자에게 전달하는 메트릭 갱신값
+type metricUpdate struct {
+	Timestamp int64                  `json:"timestamp"`
+	Result    map[string]interface{} `json:"result,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// subscriber 구독자, 갱신값 채널은 최신 값 하나만 보관하여 느린 구독자가 조회 루프를 막지 않도록 한다
+type subscriber struct {
+	updates chan metricUpdate
+}
+
+// topic 동일한 조회 조건(메트릭 키, 파라미터, 주기)을 공유하는 구독 단위
+type topic struct {
+	key         string
+	metricKeys  []prometheus.MetricKey
+	bodyParams  map[string]interface{}
+	interval    time.Duration
+	subscribers map[*subscriber]struct{}
+	last        *metricUpdate
+	idleTimer   *time.Timer
+	ctx         context.Context // 조회 루프와 진행 중인 조회를 정리(idle, 서버 종료)할 때 취소
+	cancel      context.CancelFunc
+}
+
+// subscriptionHub 구독을 조회 조건별 topic 으로 묶어 topic 당 하나의 조회 루프만 실행하는 구독 관리자
+type subscriptionHub struct {
+	mu          sync.Mutex
+	topics      map[string]*topic
+	idleTimeout time.Duration
+	query       func(ctx context.Context, metricKeys []prometheus.MetricKey, bodyParams map[string]interface{}) (map[string]interface{}, error)
+}
+
+func newSubscriptionHub() *subscriptionHub {
+	return &subscriptionHub{
+		topics:      make(map[string]*topic),
+		idleTimeout: defaultSubscriptionIdle,
+		query:       getMetricResults,
+	}
+}
+
+// topicKey 조회 조건을 정규화하여 topic 키를 생성하는 함수(json.Marshal 은 맵 키를 정렬)
+func topicKey(metricKeys []prometheus.MetricKey, bodyParams map[string]interface{}, interval time.Duration) string {
+	keys := make([]string, len(metricKeys))
+	for i, metricKey := range metricKeys {
+		keys[i] = string(metricKey)
+	}
+	sort.Strings(keys)
+	params := make(map[string]interface{}, len(bodyParams))
+	for key, value := range bodyParams {
+		if key != "metricKeys" {
+			params[key] = value
+		}
+	}
+	bytes, _ := json.Marshal(map[string]interface{}{"metricKeys": keys, "params": params, "interval": interval.String()})
+	return string(bytes)
+}
+
+// subscribe 조회 조건에 해당하는 topic 에 구독자를 등록하고, topic 이 없으면 조회 루프를 시작한다
+func (h *subscriptionHub) subscribe(metricKeys []prometheus.MetricKey, bodyParams map[string]interface{},
+	interval time.Duration) (*topic, *subscriber) {
+	key := topicKey(metricKeys, bodyParams, interval)
+	s := &subscriber{updates: make(chan metricUpdate, 1)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	t, ok := h.topics[key]
+	if !ok {
+		t = &topic{
+			key:         key,
+			metricKeys:  metricKeys,
+			bodyParams:  bodyParams,
+			interval:    interval,
+			subscribers: make(map[*subscriber]struct{}),
+		}
+		t.ctx, t.cancel = context.WithCancel(context.Background())
+		h.topics[key] = t
+		go h.run(t)
+	}
+	if t.idleTimer != nil {
+		t.idleTimer.Stop()
+		t.idleTimer = nil
+	}
+	t.subscribers[s] = struct{}{}
+	// 새 구독자는 다음 주기를 기다리지 않고 마지막 값을 바로 받는다
+	if t.last != nil {
+		s.updates <- *t.last
+	}
+	return t, s
+}
+
+// unsubscribe 구독자를 해제하고, 구독자가 없는 topic 은 idleTimeout 이후 정리한다
+func (h *subscriptionHub) unsubscribe(t *topic, s *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(t.subscribers, s)
+	if len(t.subscribers) == 0 && t.idleTimer == nil {
+		t.idleTimer = time.AfterFunc(h.idleTimeout, func() {
+			h.removeIdle(t)
+		})
+	}
+}
+
+// removeIdle 구독자가 없는 topic 의 조회 루프와 진행 중인 조회를 중지하고 제거한다
+func (h *subscriptionHub) removeIdle(t *topic) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(t.subscribers) != 0 || h.topics[t.key] != t {
+		return
+	}
+	delete(h.topics, t.key)
+	t.cancel()
+}
+
+// close 모든 topic 의 조회 루프와 진행 중인 조회를 중지한다
+func (h *subscriptionHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for key, t := range h.topics {
+		if t.idleTimer != nil {
+			t.idleTimer.Stop()
+		}
+		delete(h.topics, key)
+		t.cancel()
+	}
+}
+
+// run topic 의 조회 루프, 주기마다 한 번 조회하여 모든 구독자에게 전달한다
+func (h *subscriptionHub) run(t *topic) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		h.publish(t)
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish 조회 결과를 구독자에게 전달하는 함수, 구독자 채널이 가득 찬 경우 이전 값을 버리고 최신 값으로 대체한다
+func (h *subscriptionHub) publish(t *topic) {
+	h.mu.Lock()
+	idle := len(t.subscribers) == 0
+	h.mu.Unlock()
+	if idle {
+		return
+	}
+
+	update := metricUpdate{Timestamp: time.Now().Unix()}
+	result, err := h.query(t.ctx, t.metricKeys, t.bodyParams)
+	if t.ctx.Err() != nil {
+		// 정리 중인 topic 의 취소된 조회 결과는 전달하지 않는다
+		return
+	}
+	if err != nil {
+		update.Error = err.Error()
+	} else {
+		update.Result = result
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	t.last = &update
+	for s := range t.subscribers {
+		select {
+		case s.updates <- update:
+		default:
+			select {
+			case <-s.updates:
+			default:
+			}
+			s.updates <- update
+		}
+	}
+}
+
+// count 실행 중인 topic 수와 전체 구독자 수를 반환한다
+func (h *subscriptionHub) count() (topics int, subscribers int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, t := range h.topics {
+		subscribers += len(t.subscribers)
+	}
+	return len(h.topics), subscribers
+}
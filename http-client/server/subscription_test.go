@@ -0,0 +1,379 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-practice/http-client/prometheus"
+
+	"golang.org/x/net/websocket"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// issueTicket 토큰으로 구독 연결에 사용할 일회용 티켓을 발급받는 함수
+func issueTicket(t *testing.T, baseURL string, token string) string {
+	t.Helper()
+	request, _ := http.NewRequest(http.MethodPost, baseURL+ticketAPIEndpoint, nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	var issued struct {
+		Ticket string `json:"ticket"`
+	}
+	if err = json.NewDecoder(response.Body).Decode(&issued); err != nil || issued.Ticket == "" {
+		t.Fatalf("failed to issue ticket, status=%d, err=%v", response.StatusCode, err)
+	}
+	return issued.Ticket
+}
+
+func newCountingHub(calls *int32) *subscriptionHub {
+	hub := newSubscriptionHub()
+	hub.query = func(ctx context.Context, metricKeys []prometheus.MetricKey, bodyParams map[string]interface{}) (map[string]interface{}, error) {
+		n := atomic.AddInt32(calls, 1)
+		return map[string]interface{}{"call": n}, nil
+	}
+	return hub
+}
+
+func TestSubscriptionHubSharesTopic(t *testing.T) {
+	var calls int32
+	hub := newCountingHub(&calls)
+	defer hub.close()
+
+	keys := []prometheus.MetricKey{prometheus.ContainerCpu, prometheus.ContainerMemory}
+	reversed := []prometheus.MetricKey{prometheus.ContainerMemory, prometheus.ContainerCpu}
+	topic1, sub1 := hub.subscribe(keys, map[string]interface{}{"namespace": "team-a"}, time.Hour)
+	<-sub1.updates
+	topic2, sub2 := hub.subscribe(reversed, map[string]interface{}{"namespace": "team-a"}, time.Hour)
+	topic3, _ := hub.subscribe(keys, map[string]interface{}{"namespace": "team-b"}, time.Hour)
+
+	if topic1 != topic2 {
+		t.Error("expected identical queries to share a topic")
+	}
+	if topic1 == topic3 {
+		t.Error("expected different parameters to use a separate topic")
+	}
+	// 이미 조회된 topic 의 새 구독자는 추가 조회 없이 마지막 값을 받는다
+	update := <-sub2.updates
+	if update.Result["call"] != int32(1) {
+		t.Errorf("expected the cached update, got %v", update.Result)
+	}
+	if topics, subscribers := hub.count(); topics != 2 || subscribers != 3 {
+		t.Errorf("expected 2 topics and 3 subscribers, got %d and %d", topics, subscribers)
+	}
+}
+
+func TestSubscriptionHubBackpressure(t *testing.T) {
+	var calls int32
+	hub := newCountingHub(&calls)
+	defer hub.close()
+
+	topic, sub := hub.subscribe([]prometheus.MetricKey{prometheus.ContainerCpu}, map[string]interface{}{}, time.Hour)
+	<-sub.updates
+	// 구독자가 읽지 않아도 조회 루프는 막히지 않고 최신 값만 남긴다
+	for i := 0; i < 5; i++ {
+		hub.publish(topic)
+	}
+	update := <-sub.updates
+	if update.Result["call"] != atomic.LoadInt32(&calls) {
+		t.Errorf("expected the latest update %d, got %v", calls, update.Result["call"])
+	}
+	select {
+	case stale := <-sub.updates:
+		t.Errorf("expected no buffered update, got %v", stale)
+	default:
+	}
+}
+
+func TestSubscriptionHubIdleCleanup(t *testing.T) {
+	var calls int32
+	hub := newCountingHub(&calls)
+	hub.idleTimeout = 10 * time.Millisecond
+	defer hub.close()
+
+	topic, sub := hub.subscribe([]prometheus.MetricKey{prometheus.ContainerCpu}, map[string]interface{}{}, time.Hour)
+	hub.unsubscribe(topic, sub)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if topics, _ := hub.count(); topics == 0 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("expected idle topic to be removed")
+}
+
+func TestSubscriptionHubCancelsQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(hub *subscriptionHub, t *topic, s *subscriber)
+	}{
+		{"close", func(hub *subscriptionHub, t *topic, s *subscriber) { hub.close() }},
+		{"idle", func(hub *subscriptionHub, t *topic, s *subscriber) { hub.unsubscribe(t, s) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			started := make(chan struct{})
+			canceled := make(chan error, 1)
+			hub := newSubscriptionHub()
+			hub.idleTimeout = 10 * time.Millisecond
+			// 프로메테우스가 응답하지 않는 조회는 topic 을 정리할 때 취소되어야 한다
+			hub.query = func(ctx context.Context, metricKeys []prometheus.MetricKey, bodyParams map[string]interface{}) (map[string]interface{}, error) {
+				close(started)
+				<-ctx.Done()
+				canceled <- ctx.Err()
+				return nil, ctx.Err()
+			}
+			defer hub.close()
+
+			topic, sub := hub.subscribe([]prometheus.MetricKey{prometheus.ContainerCpu}, map[string]interface{}{}, time.Hour)
+			<-started
+			test.stop(hub, topic, sub)
+			select {
+			case err := <-canceled:
+				if err != context.Canceled {
+					t.Errorf("expected %v, got %v", context.Canceled, err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("expected the in-flight query to be canceled")
+			}
+			select {
+			case update := <-sub.updates:
+				t.Errorf("expected no update from a canceled query, got %v", update)
+			default:
+			}
+		})
+	}
+}
+
+func TestHandleStream(t *testing.T) {
+	prometheus.MetricSources[prometheus.PrometheusSourceType] = stubSource{}
+	defer delete(prometheus.MetricSources, prometheus.PrometheusSourceType)
+	server := NewServer(newFakeClient())
+	defer server.Close()
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	response, err := http.Get(httpServer.URL + streamAPIEndpoint + "?metricKeys=container_cpu&interval=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status %d for short interval, got %d", http.StatusBadRequest, response.StatusCode)
+	}
+
+	response, err = http.Get(httpServer.URL + streamAPIEndpoint + "?metricKeys=container_cpu&namespace=.*&ticket=" + issueTicket(t, httpServer.URL, "user-token"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if response.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected content type %s", response.Header.Get("Content-Type"))
+	}
+
+	reader := bufio.NewReader(response.Body)
+	event, _ := reader.ReadString('\n')
+	data, _ := reader.ReadString('\n')
+	if event != "event: metrics\n" || !strings.HasPrefix(data, "data: ") {
+		t.Fatalf("unexpected event %q %q", event, data)
+	}
+	var update metricUpdate
+	if err = json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &update); err != nil {
+		t.Fatal(err)
+	}
+	usage := update.Result["container_cpu"].(map[string]interface{})["usage"]
+	if usage != "team-a|team-b" {
+		t.Errorf("expected namespace restricted to team-a|team-b, got %v", usage)
+	}
+}
+
+func TestHandleWebSocket(t *testing.T) {
+	prometheus.MetricSources[prometheus.PrometheusSourceType] = stubSource{}
+	defer delete(prometheus.MetricSources, prometheus.PrometheusSourceType)
+	server := NewServer(newFakeClient())
+	defer server.Close()
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	wsURL := func() string {
+		return "ws" + strings.TrimPrefix(httpServer.URL, "http") + webSocketAPIEndpoint + "?ticket=" + issueTicket(t, httpServer.URL, "user-token")
+	}
+	ws, err := websocket.Dial(wsURL(), "", httpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	if err = websocket.JSON.Send(ws, map[string]interface{}{"metricKeys": []string{"node_cpu"}}); err != nil {
+		t.Fatal(err)
+	}
+	var errorMessage Error
+	if err = websocket.JSON.Receive(ws, &errorMessage); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(errorMessage.Message, "cluster-wide") {
+		t.Errorf("expected cluster-wide metric to be rejected, got %q", errorMessage.Message)
+	}
+
+	ws, err = websocket.Dial(wsURL(), "", httpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	request := map[string]interface{}{"metricKeys": []string{"container_cpu"}, "namespace": "team-b", "interval": "5s"}
+	if err = websocket.JSON.Send(ws, request); err != nil {
+		t.Fatal(err)
+	}
+	var update metricUpdate
+	if err = websocket.JSON.Receive(ws, &update); err != nil {
+		t.Fatal(err)
+	}
+	if usage := update.Result["container_cpu"].(map[string]interface{})["usage"]; usage != "team-b" {
+		t.Errorf("expected namespace team-b, got %v", usage)
+	}
+}
+
+func TestHandleStreamReauthorize(t *testing.T) {
+	prometheus.MetricSources[prometheus.PrometheusSourceType] = stubSource{}
+	defer delete(prometheus.MetricSources, prometheus.PrometheusSourceType)
+	client := newFakeClient()
+	var expired int32
+	// 만료된 토큰은 TokenReview 에서 인증되지 않은 것으로 응답
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if atomic.LoadInt32(&expired) == 0 {
+			return false, nil, nil
+		}
+		return true, action.(k8stesting.CreateAction).GetObject(), nil
+	})
+	server := NewServer(client)
+	server.ReauthorizeInterval = 10 * time.Millisecond
+	defer server.Close()
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	response, err := http.Get(httpServer.URL + streamAPIEndpoint + "?metricKeys=container_cpu&ticket=" + issueTicket(t, httpServer.URL, "user-token"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	reader := bufio.NewReader(response.Body)
+	if event, _ := reader.ReadString('\n'); event != "event: metrics\n" {
+		t.Fatalf("unexpected event %q", event)
+	}
+
+	atomic.StoreInt32(&expired, 1)
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "event: error\ndata: ") || !strings.Contains(string(body), "unauthenticated") {
+		t.Errorf("expected the stream to end with an unauthenticated error, got %q", body)
+	}
+}
+
+func TestListenAndServeShutdown(t *testing.T) {
+	prometheus.MetricSources[prometheus.PrometheusSourceType] = stubSource{}
+	defer delete(prometheus.MetricSources, prometheus.PrometheusSourceType)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	server := NewServer(newFakeClient())
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe(ctx, address)
+	}()
+
+	request, _ := http.NewRequest(http.MethodGet, "http://"+address+streamAPIEndpoint+"?metricKeys=container_cpu", nil)
+	request.Header.Set("Authorization", "Bearer user-token")
+	var response *http.Response
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if response, err = http.DefaultClient.Do(request); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	// 종료하면 구독 연결을 끊고 조회 루프를 정리한다
+	cancel()
+	if _, err = io.ReadAll(response.Body); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err = <-errs:
+		if err != nil {
+			t.Errorf("expected graceful shutdown, got %s", err)
+		}
+	case <-time.After(shutdownTimeout):
+		t.Fatal("server did not shut down")
+	}
+	if topics, _ := server.hub.count(); topics != 0 {
+		t.Errorf("expected subscriptions to be stopped, got %d topics", topics)
+	}
+}
+
+func TestHandleStreamTicket(t *testing.T) {
+	prometheus.MetricSources[prometheus.PrometheusSourceType] = stubSource{}
+	defer delete(prometheus.MetricSources, prometheus.PrometheusSourceType)
+	server := NewServer(newFakeClient())
+	defer server.Close()
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	stream := func(query string) int {
+		response, err := http.Get(httpServer.URL + streamAPIEndpoint + "?metricKeys=container_cpu&" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = response.Body.Close()
+		return response.StatusCode
+	}
+
+	response, err := http.Post(httpServer.URL+ticketAPIEndpoint, "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected status %d for ticket request without token, got %d", http.StatusUnauthorized, response.StatusCode)
+	}
+	// 토큰은 쿼리 스트링으로 받지 않는다
+	if status := stream("token=user-token"); status != http.StatusUnauthorized {
+		t.Errorf("expected status %d for token query, got %d", http.StatusUnauthorized, status)
+	}
+
+	ticket := issueTicket(t, httpServer.URL, "user-token")
+	if status := stream("ticket=" + ticket); status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, status)
+	}
+	if status := stream("ticket=" + ticket); status != http.StatusUnauthorized {
+		t.Errorf("expected status %d for reused ticket, got %d", http.StatusUnauthorized, status)
+	}
+
+	server.tickets.ttl = 0
+	if status := stream("ticket=" + issueTicket(t, httpServer.URL, "user-token")); status != http.StatusUnauthorized {
+		t.Errorf("expected status %d for expired ticket, got %d", http.StatusUnauthorized, status)
+	}
+}
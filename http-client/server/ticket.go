@@ -0,0 +1,109 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-practice/http-client/kubernetes"
+)
+
+const (
+	ticketAPIEndpoint = "/api/v1/metrics/ticket"
+
+	defaultTicketTTL = 30 * time.Second // 구독 연결용 티켓의 유효 시간 기본값
+)
+
+// ticket 구독 연결 요청의 쿼리 스트링으로 전달하는 일회용 티켓이 대신하는 사용자 토큰
+type ticket struct {
+	token     string
+	expiresAt time.Time
+}
+
+// ticketStore 발급한 일회용 티켓 저장소
+// EventSource 와 브라우저 WebSocket 은 Authorization 헤더를 지정할 수 없으므로 토큰 대신 짧게 유효한 티켓을 URL 에 사용하여
+// 접근 로그나 Referer 헤더에 토큰이 남지 않도록 한다.
+type ticketStore struct {
+	mu      sync.Mutex
+	tickets map[string]ticket
+	ttl     time.Duration
+}
+
+func newTicketStore() *ticketStore {
+	return &ticketStore{tickets: make(map[string]ticket), ttl: defaultTicketTTL}
+}
+
+// issue 토큰을 대신할 티켓을 발급하고, 만료된 티켓을 정리한다
+func (store *ticketStore) issue(token string, now time.Time) (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate ticket, err=%s", err)
+	}
+	id := hex.EncodeToString(bytes)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	for key, issued := range store.tickets {
+		if !now.Before(issued.expiresAt) {
+			delete(store.tickets, key)
+		}
+	}
+	store.tickets[id] = ticket{token: token, expiresAt: now.Add(store.ttl)}
+	return id, nil
+}
+
+// redeem 티켓을 사용 처리하고 티켓이 대신하는 토큰을 반환한다(한 번만 사용 가능)
+func (store *ticketStore) redeem(id string, now time.Time) (string, bool) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	issued, ok := store.tickets[id]
+	if !ok {
+		return "", false
+	}
+	delete(store.tickets, id)
+	if !now.Before(issued.expiresAt) {
+		return "", false
+	}
+	return issued.token, true
+}
+
+// handleTicket Authorization 헤더의 토큰을 확인하고 구독 연결에 사용할 일회용 티켓을 발급
+/* POST /api/v1/metrics/ticket (Authorization: Bearer <token>)
+ * => {"ticket":"5f0c...","expiresIn":30}
+ * GET /api/v1/metrics/stream?metricKeys=container_cpu&ticket=5f0c...
+ */
+func (s *Server) handleTicket(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, &statusError{http.StatusMethodNotAllowed, "method not allowed"})
+		return
+	}
+	token := bearerToken(r)
+	if _, err := kubernetes.ResolveUser(r.Context(), s.KubeClient, token); err != nil {
+		writeError(w, err)
+		return
+	}
+	id, err := s.tickets.issue(token, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"ticket": id, "expiresIn": int(s.tickets.ttl.Seconds())})
+}
+
+// redeemTicket 구독 요청의 ticket 쿼리 파라미터를 사용자 토큰으로 바꾸어 Authorization 헤더에 설정한다
+// Authorization 헤더가 있으면 헤더를 사용하고, 티켓이 없거나 만료되었으면 에러를 반환한다.
+func (s *Server) redeemTicket(r *http.Request) error {
+	id := r.URL.Query().Get("ticket")
+	if id == "" || r.Header.Get("Authorization") != "" {
+		return nil
+	}
+	token, ok := s.tickets.redeem(id, time.Now())
+	if !ok {
+		return &statusError{http.StatusUnauthorized, "invalid or expired ticket"}
+	}
+	r.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/jmoiron/sqlx"
-	"github.com/thoas/go-funk"
+	"go-practice/common/collection"
 	"log"
 )
 
@@ -19,9 +19,9 @@ func TestDelete() {
 	_ = json.Unmarshal([]byte(bytes2), &b)
 	fmt.Printf("%+v\n", b)
 
-	widgetIds := funk.Map(b, func(widget Widget) string {
+	widgetIds := collection.Map(b, func(widget Widget) string {
 		return widget.I
-	}).([]string)
+	})
 
 	tx, _ := db.Client2.Beginx()
 	if len(widgetIds) == 0 {